@@ -0,0 +1,5341 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.0 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AccessLogEntryProtocol.
+const (
+	AccessLogEntryProtocolHttp AccessLogEntryProtocol = "http"
+	AccessLogEntryProtocolTcp  AccessLogEntryProtocol = "tcp"
+)
+
+// Defines values for AgentConfigExpectedState.
+const (
+	AgentConfigExpectedStateOffline AgentConfigExpectedState = "offline"
+	AgentConfigExpectedStateOnline  AgentConfigExpectedState = "online"
+)
+
+// Defines values for AgentConfigPatchExpectedState.
+const (
+	AgentConfigPatchExpectedStateOffline AgentConfigPatchExpectedState = "offline"
+	AgentConfigPatchExpectedStateOnline  AgentConfigPatchExpectedState = "online"
+)
+
+// Defines values for AgentStatusState.
+const (
+	AgentStatusStateConnecting AgentStatusState = "connecting"
+	AgentStatusStateOffline    AgentStatusState = "offline"
+	AgentStatusStateOnline     AgentStatusState = "online"
+)
+
+// Defines values for AuditChangeResource.
+const (
+	AuditChangeResourceAgent    AuditChangeResource = "agent"
+	AuditChangeResourceEndpoint AuditChangeResource = "endpoint"
+)
+
+// Defines values for BatchOperationOp.
+const (
+	Create BatchOperationOp = "create"
+	Delete BatchOperationOp = "delete"
+	Start  BatchOperationOp = "start"
+	Stop   BatchOperationOp = "stop"
+	Update BatchOperationOp = "update"
+)
+
+// Defines values for ContainerHealth.
+const (
+	ContainerHealthHealthy   ContainerHealth = "healthy"
+	ContainerHealthNone      ContainerHealth = "none"
+	ContainerHealthStarting  ContainerHealth = "starting"
+	ContainerHealthUnhealthy ContainerHealth = "unhealthy"
+)
+
+// Defines values for ContainerPortProtocol.
+const (
+	ContainerPortProtocolSctp ContainerPortProtocol = "sctp"
+	ContainerPortProtocolTcp  ContainerPortProtocol = "tcp"
+	ContainerPortProtocolUdp  ContainerPortProtocol = "udp"
+)
+
+// Defines values for DiagnosticCheckStatus.
+const (
+	DiagnosticCheckStatusFail DiagnosticCheckStatus = "fail"
+	DiagnosticCheckStatusPass DiagnosticCheckStatus = "pass"
+	DiagnosticCheckStatusWarn DiagnosticCheckStatus = "warn"
+)
+
+// Defines values for DiagnosticEndpointReportStatus.
+const (
+	DiagnosticEndpointReportStatusFail DiagnosticEndpointReportStatus = "fail"
+	DiagnosticEndpointReportStatusPass DiagnosticEndpointReportStatus = "pass"
+	DiagnosticEndpointReportStatusWarn DiagnosticEndpointReportStatus = "warn"
+)
+
+// Defines values for DiagnosticsReportStatus.
+const (
+	DiagnosticsReportStatusFail DiagnosticsReportStatus = "fail"
+	DiagnosticsReportStatusPass DiagnosticsReportStatus = "pass"
+	DiagnosticsReportStatusWarn DiagnosticsReportStatus = "warn"
+)
+
+// Defines values for DiscoveredPortSources.
+const (
+	Exposed   DiscoveredPortSources = "exposed"
+	Listening DiscoveredPortSources = "listening"
+	Published DiscoveredPortSources = "published"
+)
+
+// Defines values for EndpointRequestExpectedState.
+const (
+	EndpointRequestExpectedStateOffline EndpointRequestExpectedState = "offline"
+	EndpointRequestExpectedStateOnline  EndpointRequestExpectedState = "online"
+)
+
+// Defines values for EndpointRequestForwardedFor.
+const (
+	EndpointRequestForwardedForAppend  EndpointRequestForwardedFor = "append"
+	EndpointRequestForwardedForRemove  EndpointRequestForwardedFor = "remove"
+	EndpointRequestForwardedForReplace EndpointRequestForwardedFor = "replace"
+)
+
+// Defines values for EndpointRequestProxyProtocol.
+const (
+	EndpointRequestProxyProtocolV1 EndpointRequestProxyProtocol = "v1"
+	EndpointRequestProxyProtocolV2 EndpointRequestProxyProtocol = "v2"
+)
+
+// Defines values for EndpointRequestTlsTermination.
+const (
+	EndpointRequestTlsTerminationEdge        EndpointRequestTlsTermination = "edge"
+	EndpointRequestTlsTerminationPassthrough EndpointRequestTlsTermination = "passthrough"
+)
+
+// Defines values for EndpointRequestPatchExpectedState.
+const (
+	EndpointRequestPatchExpectedStateOffline EndpointRequestPatchExpectedState = "offline"
+	EndpointRequestPatchExpectedStateOnline  EndpointRequestPatchExpectedState = "online"
+)
+
+// Defines values for EndpointRequestPatchForwardedFor.
+const (
+	EndpointRequestPatchForwardedForAppend  EndpointRequestPatchForwardedFor = "append"
+	EndpointRequestPatchForwardedForRemove  EndpointRequestPatchForwardedFor = "remove"
+	EndpointRequestPatchForwardedForReplace EndpointRequestPatchForwardedFor = "replace"
+)
+
+// Defines values for EndpointRequestPatchProxyProtocol.
+const (
+	EndpointRequestPatchProxyProtocolV1 EndpointRequestPatchProxyProtocol = "v1"
+	EndpointRequestPatchProxyProtocolV2 EndpointRequestPatchProxyProtocol = "v2"
+)
+
+// Defines values for EndpointRequestPatchTlsTermination.
+const (
+	EndpointRequestPatchTlsTerminationEdge        EndpointRequestPatchTlsTermination = "edge"
+	EndpointRequestPatchTlsTerminationPassthrough EndpointRequestPatchTlsTermination = "passthrough"
+)
+
+// Defines values for EndpointResponseForwardedFor.
+const (
+	Append  EndpointResponseForwardedFor = "append"
+	Remove  EndpointResponseForwardedFor = "remove"
+	Replace EndpointResponseForwardedFor = "replace"
+)
+
+// Defines values for EndpointResponseProxyProtocol.
+const (
+	V1 EndpointResponseProxyProtocol = "v1"
+	V2 EndpointResponseProxyProtocol = "v2"
+)
+
+// Defines values for EndpointResponseTargetType.
+const (
+	EndpointResponseTargetTypeContainer EndpointResponseTargetType = "container"
+	EndpointResponseTargetTypeRouter    EndpointResponseTargetType = "router"
+	EndpointResponseTargetTypeUpstream  EndpointResponseTargetType = "upstream"
+)
+
+// Defines values for EndpointResponseTlsTermination.
+const (
+	Edge        EndpointResponseTlsTermination = "edge"
+	Passthrough EndpointResponseTlsTermination = "passthrough"
+)
+
+// Defines values for EndpointStatusState.
+const (
+	EndpointStatusStateFailed   EndpointStatusState = "failed"
+	EndpointStatusStateOffline  EndpointStatusState = "offline"
+	EndpointStatusStateOnline   EndpointStatusState = "online"
+	EndpointStatusStateStarting EndpointStatusState = "starting"
+)
+
+// Defines values for GitOpsDriftResource.
+const (
+	GitOpsDriftResourceAgent    GitOpsDriftResource = "agent"
+	GitOpsDriftResourceEndpoint GitOpsDriftResource = "endpoint"
+)
+
+// Defines values for HealthResponseStatus.
+const (
+	HealthResponseStatusFail HealthResponseStatus = "fail"
+	HealthResponseStatusPass HealthResponseStatus = "pass"
+)
+
+// Defines values for LogEntryLevel.
+const (
+	DEBUG LogEntryLevel = "DEBUG"
+	ERROR LogEntryLevel = "ERROR"
+	INFO  LogEntryLevel = "INFO"
+	WARN  LogEntryLevel = "WARN"
+)
+
+// Defines values for LoggingConfigLevel.
+const (
+	LoggingConfigLevelDebug LoggingConfigLevel = "debug"
+	LoggingConfigLevelError LoggingConfigLevel = "error"
+	LoggingConfigLevelInfo  LoggingConfigLevel = "info"
+	LoggingConfigLevelWarn  LoggingConfigLevel = "warn"
+)
+
+// Defines values for LoggingConfigSubsystems.
+const (
+	LoggingConfigSubsystemsDebug LoggingConfigSubsystems = "debug"
+	LoggingConfigSubsystemsError LoggingConfigSubsystems = "error"
+	LoggingConfigSubsystemsInfo  LoggingConfigSubsystems = "info"
+	LoggingConfigSubsystemsWarn  LoggingConfigSubsystems = "warn"
+)
+
+// Defines values for PortSuggestionEndpointType.
+const (
+	Http PortSuggestionEndpointType = "http"
+	Tcp  PortSuggestionEndpointType = "tcp"
+	Tls  PortSuggestionEndpointType = "tls"
+)
+
+// Defines values for RouteStatusState.
+const (
+	RouteStatusStateFailed   RouteStatusState = "failed"
+	RouteStatusStateOffline  RouteStatusState = "offline"
+	RouteStatusStateOnline   RouteStatusState = "online"
+	RouteStatusStateStarting RouteStatusState = "starting"
+)
+
+// Defines values for StatusErrorCode.
+const (
+	AgentDisconnected   StatusErrorCode = "agent_disconnected"
+	AgentNotConnected   StatusErrorCode = "agent_not_connected"
+	AuthFailed          StatusErrorCode = "auth_failed"
+	EndpointMissing     StatusErrorCode = "endpoint_missing"
+	NetworkDown         StatusErrorCode = "network_down"
+	OperationTimeout    StatusErrorCode = "operation_timeout"
+	PolicyInvalid       StatusErrorCode = "policy_invalid"
+	QuotaExceeded       StatusErrorCode = "quota_exceeded"
+	SecretUnresolved    StatusErrorCode = "secret_unresolved"
+	Unknown             StatusErrorCode = "unknown"
+	UpstreamUnreachable StatusErrorCode = "upstream_unreachable"
+	UrlInUse            StatusErrorCode = "url_in_use"
+)
+
+// Defines values for TopologyNodeType.
+const (
+	TopologyNodeTypeContainer TopologyNodeType = "container"
+	TopologyNodeTypeEndpoint  TopologyNodeType = "endpoint"
+	TopologyNodeTypeRoute     TopologyNodeType = "route"
+	TopologyNodeTypeRouter    TopologyNodeType = "router"
+	TopologyNodeTypeUpstream  TopologyNodeType = "upstream"
+)
+
+// Defines values for GetEndpointLogsParamsFormat.
+const (
+	Json   GetEndpointLogsParamsFormat = "json"
+	Ndjson GetEndpointLogsParamsFormat = "ndjson"
+)
+
+// Defines values for GetLogsParamsLevel.
+const (
+	Debug GetLogsParamsLevel = "debug"
+	Error GetLogsParamsLevel = "error"
+	Info  GetLogsParamsLevel = "info"
+	Warn  GetLogsParamsLevel = "warn"
+)
+
+// AccessLogEntry defines model for AccessLogEntry.
+type AccessLogEntry struct {
+	// BytesIn Body bytes of the request, or bytes sent by the client
+	BytesIn int64 `json:"bytesIn"`
+
+	// BytesOut Body bytes of the response, or bytes sent by the upstream
+	BytesOut   int64  `json:"bytesOut"`
+	DurationMs int64  `json:"durationMs"`
+	Endpoint   string `json:"endpoint"`
+
+	// Error Why the upstream could not be reached
+	Error  *string `json:"error,omitempty"`
+	Method *string `json:"method,omitempty"`
+
+	// Path Request path, without the query
+	Path *string `json:"path,omitempty"`
+
+	// Protocol http entries are requests; tcp entries are connections
+	Protocol AccessLogEntryProtocol `json:"protocol"`
+
+	// RemoteAddr The client's address, when known
+	RemoteAddr *string `json:"remoteAddr,omitempty"`
+	Status     *int    `json:"status,omitempty"`
+
+	// Time When the request or connection started
+	Time time.Time `json:"time"`
+}
+
+// AccessLogEntryProtocol http entries are requests; tcp entries are connections
+type AccessLogEntryProtocol string
+
+// AgentConfig defines model for AgentConfig.
+type AgentConfig struct {
+	AuthToken     string                   `json:"authToken"`
+	ConnectURL    *string                  `json:"connectURL,omitempty"`
+	ExpectedState AgentConfigExpectedState `json:"expectedState"`
+
+	// ResourceVersion If set, must match the current version
+	ResourceVersion *int64 `json:"resourceVersion,omitempty"`
+}
+
+// AgentConfigExpectedState defines model for AgentConfig.ExpectedState.
+type AgentConfigExpectedState string
+
+// AgentConfigPatch JSON merge patch of AgentConfig. Absent fields are left unchanged and null clears a field.
+type AgentConfigPatch struct {
+	AuthToken       *string                        `json:"authToken,omitempty"`
+	ConnectURL      *string                        `json:"connectURL,omitempty"`
+	ExpectedState   *AgentConfigPatchExpectedState `json:"expectedState,omitempty"`
+	ResourceVersion *int64                         `json:"resourceVersion,omitempty"`
+}
+
+// AgentConfigPatchExpectedState defines model for AgentConfigPatch.ExpectedState.
+type AgentConfigPatchExpectedState string
+
+// AgentResponse defines model for AgentResponse.
+type AgentResponse struct {
+	AuthToken       string      `json:"authToken"`
+	ConnectURL      *string     `json:"connectURL,omitempty"`
+	ExpectedState   string      `json:"expectedState"`
+	ResourceVersion int64       `json:"resourceVersion"`
+	Status          AgentStatus `json:"status"`
+}
+
+// AgentStatus defines model for AgentStatus.
+type AgentStatus struct {
+	ConnectedAt time.Time `json:"connectedAt"`
+
+	// Error Classified agent or endpoint error
+	Error     *StatusError `json:"error,omitempty"`
+	LastError *string      `json:"lastError,omitempty"`
+
+	// Latency Heartbeat latency in nanoseconds
+	Latency *int64           `json:"latency,omitempty"`
+	State   AgentStatusState `json:"state"`
+}
+
+// AgentStatusState defines model for AgentStatus.State.
+type AgentStatusState string
+
+// AuditChange defines model for AuditChange.
+type AuditChange struct {
+	// After Value after the call; secrets are redacted
+	After interface{} `json:"after,omitempty"`
+
+	// Before Value before the call; secrets are redacted
+	Before interface{} `json:"before,omitempty"`
+	Field  string      `json:"field"`
+
+	// Id Endpoint ID; empty for the agent
+	Id       *string             `json:"id,omitempty"`
+	Resource AuditChangeResource `json:"resource"`
+}
+
+// AuditChangeResource defines model for AuditChange.Resource.
+type AuditChangeResource string
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Changes   *[]AuditChange `json:"changes,omitempty"`
+	Endpoints *[]string      `json:"endpoints,omitempty"`
+
+	// Identity Token name or client header of the caller
+	Identity string    `json:"identity"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Status   int       `json:"status"`
+	Time     time.Time `json:"time"`
+}
+
+// BatchOperation defines model for BatchOperation.
+type BatchOperation struct {
+	Endpoint        *EndpointRequest `json:"endpoint,omitempty"`
+	Id              *string          `json:"id,omitempty"`
+	Op              BatchOperationOp `json:"op"`
+	ResourceVersion *int64           `json:"resourceVersion,omitempty"`
+
+	// Selector Matches existing endpoints, including those created earlier in the batch. Every field set must match, and at least one must be set. An operation whose selector matches no endpoint fails with status 404.
+	Selector *EndpointSelector `json:"selector,omitempty"`
+}
+
+// BatchOperationOp defines model for BatchOperation.Op.
+type BatchOperationOp string
+
+// BatchRequest defines model for BatchRequest.
+type BatchRequest struct {
+	Atomic     *bool            `json:"atomic,omitempty"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Endpoints *[]EndpointResponse `json:"endpoints,omitempty"`
+	Error     *string             `json:"error,omitempty"`
+	Ids       []string            `json:"ids"`
+	Op        string              `json:"op"`
+	Status    int                 `json:"status"`
+}
+
+// Container defines model for Container.
+type Container struct {
+	ComposeProject *string `json:"composeProject,omitempty"`
+	ComposeService *string `json:"composeService,omitempty"`
+
+	// Endpoints IDs of the endpoints configured for the container
+	Endpoints []string        `json:"endpoints"`
+	Health    ContainerHealth `json:"health"`
+	Id        string          `json:"id"`
+	Image     string          `json:"image"`
+	Name      string          `json:"name"`
+	Networks  []string        `json:"networks"`
+	Ports     []ContainerPort `json:"ports"`
+
+	// State e.g. running, exited
+	State string `json:"state"`
+
+	// Status e.g. Up 5 minutes (healthy)
+	Status string `json:"status"`
+}
+
+// ContainerHealth defines model for Container.Health.
+type ContainerHealth string
+
+// ContainerDiscovery defines model for ContainerDiscovery.
+type ContainerDiscovery struct {
+	ContainerId string           `json:"containerId"`
+	Ports       []DiscoveredPort `json:"ports"`
+	Warnings    *[]string        `json:"warnings,omitempty"`
+}
+
+// ContainerPort defines model for ContainerPort.
+type ContainerPort struct {
+	// Detected Cached protocol detection result
+	Detected *PortDetection `json:"detected,omitempty"`
+
+	// EndpointId Endpoint forwarding to this port
+	EndpointId  *string               `json:"endpointId,omitempty"`
+	PrivatePort int32                 `json:"privatePort"`
+	Protocol    ContainerPortProtocol `json:"protocol"`
+
+	// PublicPort Port published on the host; absent if the port is only exposed
+	PublicPort *int32 `json:"publicPort,omitempty"`
+}
+
+// ContainerPortProtocol defines model for ContainerPort.Protocol.
+type ContainerPortProtocol string
+
+// DetectProtocolRequest defines model for DetectProtocolRequest.
+type DetectProtocolRequest struct {
+	ContainerId string `json:"container_id"`
+	Port        string `json:"port"`
+}
+
+// DetectProtocolResponse defines model for DetectProtocolResponse.
+type DetectProtocolResponse struct {
+	DetectedAt time.Time `json:"detectedAt"`
+
+	// Error Why the port could not be reached
+	Error *string `json:"error,omitempty"`
+	Http  bool    `json:"http"`
+
+	// HttpPath The first probe path that got a response other than a 404; absent if every path got a 404
+	HttpPath *string `json:"httpPath,omitempty"`
+	Https    bool    `json:"https"`
+	Tcp      bool    `json:"tcp"`
+	Tls      bool    `json:"tls"`
+}
+
+// DiagnosticCheck defines model for DiagnosticCheck.
+type DiagnosticCheck struct {
+	Message string                `json:"message"`
+	Name    string                `json:"name"`
+	Status  DiagnosticCheckStatus `json:"status"`
+}
+
+// DiagnosticCheckStatus defines model for DiagnosticCheck.Status.
+type DiagnosticCheckStatus string
+
+// DiagnosticEndpointReport defines model for DiagnosticEndpointReport.
+type DiagnosticEndpointReport struct {
+	Checks []DiagnosticCheck              `json:"checks"`
+	Id     string                         `json:"id"`
+	Status DiagnosticEndpointReportStatus `json:"status"`
+}
+
+// DiagnosticEndpointReportStatus defines model for DiagnosticEndpointReport.Status.
+type DiagnosticEndpointReportStatus string
+
+// DiagnosticsReport defines model for DiagnosticsReport.
+type DiagnosticsReport struct {
+	Checks    []DiagnosticCheck          `json:"checks"`
+	Endpoints []DiagnosticEndpointReport `json:"endpoints"`
+	Status    DiagnosticsReportStatus    `json:"status"`
+}
+
+// DiagnosticsReportStatus defines model for DiagnosticsReport.Status.
+type DiagnosticsReportStatus string
+
+// DiscoverRequest defines model for DiscoverRequest.
+type DiscoverRequest struct {
+	// ScanListening Also list the ports listening inside the container by reading /proc/net/tcp with docker exec
+	ScanListening *bool `json:"scanListening,omitempty"`
+}
+
+// DiscoveredPort defines model for DiscoveredPort.
+type DiscoveredPort struct {
+	// Address Address the port was probed at
+	Address string `json:"address"`
+
+	// Detected Cached protocol detection result
+	Detected *PortDetection `json:"detected,omitempty"`
+
+	// Port Port inside the container
+	Port int32 `json:"port"`
+
+	// PublicPort Port published on the host; absent if unpublished
+	PublicPort *int32 `json:"publicPort,omitempty"`
+
+	// Score Rank of the port; higher is better
+	Score      int                     `json:"score"`
+	Sources    []DiscoveredPortSources `json:"sources"`
+	Suggestion *PortSuggestion         `json:"suggestion,omitempty"`
+}
+
+// DiscoveredPortSources defines model for DiscoveredPort.Sources.
+type DiscoveredPortSources string
+
+// EndpointRequest defines model for EndpointRequest.
+type EndpointRequest struct {
+	// Binding public, internal or kubernetes. Endpoints with a .internal url are internal; internal endpoints require such a url.
+	Binding *string `json:"binding,omitempty"`
+
+	// ContainerId Empty for endpoints targeting an upstream URL and for routers
+	ContainerId   string                       `json:"containerId"`
+	Description   *string                      `json:"description,omitempty"`
+	ExpectedState EndpointRequestExpectedState `json:"expectedState"`
+
+	// ForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+	ForwardedFor *EndpointRequestForwardedFor `json:"forwardedFor,omitempty"`
+
+	// HostHeader Host header sent to the upstream: rewrite for the upstream's address, or a host such as example.com:8080. The client's by default. http and https endpoints only.
+	HostHeader *string `json:"hostHeader,omitempty"`
+
+	// Metadata May contain ${secret:name} and ${env:NAME} references, resolved when the endpoint starts. NAME must start with NGROK_EXT_SECRET_.
+	Metadata       *string `json:"metadata,omitempty"`
+	PoolingEnabled *bool   `json:"poolingEnabled,omitempty"`
+
+	// ProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+	ProxyProtocol *EndpointRequestProxyProtocol `json:"proxyProtocol,omitempty"`
+
+	// RealIP Set X-Real-IP to the client's address. http and https endpoints only.
+	RealIP *bool `json:"realIP,omitempty"`
+
+	// ResourceVersion If set, must match the current version
+	ResourceVersion *int64 `json:"resourceVersion,omitempty"`
+
+	// Router Makes the endpoint a router, identified as router:host by its url. Rules are evaluated in order after the traffic policy's own on_http_request rules; requests matching no rule get a 404. Cannot be combined with containerId, targetPort, upstream or routes.
+	Router *[]RouterRule `json:"router,omitempty"`
+
+	// Routes Evaluated in order after the traffic policy's own on_http_request rules; requests matching no route go to targetPort. Requires an http or https endpoint attached to a container.
+	Routes *[]EndpointRoute `json:"routes,omitempty"`
+
+	// TargetPort Empty for endpoints targeting an upstream URL
+	TargetPort string `json:"targetPort"`
+
+	// TlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+	TlsTermination *EndpointRequestTlsTermination `json:"tlsTermination,omitempty"`
+
+	// TrafficPolicy May contain ${secret:name} and ${env:NAME} references, resolved when the endpoint starts. NAME must start with NGROK_EXT_SECRET_.
+	TrafficPolicy *string `json:"trafficPolicy,omitempty"`
+
+	// Upstream Upstream URL (http, https, tcp or tls) for endpoints not attached to a container, e.g. http://host.docker.internal:3000. Mutually exclusive with containerId and targetPort.
+	Upstream *string `json:"upstream,omitempty"`
+
+	// Url Endpoint URL. tcp URLs are tcp:// for an address assigned on start, a reserved address such as tcp://1.tcp.ngrok.io:12345, or an internal address.
+	Url *string `json:"url,omitempty"`
+}
+
+// EndpointRequestExpectedState defines model for EndpointRequest.ExpectedState.
+type EndpointRequestExpectedState string
+
+// EndpointRequestForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+type EndpointRequestForwardedFor string
+
+// EndpointRequestProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+type EndpointRequestProxyProtocol string
+
+// EndpointRequestTlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+type EndpointRequestTlsTermination string
+
+// EndpointRequestPatch JSON merge patch of EndpointRequest. Absent fields are left unchanged and null clears a field. containerId and targetPort cannot be changed.
+type EndpointRequestPatch struct {
+	// Binding public, internal or kubernetes. Endpoints with a .internal url are internal; internal endpoints require such a url.
+	Binding       *string                            `json:"binding,omitempty"`
+	Description   *string                            `json:"description,omitempty"`
+	ExpectedState *EndpointRequestPatchExpectedState `json:"expectedState,omitempty"`
+
+	// ForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+	ForwardedFor *EndpointRequestPatchForwardedFor `json:"forwardedFor,omitempty"`
+
+	// HostHeader Host header sent to the upstream: rewrite for the upstream's address, or a host such as example.com:8080. The client's by default. http and https endpoints only.
+	HostHeader     *string `json:"hostHeader,omitempty"`
+	Metadata       *string `json:"metadata,omitempty"`
+	PoolingEnabled *bool   `json:"poolingEnabled,omitempty"`
+
+	// ProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+	ProxyProtocol *EndpointRequestPatchProxyProtocol `json:"proxyProtocol,omitempty"`
+
+	// RealIP Set X-Real-IP to the client's address. http and https endpoints only.
+	RealIP          *bool  `json:"realIP,omitempty"`
+	ResourceVersion *int64 `json:"resourceVersion,omitempty"`
+
+	// Router Makes the endpoint a router, identified as router:host by its url. Rules are evaluated in order after the traffic policy's own on_http_request rules; requests matching no rule get a 404. Cannot be combined with containerId, targetPort, upstream or routes.
+	Router *[]RouterRule `json:"router,omitempty"`
+
+	// Routes Evaluated in order after the traffic policy's own on_http_request rules; requests matching no route go to targetPort. Requires an http or https endpoint attached to a container.
+	Routes *[]EndpointRoute `json:"routes,omitempty"`
+
+	// TlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+	TlsTermination *EndpointRequestPatchTlsTermination `json:"tlsTermination,omitempty"`
+	TrafficPolicy  *string                             `json:"trafficPolicy,omitempty"`
+	Url            *string                             `json:"url,omitempty"`
+}
+
+// EndpointRequestPatchExpectedState defines model for EndpointRequestPatch.ExpectedState.
+type EndpointRequestPatchExpectedState string
+
+// EndpointRequestPatchForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+type EndpointRequestPatchForwardedFor string
+
+// EndpointRequestPatchProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+type EndpointRequestPatchProxyProtocol string
+
+// EndpointRequestPatchTlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+type EndpointRequestPatchTlsTermination string
+
+// EndpointResponse defines model for EndpointResponse.
+type EndpointResponse struct {
+	// Binding public, internal or kubernetes. Endpoints with a .internal url are internal; internal endpoints require such a url.
+	Binding *string `json:"binding,omitempty"`
+
+	// ContainerId Empty for endpoints targeting an upstream URL
+	ContainerId   string  `json:"containerId"`
+	Description   *string `json:"description,omitempty"`
+	ExpectedState string  `json:"expectedState"`
+
+	// ForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+	ForwardedFor *EndpointResponseForwardedFor `json:"forwardedFor,omitempty"`
+
+	// HostHeader Host header sent to the upstream: rewrite for the upstream's address, or a host such as example.com:8080. The client's by default. http and https endpoints only.
+	HostHeader *string `json:"hostHeader,omitempty"`
+
+	// Id containerId:targetPort, or upstream:host:port for endpoints targeting an upstream URL
+	Id          string  `json:"id"`
+	LastStarted *string `json:"lastStarted,omitempty"`
+
+	// Metadata May contain ${secret:name} and ${env:NAME} references, resolved when the endpoint starts. NAME must start with NGROK_EXT_SECRET_.
+	Metadata       *string `json:"metadata,omitempty"`
+	PoolingEnabled bool    `json:"poolingEnabled"`
+
+	// ProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+	ProxyProtocol *EndpointResponseProxyProtocol `json:"proxyProtocol,omitempty"`
+
+	// RealIP Set X-Real-IP to the client's address. http and https endpoints only.
+	RealIP          *bool `json:"realIP,omitempty"`
+	ResourceVersion int64 `json:"resourceVersion"`
+
+	// Router Makes the endpoint a router, identified as router:host by its url. Rules are evaluated in order after the traffic policy's own on_http_request rules; requests matching no rule get a 404. Cannot be combined with containerId, targetPort, upstream or routes.
+	Router *[]RouterRule `json:"router,omitempty"`
+
+	// Routes Evaluated in order after the traffic policy's own on_http_request rules; requests matching no route go to targetPort. Requires an http or https endpoint attached to a container.
+	Routes *[]EndpointRoute `json:"routes,omitempty"`
+	Status EndpointStatus   `json:"status"`
+
+	// TargetPort Empty for endpoints targeting an upstream URL
+	TargetPort string                     `json:"targetPort"`
+	TargetType EndpointResponseTargetType `json:"targetType"`
+
+	// TlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+	TlsTermination *EndpointResponseTlsTermination `json:"tlsTermination,omitempty"`
+
+	// TrafficPolicy May contain ${secret:name} and ${env:NAME} references, resolved when the endpoint starts. NAME must start with NGROK_EXT_SECRET_.
+	TrafficPolicy *string `json:"trafficPolicy,omitempty"`
+
+	// Upstream Upstream URL (http, https, tcp or tls) for endpoints not attached to a container, e.g. http://host.docker.internal:3000. Mutually exclusive with containerId and targetPort.
+	Upstream *string `json:"upstream,omitempty"`
+
+	// Url May contain ${secret:name} and ${env:NAME} references, resolved when the endpoint starts. NAME must start with NGROK_EXT_SECRET_.
+	Url *string `json:"url,omitempty"`
+}
+
+// EndpointResponseForwardedFor X-Forwarded-For sent to the upstream: append (default) adds the client's address to the header sent by the client, replace discards the client's header, remove sends none. http and https endpoints only.
+type EndpointResponseForwardedFor string
+
+// EndpointResponseProxyProtocol PROXY protocol header sent to the upstream with each connection. tcp and tls endpoints only.
+type EndpointResponseProxyProtocol string
+
+// EndpointResponseTargetType defines model for EndpointResponse.TargetType.
+type EndpointResponseTargetType string
+
+// EndpointResponseTlsTermination Where a tls endpoint terminates TLS: passthrough (default) forwards the encrypted stream to the upstream, edge decrypts it in the ngrok cloud. tls endpoints only.
+type EndpointResponseTlsTermination string
+
+// EndpointRoute Sends the requests of an HTTP endpoint whose path starts with path to another port of its container
+type EndpointRoute struct {
+	// Path Path prefix, e.g. /api/ or /api/*
+	Path string `json:"path"`
+
+	// TargetPort Port of the endpoint's container serving the route
+	TargetPort string `json:"targetPort"`
+}
+
+// EndpointSelector Matches existing endpoints, including those created earlier in the batch. Every field set must match, and at least one must be set. An operation whose selector matches no endpoint fails with status 404.
+type EndpointSelector struct {
+	ComposeProject *string `json:"composeProject,omitempty"`
+	ComposeService *string `json:"composeService,omitempty"`
+	ContainerId    *string `json:"containerId,omitempty"`
+}
+
+// EndpointStatus defines model for EndpointStatus.
+type EndpointStatus struct {
+	// Error Classified agent or endpoint error
+	Error     *StatusError `json:"error,omitempty"`
+	LastError *string      `json:"lastError,omitempty"`
+
+	// Routes One per configured route, in order
+	Routes *[]RouteStatus      `json:"routes,omitempty"`
+	State  EndpointStatusState `json:"state"`
+	Url    *string             `json:"url,omitempty"`
+}
+
+// EndpointStatusState defines model for EndpointStatus.State.
+type EndpointStatusState string
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// GetAuditResponse defines model for GetAuditResponse.
+type GetAuditResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// GetEndpointLogsResponse defines model for GetEndpointLogsResponse.
+type GetEndpointLogsResponse struct {
+	Entries []AccessLogEntry `json:"entries"`
+}
+
+// GetEndpointsResponse defines model for GetEndpointsResponse.
+type GetEndpointsResponse struct {
+	Endpoints []EndpointResponse `json:"endpoints"`
+}
+
+// GetGitOpsResponse defines model for GetGitOpsResponse.
+type GetGitOpsResponse struct {
+	Drift   []GitOpsDrift `json:"drift"`
+	Enabled bool          `json:"enabled"`
+	Source  *GitOpsStatus `json:"source,omitempty"`
+}
+
+// GetLogsResponse defines model for GetLogsResponse.
+type GetLogsResponse struct {
+	Entries []LogEntry `json:"entries"`
+}
+
+// GitOpsDrift defines model for GitOpsDrift.
+type GitOpsDrift struct {
+	Actual string `json:"actual"`
+
+	// Desired expectedState from the file, or 'absent' for endpoints not declared in it
+	Desired string  `json:"desired"`
+	Id      *string `json:"id,omitempty"`
+
+	// Reason Last runtime error
+	Reason   *string             `json:"reason,omitempty"`
+	Resource GitOpsDriftResource `json:"resource"`
+}
+
+// GitOpsDriftResource defines model for GitOpsDrift.Resource.
+type GitOpsDriftResource string
+
+// GitOpsStatus defines model for GitOpsStatus.
+type GitOpsStatus struct {
+	// Error Why the current file contents are rejected; the last valid configuration stays in effect
+	Error *string `json:"error,omitempty"`
+
+	// LoadedAt When the last valid contents were read
+	LoadedAt time.Time `json:"loadedAt"`
+	Path     string    `json:"path"`
+}
+
+// HealthResponse defines model for HealthResponse.
+type HealthResponse struct {
+	Checks []DiagnosticCheck `json:"checks"`
+
+	// Status fail if any check failed
+	Status HealthResponseStatus `json:"status"`
+}
+
+// HealthResponseStatus fail if any check failed
+type HealthResponseStatus string
+
+// ListContainersResponse defines model for ListContainersResponse.
+type ListContainersResponse struct {
+	Containers []Container `json:"containers"`
+}
+
+// ListSecretsResponse defines model for ListSecretsResponse.
+type ListSecretsResponse struct {
+	Secrets []SecretInfo `json:"secrets"`
+}
+
+// LogEntry defines model for LogEntry.
+type LogEntry struct {
+	// Attrs The entry's other attributes
+	Attrs *map[string]interface{} `json:"attrs,omitempty"`
+	Level LogEntryLevel           `json:"level"`
+	Msg   string                  `json:"msg"`
+
+	// Subsystem The subsystem that wrote the entry, if any
+	Subsystem *string   `json:"subsystem,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// LogEntryLevel defines model for LogEntry.Level.
+type LogEntryLevel string
+
+// LoggingConfig defines model for LoggingConfig.
+type LoggingConfig struct {
+	// Level The default level
+	Level LoggingConfigLevel `json:"level"`
+
+	// Subsystems Levels overriding the default, by subsystem: manager, store, detectproto or http
+	Subsystems *map[string]LoggingConfigSubsystems `json:"subsystems,omitempty"`
+}
+
+// LoggingConfigLevel The default level
+type LoggingConfigLevel string
+
+// LoggingConfigSubsystems defines model for LoggingConfig.Subsystems.
+type LoggingConfigSubsystems string
+
+// PortDetection Cached protocol detection result
+type PortDetection struct {
+	DetectedAt time.Time `json:"detectedAt"`
+
+	// Error Why the port could not be reached
+	Error *string `json:"error,omitempty"`
+	Http  bool    `json:"http"`
+	Https bool    `json:"https"`
+	Tcp   bool    `json:"tcp"`
+	Tls   bool    `json:"tls"`
+}
+
+// PortSuggestion defines model for PortSuggestion.
+type PortSuggestion struct {
+	EndpointType PortSuggestionEndpointType `json:"endpointType"`
+	Reason       string                     `json:"reason"`
+
+	// TargetPort targetPort to create the endpoint with; absent if the port is not published
+	TargetPort *string `json:"targetPort,omitempty"`
+
+	// UpstreamScheme Scheme used to reach the container
+	UpstreamScheme string `json:"upstreamScheme"`
+
+	// UrlScheme Scheme of the endpoint URL
+	UrlScheme string `json:"urlScheme"`
+}
+
+// PortSuggestionEndpointType defines model for PortSuggestion.EndpointType.
+type PortSuggestionEndpointType string
+
+// PutSecretRequest defines model for PutSecretRequest.
+type PutSecretRequest struct {
+	Value string `json:"value"`
+}
+
+// RouteStatus Runtime state of one route of an endpoint
+type RouteStatus struct {
+	// Error Classified agent or endpoint error
+	Error      *StatusError     `json:"error,omitempty"`
+	LastError  *string          `json:"lastError,omitempty"`
+	Path       string           `json:"path"`
+	State      RouteStatusState `json:"state"`
+	TargetPort string           `json:"targetPort"`
+
+	// Url Internal endpoint serving the route
+	Url string `json:"url"`
+}
+
+// RouteStatusState defines model for RouteStatus.State.
+type RouteStatusState string
+
+// RouterRule Forwards the requests of a router matching host and path to an internal endpoint
+type RouterRule struct {
+	// Endpoint ID of the internal endpoint requests are forwarded to
+	Endpoint string `json:"endpoint"`
+
+	// Host Host the request must be for, e.g. api.example.com; empty matches every host
+	Host *string `json:"host,omitempty"`
+
+	// Path Path prefix, e.g. /api/ or /api/*; empty matches every path
+	Path *string `json:"path,omitempty"`
+}
+
+// SecretInfo A stored secret; the value is never returned
+type SecretInfo struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// StatusError Classified agent or endpoint error
+type StatusError struct {
+	Code StatusErrorCode `json:"code"`
+
+	// Message Original error message
+	Message string `json:"message"`
+
+	// NgrokCode ngrok cloud error code, e.g. ERR_NGROK_105
+	NgrokCode *string `json:"ngrokCode,omitempty"`
+
+	// Remediation Hint on how to fix the error
+	Remediation *string `json:"remediation,omitempty"`
+}
+
+// StatusErrorCode defines model for StatusError.Code.
+type StatusErrorCode string
+
+// Topology Graph of the endpoints and what they forward to
+type Topology struct {
+	Edges []TopologyEdge `json:"edges"`
+	Nodes []TopologyNode `json:"nodes"`
+}
+
+// TopologyEdge defines model for TopologyEdge.
+type TopologyEdge struct {
+	// Broken The node forwarded to does not exist
+	Broken *bool  `json:"broken,omitempty"`
+	From   string `json:"from"`
+
+	// Host Host matched by a router rule
+	Host *string `json:"host,omitempty"`
+
+	// Path Path matched by a router rule or route
+	Path *string `json:"path,omitempty"`
+
+	// Port Container port, for edges to containers
+	Port *string `json:"port,omitempty"`
+	To   string  `json:"to"`
+}
+
+// TopologyNode defines model for TopologyNode.
+type TopologyNode struct {
+	// Binding Endpoints and routers only
+	Binding *string `json:"binding,omitempty"`
+
+	// Id Endpoint ID, route URL, container:<id> or upstream URL
+	Id    string `json:"id"`
+	Label string `json:"label"`
+
+	// State Endpoints, routers and routes only
+	State *string          `json:"state,omitempty"`
+	Type  TopologyNodeType `json:"type"`
+	Url   *string          `json:"url,omitempty"`
+}
+
+// TopologyNodeType defines model for TopologyNode.Type.
+type TopologyNodeType string
+
+// PatchAgentParams defines parameters for PatchAgent.
+type PatchAgentParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// PutAgentParams defines parameters for PutAgent.
+type PutAgentParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetAuditParams defines parameters for GetAudit.
+type GetAuditParams struct {
+	// Since Only entries at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only entries at or before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// Identity Only entries made by this identity
+	Identity *string `form:"identity,omitempty" json:"identity,omitempty"`
+
+	// Endpoint Only entries that changed this endpoint
+	Endpoint *string `form:"endpoint,omitempty" json:"endpoint,omitempty"`
+
+	// Limit Keep only the most recent entries
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListContainersParams defines parameters for ListContainers.
+type ListContainersParams struct {
+	// All Include stopped containers
+	All *bool `form:"all,omitempty" json:"all,omitempty"`
+}
+
+// PostEndpointsParams defines parameters for PostEndpoints.
+type PostEndpointsParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// DeleteEndpointByIDParams defines parameters for DeleteEndpointByID.
+type DeleteEndpointByIDParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// PatchEndpointByIDParams defines parameters for PatchEndpointByID.
+type PatchEndpointByIDParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// PutEndpointByIDParams defines parameters for PutEndpointByID.
+type PutEndpointByIDParams struct {
+	// IfMatch Only apply the change if the resource's ETag matches
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetEndpointLogsParams defines parameters for GetEndpointLogs.
+type GetEndpointLogsParams struct {
+	// Since Only entries at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only entries at or before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// RemoteAddr Only entries from this client address
+	RemoteAddr *string `form:"remoteAddr,omitempty" json:"remoteAddr,omitempty"`
+
+	// Method Only requests with this method
+	Method *string `form:"method,omitempty" json:"method,omitempty"`
+
+	// Path Only requests whose path starts with this prefix
+	Path *string `form:"path,omitempty" json:"path,omitempty"`
+
+	// Status Only entries with this status code, e.g. 404, or class, e.g. 5xx
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit Keep only the most recent entries
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Format json (the default) or ndjson to export newline delimited JSON
+	Format *GetEndpointLogsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Follow Stream the entries as newline delimited JSON, then every matching entry recorded until the client disconnects
+	Follow *bool `form:"follow,omitempty" json:"follow,omitempty"`
+}
+
+// GetEndpointLogsParamsFormat defines parameters for GetEndpointLogs.
+type GetEndpointLogsParamsFormat string
+
+// GetLogsParams defines parameters for GetLogs.
+type GetLogsParams struct {
+	// Since Only entries at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Level Only entries at or above this level
+	Level *GetLogsParamsLevel `form:"level,omitempty" json:"level,omitempty"`
+
+	// Subsystem Only entries written by this subsystem
+	Subsystem *string `form:"subsystem,omitempty" json:"subsystem,omitempty"`
+
+	// Limit Keep only the most recent entries
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Follow Stream the entries as newline delimited JSON, then every matching entry written until the client disconnects
+	Follow *bool `form:"follow,omitempty" json:"follow,omitempty"`
+}
+
+// GetLogsParamsLevel defines parameters for GetLogs.
+type GetLogsParamsLevel string
+
+// PatchAgentApplicationMergePatchPlusJSONRequestBody defines body for PatchAgent for application/merge-patch+json ContentType.
+type PatchAgentApplicationMergePatchPlusJSONRequestBody = AgentConfigPatch
+
+// PutAgentJSONRequestBody defines body for PutAgent for application/json ContentType.
+type PutAgentJSONRequestBody = AgentConfig
+
+// DiscoverContainerPortsJSONRequestBody defines body for DiscoverContainerPorts for application/json ContentType.
+type DiscoverContainerPortsJSONRequestBody = DiscoverRequest
+
+// DetectProtocolJSONRequestBody defines body for DetectProtocol for application/json ContentType.
+type DetectProtocolJSONRequestBody = DetectProtocolRequest
+
+// PostEndpointsJSONRequestBody defines body for PostEndpoints for application/json ContentType.
+type PostEndpointsJSONRequestBody = EndpointRequest
+
+// PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody defines body for PatchEndpointByID for application/merge-patch+json ContentType.
+type PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody = EndpointRequestPatch
+
+// PutEndpointByIDJSONRequestBody defines body for PutEndpointByID for application/json ContentType.
+type PutEndpointByIDJSONRequestBody = EndpointRequest
+
+// PostEndpointsBatchJSONRequestBody defines body for PostEndpointsBatch for application/json ContentType.
+type PostEndpointsBatchJSONRequestBody = BatchRequest
+
+// PutLoggingJSONRequestBody defines body for PutLogging for application/json ContentType.
+type PutLoggingJSONRequestBody = LoggingConfig
+
+// PutSecretJSONRequestBody defines body for PutSecret for application/json ContentType.
+type PutSecretJSONRequestBody = PutSecretRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// GetAgent request
+	GetAgent(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PatchAgentWithBody request with any body
+	PatchAgentWithBody(ctx context.Context, params *PatchAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PatchAgentWithApplicationMergePatchPlusJSONBody(ctx context.Context, params *PatchAgentParams, body PatchAgentApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutAgentWithBody request with any body
+	PutAgentWithBody(ctx context.Context, params *PutAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutAgent(ctx context.Context, params *PutAgentParams, body PutAgentJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAudit request
+	GetAudit(ctx context.Context, params *GetAuditParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListContainers request
+	ListContainers(ctx context.Context, params *ListContainersParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DiscoverContainerPortsWithBody request with any body
+	DiscoverContainerPortsWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	DiscoverContainerPorts(ctx context.Context, id string, body DiscoverContainerPortsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DetectProtocolWithBody request with any body
+	DetectProtocolWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	DetectProtocol(ctx context.Context, body DetectProtocolJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetDiagnostics request
+	GetDiagnostics(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEndpoints request
+	GetEndpoints(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostEndpointsWithBody request with any body
+	PostEndpointsWithBody(ctx context.Context, params *PostEndpointsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostEndpoints(ctx context.Context, params *PostEndpointsParams, body PostEndpointsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteEndpointByID request
+	DeleteEndpointByID(ctx context.Context, id string, params *DeleteEndpointByIDParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEndpointByID request
+	GetEndpointByID(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PatchEndpointByIDWithBody request with any body
+	PatchEndpointByIDWithBody(ctx context.Context, id string, params *PatchEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PatchEndpointByIDWithApplicationMergePatchPlusJSONBody(ctx context.Context, id string, params *PatchEndpointByIDParams, body PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutEndpointByIDWithBody request with any body
+	PutEndpointByIDWithBody(ctx context.Context, id string, params *PutEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutEndpointByID(ctx context.Context, id string, params *PutEndpointByIDParams, body PutEndpointByIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEndpointLogs request
+	GetEndpointLogs(ctx context.Context, id string, params *GetEndpointLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostEndpointsBatchWithBody request with any body
+	PostEndpointsBatchWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostEndpointsBatch(ctx context.Context, body PostEndpointsBatchJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGitOps request
+	GetGitOps(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetHealthz request
+	GetHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetLogging request
+	GetLogging(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutLoggingWithBody request with any body
+	PutLoggingWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutLogging(ctx context.Context, body PutLoggingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetLogs request
+	GetLogs(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPI request
+	GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetReadyz request
+	GetReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListSecrets request
+	ListSecrets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteSecret request
+	DeleteSecret(ctx context.Context, name string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutSecretWithBody request with any body
+	PutSecretWithBody(ctx context.Context, name string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutSecret(ctx context.Context, name string, body PutSecretJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetTopology request
+	GetTopology(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetAgent(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAgentRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchAgentWithBody(ctx context.Context, params *PatchAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchAgentRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchAgentWithApplicationMergePatchPlusJSONBody(ctx context.Context, params *PatchAgentParams, body PatchAgentApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchAgentRequestWithApplicationMergePatchPlusJSONBody(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutAgentWithBody(ctx context.Context, params *PutAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutAgentRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutAgent(ctx context.Context, params *PutAgentParams, body PutAgentJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutAgentRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAudit(ctx context.Context, params *GetAuditParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAuditRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListContainers(ctx context.Context, params *ListContainersParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListContainersRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DiscoverContainerPortsWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDiscoverContainerPortsRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DiscoverContainerPorts(ctx context.Context, id string, body DiscoverContainerPortsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDiscoverContainerPortsRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DetectProtocolWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDetectProtocolRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DetectProtocol(ctx context.Context, body DetectProtocolJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDetectProtocolRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetDiagnostics(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetDiagnosticsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEndpoints(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEndpointsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostEndpointsWithBody(ctx context.Context, params *PostEndpointsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostEndpointsRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostEndpoints(ctx context.Context, params *PostEndpointsParams, body PostEndpointsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostEndpointsRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteEndpointByID(ctx context.Context, id string, params *DeleteEndpointByIDParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteEndpointByIDRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEndpointByID(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEndpointByIDRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchEndpointByIDWithBody(ctx context.Context, id string, params *PatchEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchEndpointByIDRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchEndpointByIDWithApplicationMergePatchPlusJSONBody(ctx context.Context, id string, params *PatchEndpointByIDParams, body PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchEndpointByIDRequestWithApplicationMergePatchPlusJSONBody(c.Server, id, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutEndpointByIDWithBody(ctx context.Context, id string, params *PutEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutEndpointByIDRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutEndpointByID(ctx context.Context, id string, params *PutEndpointByIDParams, body PutEndpointByIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutEndpointByIDRequest(c.Server, id, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEndpointLogs(ctx context.Context, id string, params *GetEndpointLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEndpointLogsRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostEndpointsBatchWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostEndpointsBatchRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostEndpointsBatch(ctx context.Context, body PostEndpointsBatchJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostEndpointsBatchRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGitOps(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGitOpsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetHealthzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetLogging(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLoggingRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutLoggingWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutLoggingRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutLogging(ctx context.Context, body PutLoggingJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutLoggingRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetLogs(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLogsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPIRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetReadyzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListSecrets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListSecretsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteSecret(ctx context.Context, name string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteSecretRequest(c.Server, name)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutSecretWithBody(ctx context.Context, name string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutSecretRequestWithBody(c.Server, name, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutSecret(ctx context.Context, name string, body PutSecretJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutSecretRequest(c.Server, name, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetTopology(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTopologyRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetAgentRequest generates requests for GetAgent
+func NewGetAgentRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/agent")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPatchAgentRequestWithApplicationMergePatchPlusJSONBody calls the generic PatchAgent builder with application/merge-patch+json body
+func NewPatchAgentRequestWithApplicationMergePatchPlusJSONBody(server string, params *PatchAgentParams, body PatchAgentApplicationMergePatchPlusJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPatchAgentRequestWithBody(server, params, "application/merge-patch+json", bodyReader)
+}
+
+// NewPatchAgentRequestWithBody generates requests for PatchAgent with any type of body
+func NewPatchAgentRequestWithBody(server string, params *PatchAgentParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/agent")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewPutAgentRequest calls the generic PutAgent builder with application/json body
+func NewPutAgentRequest(server string, params *PutAgentParams, body PutAgentJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutAgentRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewPutAgentRequestWithBody generates requests for PutAgent with any type of body
+func NewPutAgentRequestWithBody(server string, params *PutAgentParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/agent")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetAuditRequest generates requests for GetAudit
+func NewGetAuditRequest(server string, params *GetAuditParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/audit")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Until != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "until", runtime.ParamLocationQuery, *params.Until); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Identity != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "identity", runtime.ParamLocationQuery, *params.Identity); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Endpoint != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "endpoint", runtime.ParamLocationQuery, *params.Endpoint); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListContainersRequest generates requests for ListContainers
+func NewListContainersRequest(server string, params *ListContainersParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/containers")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.All != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "all", runtime.ParamLocationQuery, *params.All); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDiscoverContainerPortsRequest calls the generic DiscoverContainerPorts builder with application/json body
+func NewDiscoverContainerPortsRequest(server string, id string, body DiscoverContainerPortsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewDiscoverContainerPortsRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewDiscoverContainerPortsRequestWithBody generates requests for DiscoverContainerPorts with any type of body
+func NewDiscoverContainerPortsRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/containers/%s/discover", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDetectProtocolRequest calls the generic DetectProtocol builder with application/json body
+func NewDetectProtocolRequest(server string, body DetectProtocolJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewDetectProtocolRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewDetectProtocolRequestWithBody generates requests for DetectProtocol with any type of body
+func NewDetectProtocolRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/detect_protocol")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetDiagnosticsRequest generates requests for GetDiagnostics
+func NewGetDiagnosticsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/diagnostics")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetEndpointsRequest generates requests for GetEndpoints
+func NewGetEndpointsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostEndpointsRequest calls the generic PostEndpoints builder with application/json body
+func NewPostEndpointsRequest(server string, params *PostEndpointsParams, body PostEndpointsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostEndpointsRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewPostEndpointsRequestWithBody generates requests for PostEndpoints with any type of body
+func NewPostEndpointsRequestWithBody(server string, params *PostEndpointsParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewDeleteEndpointByIDRequest generates requests for DeleteEndpointByID
+func NewDeleteEndpointByIDRequest(server string, id string, params *DeleteEndpointByIDParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetEndpointByIDRequest generates requests for GetEndpointByID
+func NewGetEndpointByIDRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPatchEndpointByIDRequestWithApplicationMergePatchPlusJSONBody calls the generic PatchEndpointByID builder with application/merge-patch+json body
+func NewPatchEndpointByIDRequestWithApplicationMergePatchPlusJSONBody(server string, id string, params *PatchEndpointByIDParams, body PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPatchEndpointByIDRequestWithBody(server, id, params, "application/merge-patch+json", bodyReader)
+}
+
+// NewPatchEndpointByIDRequestWithBody generates requests for PatchEndpointByID with any type of body
+func NewPatchEndpointByIDRequestWithBody(server string, id string, params *PatchEndpointByIDParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewPutEndpointByIDRequest calls the generic PutEndpointByID builder with application/json body
+func NewPutEndpointByIDRequest(server string, id string, params *PutEndpointByIDParams, body PutEndpointByIDJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutEndpointByIDRequestWithBody(server, id, params, "application/json", bodyReader)
+}
+
+// NewPutEndpointByIDRequestWithBody generates requests for PutEndpointByID with any type of body
+func NewPutEndpointByIDRequestWithBody(server string, id string, params *PutEndpointByIDParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IfMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, *params.IfMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetEndpointLogsRequest generates requests for GetEndpointLogs
+func NewGetEndpointLogsRequest(server string, id string, params *GetEndpointLogsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints/%s/logs", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Until != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "until", runtime.ParamLocationQuery, *params.Until); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.RemoteAddr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "remoteAddr", runtime.ParamLocationQuery, *params.RemoteAddr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Method != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "method", runtime.ParamLocationQuery, *params.Method); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Path != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "path", runtime.ParamLocationQuery, *params.Path); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Follow != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "follow", runtime.ParamLocationQuery, *params.Follow); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostEndpointsBatchRequest calls the generic PostEndpointsBatch builder with application/json body
+func NewPostEndpointsBatchRequest(server string, body PostEndpointsBatchJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostEndpointsBatchRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostEndpointsBatchRequestWithBody generates requests for PostEndpointsBatch with any type of body
+func NewPostEndpointsBatchRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/endpoints:batch")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetGitOpsRequest generates requests for GetGitOps
+func NewGetGitOpsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/gitops")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetHealthzRequest generates requests for GetHealthz
+func NewGetHealthzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/healthz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetLoggingRequest generates requests for GetLogging
+func NewGetLoggingRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/logging")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPutLoggingRequest calls the generic PutLogging builder with application/json body
+func NewPutLoggingRequest(server string, body PutLoggingJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutLoggingRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPutLoggingRequestWithBody generates requests for PutLogging with any type of body
+func NewPutLoggingRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/logging")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetLogsRequest generates requests for GetLogs
+func NewGetLogsRequest(server string, params *GetLogsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/logs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Level != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "level", runtime.ParamLocationQuery, *params.Level); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Subsystem != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "subsystem", runtime.ParamLocationQuery, *params.Subsystem); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Follow != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "follow", runtime.ParamLocationQuery, *params.Follow); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPIRequest generates requests for GetOpenAPI
+func NewGetOpenAPIRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/openapi.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetReadyzRequest generates requests for GetReadyz
+func NewGetReadyzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/readyz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListSecretsRequest generates requests for ListSecrets
+func NewListSecretsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/secrets")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeleteSecretRequest generates requests for DeleteSecret
+func NewDeleteSecretRequest(server string, name string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/secrets/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPutSecretRequest calls the generic PutSecret builder with application/json body
+func NewPutSecretRequest(server string, name string, body PutSecretJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutSecretRequestWithBody(server, name, "application/json", bodyReader)
+}
+
+// NewPutSecretRequestWithBody generates requests for PutSecret with any type of body
+func NewPutSecretRequestWithBody(server string, name string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/secrets/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetTopologyRequest generates requests for GetTopology
+func NewGetTopologyRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/topology")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetAgentWithResponse request
+	GetAgentWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAgentResult, error)
+
+	// PatchAgentWithBodyWithResponse request with any body
+	PatchAgentWithBodyWithResponse(ctx context.Context, params *PatchAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchAgentResult, error)
+
+	PatchAgentWithApplicationMergePatchPlusJSONBodyWithResponse(ctx context.Context, params *PatchAgentParams, body PatchAgentApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchAgentResult, error)
+
+	// PutAgentWithBodyWithResponse request with any body
+	PutAgentWithBodyWithResponse(ctx context.Context, params *PutAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAgentResult, error)
+
+	PutAgentWithResponse(ctx context.Context, params *PutAgentParams, body PutAgentJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAgentResult, error)
+
+	// GetAuditWithResponse request
+	GetAuditWithResponse(ctx context.Context, params *GetAuditParams, reqEditors ...RequestEditorFn) (*GetAuditResult, error)
+
+	// ListContainersWithResponse request
+	ListContainersWithResponse(ctx context.Context, params *ListContainersParams, reqEditors ...RequestEditorFn) (*ListContainersResult, error)
+
+	// DiscoverContainerPortsWithBodyWithResponse request with any body
+	DiscoverContainerPortsWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DiscoverContainerPortsResult, error)
+
+	DiscoverContainerPortsWithResponse(ctx context.Context, id string, body DiscoverContainerPortsJSONRequestBody, reqEditors ...RequestEditorFn) (*DiscoverContainerPortsResult, error)
+
+	// DetectProtocolWithBodyWithResponse request with any body
+	DetectProtocolWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DetectProtocolResult, error)
+
+	DetectProtocolWithResponse(ctx context.Context, body DetectProtocolJSONRequestBody, reqEditors ...RequestEditorFn) (*DetectProtocolResult, error)
+
+	// GetDiagnosticsWithResponse request
+	GetDiagnosticsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetDiagnosticsResult, error)
+
+	// GetEndpointsWithResponse request
+	GetEndpointsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEndpointsResult, error)
+
+	// PostEndpointsWithBodyWithResponse request with any body
+	PostEndpointsWithBodyWithResponse(ctx context.Context, params *PostEndpointsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostEndpointsResult, error)
+
+	PostEndpointsWithResponse(ctx context.Context, params *PostEndpointsParams, body PostEndpointsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostEndpointsResult, error)
+
+	// DeleteEndpointByIDWithResponse request
+	DeleteEndpointByIDWithResponse(ctx context.Context, id string, params *DeleteEndpointByIDParams, reqEditors ...RequestEditorFn) (*DeleteEndpointByIDResult, error)
+
+	// GetEndpointByIDWithResponse request
+	GetEndpointByIDWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetEndpointByIDResult, error)
+
+	// PatchEndpointByIDWithBodyWithResponse request with any body
+	PatchEndpointByIDWithBodyWithResponse(ctx context.Context, id string, params *PatchEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchEndpointByIDResult, error)
+
+	PatchEndpointByIDWithApplicationMergePatchPlusJSONBodyWithResponse(ctx context.Context, id string, params *PatchEndpointByIDParams, body PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchEndpointByIDResult, error)
+
+	// PutEndpointByIDWithBodyWithResponse request with any body
+	PutEndpointByIDWithBodyWithResponse(ctx context.Context, id string, params *PutEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutEndpointByIDResult, error)
+
+	PutEndpointByIDWithResponse(ctx context.Context, id string, params *PutEndpointByIDParams, body PutEndpointByIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PutEndpointByIDResult, error)
+
+	// GetEndpointLogsWithResponse request
+	GetEndpointLogsWithResponse(ctx context.Context, id string, params *GetEndpointLogsParams, reqEditors ...RequestEditorFn) (*GetEndpointLogsResult, error)
+
+	// PostEndpointsBatchWithBodyWithResponse request with any body
+	PostEndpointsBatchWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostEndpointsBatchResult, error)
+
+	PostEndpointsBatchWithResponse(ctx context.Context, body PostEndpointsBatchJSONRequestBody, reqEditors ...RequestEditorFn) (*PostEndpointsBatchResult, error)
+
+	// GetGitOpsWithResponse request
+	GetGitOpsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetGitOpsResult, error)
+
+	// GetHealthzWithResponse request
+	GetHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetHealthzResult, error)
+
+	// GetLoggingWithResponse request
+	GetLoggingWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetLoggingResult, error)
+
+	// PutLoggingWithBodyWithResponse request with any body
+	PutLoggingWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutLoggingResult, error)
+
+	PutLoggingWithResponse(ctx context.Context, body PutLoggingJSONRequestBody, reqEditors ...RequestEditorFn) (*PutLoggingResult, error)
+
+	// GetLogsWithResponse request
+	GetLogsWithResponse(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*GetLogsResult, error)
+
+	// GetOpenAPIWithResponse request
+	GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResult, error)
+
+	// GetReadyzWithResponse request
+	GetReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetReadyzResult, error)
+
+	// ListSecretsWithResponse request
+	ListSecretsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSecretsResult, error)
+
+	// DeleteSecretWithResponse request
+	DeleteSecretWithResponse(ctx context.Context, name string, reqEditors ...RequestEditorFn) (*DeleteSecretResult, error)
+
+	// PutSecretWithBodyWithResponse request with any body
+	PutSecretWithBodyWithResponse(ctx context.Context, name string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutSecretResult, error)
+
+	PutSecretWithResponse(ctx context.Context, name string, body PutSecretJSONRequestBody, reqEditors ...RequestEditorFn) (*PutSecretResult, error)
+
+	// GetTopologyWithResponse request
+	GetTopologyWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTopologyResult, error)
+}
+
+type GetAgentResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *AgentResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAgentResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAgentResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PatchAgentResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *AgentResponse
+	JSON400      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PatchAgentResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PatchAgentResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutAgentResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *AgentResponse
+	JSON400      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PutAgentResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutAgentResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAuditResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetAuditResponse
+	JSON400      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAuditResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAuditResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListContainersResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ListContainersResponse
+	JSON400      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r ListContainersResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListContainersResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DiscoverContainerPortsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ContainerDiscovery
+	JSON400      *ErrorResponse
+	JSON404      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DiscoverContainerPortsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DiscoverContainerPortsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DetectProtocolResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *DetectProtocolResponse
+	JSON400      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DetectProtocolResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DetectProtocolResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetDiagnosticsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *DiagnosticsReport
+}
+
+// Status returns HTTPResponse.Status
+func (r GetDiagnosticsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetDiagnosticsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEndpointsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetEndpointsResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEndpointsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEndpointsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostEndpointsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *EndpointResponse
+	JSON400      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostEndpointsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostEndpointsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteEndpointByIDResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteEndpointByIDResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteEndpointByIDResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEndpointByIDResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EndpointResponse
+	JSON404      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEndpointByIDResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEndpointByIDResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PatchEndpointByIDResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EndpointResponse
+	JSON400      *ErrorResponse
+	JSON404      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PatchEndpointByIDResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PatchEndpointByIDResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutEndpointByIDResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *EndpointResponse
+	JSON400      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON412      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PutEndpointByIDResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutEndpointByIDResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEndpointLogsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetEndpointLogsResponse
+	JSON400      *ErrorResponse
+	JSON404      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEndpointLogsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEndpointLogsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostEndpointsBatchResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *BatchResponse
+	JSON400      *ErrorResponse
+	JSON409      *ErrorResponse
+	JSON422      *BatchResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostEndpointsBatchResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostEndpointsBatchResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGitOpsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetGitOpsResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGitOpsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGitOpsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetHealthzResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HealthResponse
+	JSON503      *HealthResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetHealthzResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetHealthzResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetLoggingResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *LoggingConfig
+	JSON404      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLoggingResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLoggingResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutLoggingResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *LoggingConfig
+	JSON400      *ErrorResponse
+	JSON404      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PutLoggingResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutLoggingResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetLogsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetLogsResponse
+	JSON400      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLogsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLogsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetOpenAPIResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPIResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPIResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetReadyzResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HealthResponse
+	JSON503      *HealthResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetReadyzResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetReadyzResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListSecretsResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ListSecretsResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r ListSecretsResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListSecretsResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteSecretResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteSecretResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteSecretResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutSecretResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SecretInfo
+	JSON201      *SecretInfo
+	JSON400      *ErrorResponse
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PutSecretResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutSecretResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTopologyResult struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Topology
+	JSON500      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTopologyResult) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTopologyResult) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetAgentWithResponse request returning *GetAgentResult
+func (c *ClientWithResponses) GetAgentWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAgentResult, error) {
+	rsp, err := c.GetAgent(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAgentResult(rsp)
+}
+
+// PatchAgentWithBodyWithResponse request with arbitrary body returning *PatchAgentResult
+func (c *ClientWithResponses) PatchAgentWithBodyWithResponse(ctx context.Context, params *PatchAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchAgentResult, error) {
+	rsp, err := c.PatchAgentWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchAgentResult(rsp)
+}
+
+func (c *ClientWithResponses) PatchAgentWithApplicationMergePatchPlusJSONBodyWithResponse(ctx context.Context, params *PatchAgentParams, body PatchAgentApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchAgentResult, error) {
+	rsp, err := c.PatchAgentWithApplicationMergePatchPlusJSONBody(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchAgentResult(rsp)
+}
+
+// PutAgentWithBodyWithResponse request with arbitrary body returning *PutAgentResult
+func (c *ClientWithResponses) PutAgentWithBodyWithResponse(ctx context.Context, params *PutAgentParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAgentResult, error) {
+	rsp, err := c.PutAgentWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAgentResult(rsp)
+}
+
+func (c *ClientWithResponses) PutAgentWithResponse(ctx context.Context, params *PutAgentParams, body PutAgentJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAgentResult, error) {
+	rsp, err := c.PutAgent(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAgentResult(rsp)
+}
+
+// GetAuditWithResponse request returning *GetAuditResult
+func (c *ClientWithResponses) GetAuditWithResponse(ctx context.Context, params *GetAuditParams, reqEditors ...RequestEditorFn) (*GetAuditResult, error) {
+	rsp, err := c.GetAudit(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAuditResult(rsp)
+}
+
+// ListContainersWithResponse request returning *ListContainersResult
+func (c *ClientWithResponses) ListContainersWithResponse(ctx context.Context, params *ListContainersParams, reqEditors ...RequestEditorFn) (*ListContainersResult, error) {
+	rsp, err := c.ListContainers(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListContainersResult(rsp)
+}
+
+// DiscoverContainerPortsWithBodyWithResponse request with arbitrary body returning *DiscoverContainerPortsResult
+func (c *ClientWithResponses) DiscoverContainerPortsWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DiscoverContainerPortsResult, error) {
+	rsp, err := c.DiscoverContainerPortsWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiscoverContainerPortsResult(rsp)
+}
+
+func (c *ClientWithResponses) DiscoverContainerPortsWithResponse(ctx context.Context, id string, body DiscoverContainerPortsJSONRequestBody, reqEditors ...RequestEditorFn) (*DiscoverContainerPortsResult, error) {
+	rsp, err := c.DiscoverContainerPorts(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiscoverContainerPortsResult(rsp)
+}
+
+// DetectProtocolWithBodyWithResponse request with arbitrary body returning *DetectProtocolResult
+func (c *ClientWithResponses) DetectProtocolWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*DetectProtocolResult, error) {
+	rsp, err := c.DetectProtocolWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDetectProtocolResult(rsp)
+}
+
+func (c *ClientWithResponses) DetectProtocolWithResponse(ctx context.Context, body DetectProtocolJSONRequestBody, reqEditors ...RequestEditorFn) (*DetectProtocolResult, error) {
+	rsp, err := c.DetectProtocol(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDetectProtocolResult(rsp)
+}
+
+// GetDiagnosticsWithResponse request returning *GetDiagnosticsResult
+func (c *ClientWithResponses) GetDiagnosticsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetDiagnosticsResult, error) {
+	rsp, err := c.GetDiagnostics(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetDiagnosticsResult(rsp)
+}
+
+// GetEndpointsWithResponse request returning *GetEndpointsResult
+func (c *ClientWithResponses) GetEndpointsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEndpointsResult, error) {
+	rsp, err := c.GetEndpoints(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEndpointsResult(rsp)
+}
+
+// PostEndpointsWithBodyWithResponse request with arbitrary body returning *PostEndpointsResult
+func (c *ClientWithResponses) PostEndpointsWithBodyWithResponse(ctx context.Context, params *PostEndpointsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostEndpointsResult, error) {
+	rsp, err := c.PostEndpointsWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostEndpointsResult(rsp)
+}
+
+func (c *ClientWithResponses) PostEndpointsWithResponse(ctx context.Context, params *PostEndpointsParams, body PostEndpointsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostEndpointsResult, error) {
+	rsp, err := c.PostEndpoints(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostEndpointsResult(rsp)
+}
+
+// DeleteEndpointByIDWithResponse request returning *DeleteEndpointByIDResult
+func (c *ClientWithResponses) DeleteEndpointByIDWithResponse(ctx context.Context, id string, params *DeleteEndpointByIDParams, reqEditors ...RequestEditorFn) (*DeleteEndpointByIDResult, error) {
+	rsp, err := c.DeleteEndpointByID(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteEndpointByIDResult(rsp)
+}
+
+// GetEndpointByIDWithResponse request returning *GetEndpointByIDResult
+func (c *ClientWithResponses) GetEndpointByIDWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetEndpointByIDResult, error) {
+	rsp, err := c.GetEndpointByID(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEndpointByIDResult(rsp)
+}
+
+// PatchEndpointByIDWithBodyWithResponse request with arbitrary body returning *PatchEndpointByIDResult
+func (c *ClientWithResponses) PatchEndpointByIDWithBodyWithResponse(ctx context.Context, id string, params *PatchEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchEndpointByIDResult, error) {
+	rsp, err := c.PatchEndpointByIDWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchEndpointByIDResult(rsp)
+}
+
+func (c *ClientWithResponses) PatchEndpointByIDWithApplicationMergePatchPlusJSONBodyWithResponse(ctx context.Context, id string, params *PatchEndpointByIDParams, body PatchEndpointByIDApplicationMergePatchPlusJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchEndpointByIDResult, error) {
+	rsp, err := c.PatchEndpointByIDWithApplicationMergePatchPlusJSONBody(ctx, id, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchEndpointByIDResult(rsp)
+}
+
+// PutEndpointByIDWithBodyWithResponse request with arbitrary body returning *PutEndpointByIDResult
+func (c *ClientWithResponses) PutEndpointByIDWithBodyWithResponse(ctx context.Context, id string, params *PutEndpointByIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutEndpointByIDResult, error) {
+	rsp, err := c.PutEndpointByIDWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutEndpointByIDResult(rsp)
+}
+
+func (c *ClientWithResponses) PutEndpointByIDWithResponse(ctx context.Context, id string, params *PutEndpointByIDParams, body PutEndpointByIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PutEndpointByIDResult, error) {
+	rsp, err := c.PutEndpointByID(ctx, id, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutEndpointByIDResult(rsp)
+}
+
+// GetEndpointLogsWithResponse request returning *GetEndpointLogsResult
+func (c *ClientWithResponses) GetEndpointLogsWithResponse(ctx context.Context, id string, params *GetEndpointLogsParams, reqEditors ...RequestEditorFn) (*GetEndpointLogsResult, error) {
+	rsp, err := c.GetEndpointLogs(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEndpointLogsResult(rsp)
+}
+
+// PostEndpointsBatchWithBodyWithResponse request with arbitrary body returning *PostEndpointsBatchResult
+func (c *ClientWithResponses) PostEndpointsBatchWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostEndpointsBatchResult, error) {
+	rsp, err := c.PostEndpointsBatchWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostEndpointsBatchResult(rsp)
+}
+
+func (c *ClientWithResponses) PostEndpointsBatchWithResponse(ctx context.Context, body PostEndpointsBatchJSONRequestBody, reqEditors ...RequestEditorFn) (*PostEndpointsBatchResult, error) {
+	rsp, err := c.PostEndpointsBatch(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostEndpointsBatchResult(rsp)
+}
+
+// GetGitOpsWithResponse request returning *GetGitOpsResult
+func (c *ClientWithResponses) GetGitOpsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetGitOpsResult, error) {
+	rsp, err := c.GetGitOps(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGitOpsResult(rsp)
+}
+
+// GetHealthzWithResponse request returning *GetHealthzResult
+func (c *ClientWithResponses) GetHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetHealthzResult, error) {
+	rsp, err := c.GetHealthz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetHealthzResult(rsp)
+}
+
+// GetLoggingWithResponse request returning *GetLoggingResult
+func (c *ClientWithResponses) GetLoggingWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetLoggingResult, error) {
+	rsp, err := c.GetLogging(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLoggingResult(rsp)
+}
+
+// PutLoggingWithBodyWithResponse request with arbitrary body returning *PutLoggingResult
+func (c *ClientWithResponses) PutLoggingWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutLoggingResult, error) {
+	rsp, err := c.PutLoggingWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutLoggingResult(rsp)
+}
+
+func (c *ClientWithResponses) PutLoggingWithResponse(ctx context.Context, body PutLoggingJSONRequestBody, reqEditors ...RequestEditorFn) (*PutLoggingResult, error) {
+	rsp, err := c.PutLogging(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutLoggingResult(rsp)
+}
+
+// GetLogsWithResponse request returning *GetLogsResult
+func (c *ClientWithResponses) GetLogsWithResponse(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*GetLogsResult, error) {
+	rsp, err := c.GetLogs(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLogsResult(rsp)
+}
+
+// GetOpenAPIWithResponse request returning *GetOpenAPIResult
+func (c *ClientWithResponses) GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResult, error) {
+	rsp, err := c.GetOpenAPI(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPIResult(rsp)
+}
+
+// GetReadyzWithResponse request returning *GetReadyzResult
+func (c *ClientWithResponses) GetReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetReadyzResult, error) {
+	rsp, err := c.GetReadyz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetReadyzResult(rsp)
+}
+
+// ListSecretsWithResponse request returning *ListSecretsResult
+func (c *ClientWithResponses) ListSecretsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSecretsResult, error) {
+	rsp, err := c.ListSecrets(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListSecretsResult(rsp)
+}
+
+// DeleteSecretWithResponse request returning *DeleteSecretResult
+func (c *ClientWithResponses) DeleteSecretWithResponse(ctx context.Context, name string, reqEditors ...RequestEditorFn) (*DeleteSecretResult, error) {
+	rsp, err := c.DeleteSecret(ctx, name, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteSecretResult(rsp)
+}
+
+// PutSecretWithBodyWithResponse request with arbitrary body returning *PutSecretResult
+func (c *ClientWithResponses) PutSecretWithBodyWithResponse(ctx context.Context, name string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutSecretResult, error) {
+	rsp, err := c.PutSecretWithBody(ctx, name, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutSecretResult(rsp)
+}
+
+func (c *ClientWithResponses) PutSecretWithResponse(ctx context.Context, name string, body PutSecretJSONRequestBody, reqEditors ...RequestEditorFn) (*PutSecretResult, error) {
+	rsp, err := c.PutSecret(ctx, name, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutSecretResult(rsp)
+}
+
+// GetTopologyWithResponse request returning *GetTopologyResult
+func (c *ClientWithResponses) GetTopologyWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTopologyResult, error) {
+	rsp, err := c.GetTopology(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTopologyResult(rsp)
+}
+
+// ParseGetAgentResult parses an HTTP response from a GetAgentWithResponse call
+func ParseGetAgentResult(rsp *http.Response) (*GetAgentResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAgentResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest AgentResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePatchAgentResult parses an HTTP response from a PatchAgentWithResponse call
+func ParsePatchAgentResult(rsp *http.Response) (*PatchAgentResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PatchAgentResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest AgentResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutAgentResult parses an HTTP response from a PutAgentWithResponse call
+func ParsePutAgentResult(rsp *http.Response) (*PutAgentResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutAgentResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest AgentResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAuditResult parses an HTTP response from a GetAuditWithResponse call
+func ParseGetAuditResult(rsp *http.Response) (*GetAuditResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAuditResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetAuditResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListContainersResult parses an HTTP response from a ListContainersWithResponse call
+func ParseListContainersResult(rsp *http.Response) (*ListContainersResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListContainersResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ListContainersResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDiscoverContainerPortsResult parses an HTTP response from a DiscoverContainerPortsWithResponse call
+func ParseDiscoverContainerPortsResult(rsp *http.Response) (*DiscoverContainerPortsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DiscoverContainerPortsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ContainerDiscovery
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDetectProtocolResult parses an HTTP response from a DetectProtocolWithResponse call
+func ParseDetectProtocolResult(rsp *http.Response) (*DetectProtocolResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DetectProtocolResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest DetectProtocolResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetDiagnosticsResult parses an HTTP response from a GetDiagnosticsWithResponse call
+func ParseGetDiagnosticsResult(rsp *http.Response) (*GetDiagnosticsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetDiagnosticsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest DiagnosticsReport
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetEndpointsResult parses an HTTP response from a GetEndpointsWithResponse call
+func ParseGetEndpointsResult(rsp *http.Response) (*GetEndpointsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEndpointsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetEndpointsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostEndpointsResult parses an HTTP response from a PostEndpointsWithResponse call
+func ParsePostEndpointsResult(rsp *http.Response) (*PostEndpointsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostEndpointsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest EndpointResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteEndpointByIDResult parses an HTTP response from a DeleteEndpointByIDWithResponse call
+func ParseDeleteEndpointByIDResult(rsp *http.Response) (*DeleteEndpointByIDResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteEndpointByIDResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetEndpointByIDResult parses an HTTP response from a GetEndpointByIDWithResponse call
+func ParseGetEndpointByIDResult(rsp *http.Response) (*GetEndpointByIDResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEndpointByIDResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EndpointResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePatchEndpointByIDResult parses an HTTP response from a PatchEndpointByIDWithResponse call
+func ParsePatchEndpointByIDResult(rsp *http.Response) (*PatchEndpointByIDResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PatchEndpointByIDResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EndpointResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutEndpointByIDResult parses an HTTP response from a PutEndpointByIDWithResponse call
+func ParsePutEndpointByIDResult(rsp *http.Response) (*PutEndpointByIDResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutEndpointByIDResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest EndpointResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetEndpointLogsResult parses an HTTP response from a GetEndpointLogsWithResponse call
+func ParseGetEndpointLogsResult(rsp *http.Response) (*GetEndpointLogsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEndpointLogsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetEndpointLogsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (application/x-ndjson) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParsePostEndpointsBatchResult parses an HTTP response from a PostEndpointsBatchWithResponse call
+func ParsePostEndpointsBatchResult(rsp *http.Response) (*PostEndpointsBatchResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostEndpointsBatchResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest BatchResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest BatchResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetGitOpsResult parses an HTTP response from a GetGitOpsWithResponse call
+func ParseGetGitOpsResult(rsp *http.Response) (*GetGitOpsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGitOpsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetGitOpsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetHealthzResult parses an HTTP response from a GetHealthzWithResponse call
+func ParseGetHealthzResult(rsp *http.Response) (*GetHealthzResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetHealthzResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest HealthResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest HealthResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetLoggingResult parses an HTTP response from a GetLoggingWithResponse call
+func ParseGetLoggingResult(rsp *http.Response) (*GetLoggingResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLoggingResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest LoggingConfig
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutLoggingResult parses an HTTP response from a PutLoggingWithResponse call
+func ParsePutLoggingResult(rsp *http.Response) (*PutLoggingResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutLoggingResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest LoggingConfig
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetLogsResult parses an HTTP response from a GetLogsWithResponse call
+func ParseGetLogsResult(rsp *http.Response) (*GetLogsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLogsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetLogsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (application/x-ndjson) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPIResult parses an HTTP response from a GetOpenAPIWithResponse call
+func ParseGetOpenAPIResult(rsp *http.Response) (*GetOpenAPIResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPIResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetReadyzResult parses an HTTP response from a GetReadyzWithResponse call
+func ParseGetReadyzResult(rsp *http.Response) (*GetReadyzResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetReadyzResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest HealthResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest HealthResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListSecretsResult parses an HTTP response from a ListSecretsWithResponse call
+func ParseListSecretsResult(rsp *http.Response) (*ListSecretsResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListSecretsResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ListSecretsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteSecretResult parses an HTTP response from a DeleteSecretWithResponse call
+func ParseDeleteSecretResult(rsp *http.Response) (*DeleteSecretResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteSecretResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutSecretResult parses an HTTP response from a PutSecretWithResponse call
+func ParsePutSecretResult(rsp *http.Response) (*PutSecretResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutSecretResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SecretInfo
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest SecretInfo
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetTopologyResult parses an HTTP response from a GetTopologyWithResponse call
+func ParseGetTopologyResult(rsp *http.Response) (*GetTopologyResult, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetTopologyResult{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Topology
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
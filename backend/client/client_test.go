@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/client"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// newTestClient serves the real handler with an offline agent, so that no
+// ngrok SDK calls are made, and returns a client for it
+func newTestClient(t *testing.T) *client.ClientWithResponses {
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	memoryStore := store.NewMemoryStore(&store.State{
+		AgentConfig:     store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "offline"},
+		EndpointConfigs: map[string]store.EndpointConfig{},
+		Version:         1,
+	})
+	mockDocker := mocks.NewMockDockerClient(ctrl)
+	mgr := manager.NewManager(memoryStore, mocks.NewMockNgrokSDK(ctrl), mockDocker, mocks.NewMockProtocolDetector(ctrl), nil, nil, nil, logger, "test-extension-version", 0)
+
+	e := echo.New()
+	handler.New(e, mgr, memoryStore, mockDocker, logger)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	c, err := client.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_EndpointLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	description := "web server"
+	created, err := c.PostEndpointsWithResponse(ctx, nil, client.EndpointRequest{
+		ContainerId:   "web",
+		TargetPort:    "80",
+		Description:   &description,
+		ExpectedState: client.EndpointRequestExpectedStateOffline,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, created.StatusCode())
+	assert.Equal(t, "web:80", created.JSON201.Id)
+	etag := created.HTTPResponse.Header.Get("ETag")
+
+	metadata := "team=web"
+	patched, err := c.PatchEndpointByIDWithApplicationMergePatchPlusJSONBodyWithResponse(ctx, "web:80",
+		&client.PatchEndpointByIDParams{IfMatch: &etag},
+		client.EndpointRequestPatch{Metadata: &metadata})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, patched.StatusCode())
+	assert.Equal(t, &metadata, patched.JSON200.Metadata)
+	assert.Equal(t, &description, patched.JSON200.Description)
+
+	// The old ETag is now stale
+	stale, err := c.DeleteEndpointByIDWithResponse(ctx, "web:80", &client.DeleteEndpointByIDParams{IfMatch: &etag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, stale.StatusCode())
+	require.NotNil(t, stale.JSON412)
+	assert.NotEmpty(t, stale.JSON412.Error)
+
+	list, err := c.GetEndpointsWithResponse(ctx)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, list.StatusCode())
+	require.Len(t, list.JSON200.Endpoints, 1)
+	assert.Equal(t, client.EndpointStatusStateOffline, list.JSON200.Endpoints[0].Status.State)
+
+	batch, err := c.PostEndpointsBatchWithResponse(ctx, client.BatchRequest{
+		Operations: []client.BatchOperation{{Op: client.Delete, Id: ptr("web:80")}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, batch.StatusCode())
+	assert.True(t, batch.JSON200.Applied)
+	assert.Equal(t, []string{"web:80"}, batch.JSON200.Results[0].Ids)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
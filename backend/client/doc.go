@@ -0,0 +1,17 @@
+// Package client is a typed Go client for the ngrok Docker extension backend
+// API, generated from the OpenAPI document the backend serves at
+// GET /openapi.json.
+//
+// The backend listens on a Unix domain socket, so pass an HTTP client that
+// dials it:
+//
+//	httpClient := &http.Client{Transport: &http.Transport{
+//		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+//			var d net.Dialer
+//			return d.DialContext(ctx, "unix", "/run/guest-services/backend.sock")
+//		},
+//	}}
+//	c, err := client.NewClientWithResponses("http://ngrok-ext", client.WithHTTPClient(httpClient))
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.0 -config oapi-codegen.yaml ../internal/handler/openapi.json
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+)
+
+func (c *cli) agentCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: ngrok-ext agent up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		fs := flag.NewFlagSet("agent up", flag.ContinueOnError)
+		authToken := fs.String("authtoken", "", "ngrok authtoken (keeps the saved one if empty)")
+		connectURL := fs.String("connect-url", "", "agent connect URL (keeps the saved one if empty)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		patch := map[string]any{"expectedState": manager.AgentStateOnline}
+		if *authToken != "" {
+			patch["authToken"] = *authToken
+		}
+		if *connectURL != "" {
+			patch["connectURL"] = *connectURL
+		}
+		agent, err := c.client.patchAgent(ctx, patch)
+		if err != nil {
+			return err
+		}
+		return c.printAgent(agent)
+	case "down":
+		agent, err := c.client.patchAgent(ctx, map[string]any{"expectedState": manager.AgentStateOffline})
+		if err != nil {
+			return err
+		}
+		return c.printAgent(agent)
+	case "status":
+		return c.repeat(ctx, func() error {
+			agent, err := c.client.getAgent(ctx)
+			if err != nil {
+				return err
+			}
+			return c.printAgent(agent)
+		})
+	default:
+		return fmt.Errorf("unknown agent command %q", args[0])
+	}
+}
+
+func (c *cli) printAgent(agent *handler.AgentResponse) error {
+	return c.print(agent, []string{"EXPECTED", "STATE", "LATENCY", "AUTHTOKEN", "CONNECT URL", "ERROR"}, func() [][]string {
+		latency := "-"
+		if agent.Status.Latency > 0 {
+			latency = agent.Status.Latency.String()
+		}
+		return [][]string{{
+			orDash(agent.ExpectedState),
+			orDash(agent.Status.State),
+			latency,
+			orDash(redact(agent.AuthToken)),
+			orDash(agent.ConnectURL),
+			orDash(agent.Status.LastError),
+		}}
+	})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/apiauth"
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+)
+
+// client talks to the extension backend over its Unix domain socket
+type client struct {
+	http  *http.Client
+	token string
+}
+
+// newClient creates a client for the backend listening on socketPath. token
+// is sent as a bearer token when non-empty.
+func newClient(socketPath, token string) *client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	return &client{
+		http:  &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		token: token,
+	}
+}
+
+// apiError is a non-2xx response from the backend
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into out, if out is non-nil
+func (c *client) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach extension backend: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return responseError(resp.StatusCode, data)
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// newRequest creates a request to the backend identifying the CLI
+func (c *client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	// The host is ignored when dialing the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://ngrok-ext"+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(apiauth.ClientHeader, "ngrok-ext-cli")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req, nil
+}
+
+// responseError decodes the error of a non-2xx response
+func responseError(statusCode int, data []byte) error {
+	var errResp handler.ErrorResponse
+	if json.Unmarshal(data, &errResp) != nil || errResp.Error == "" {
+		errResp.Error = http.StatusText(statusCode)
+	}
+	return &apiError{StatusCode: statusCode, Message: errResp.Error}
+}
+
+// stream sends a GET request for newline delimited JSON and calls fn with
+// every line until the response ends or ctx is done. Streams are not subject
+// to the client's timeout.
+func (c *client) stream(ctx context.Context, path string, fn func(line []byte) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	streaming := *c.http
+	streaming.Timeout = 0
+	resp, err := streaming.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach extension backend: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, data)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return nil
+}
+
+func (c *client) getAgent(ctx context.Context) (*handler.AgentResponse, error) {
+	var resp handler.AgentResponse
+	return &resp, c.do(ctx, http.MethodGet, "/agent", nil, &resp)
+}
+
+func (c *client) patchAgent(ctx context.Context, patch map[string]any) (*handler.AgentResponse, error) {
+	var resp handler.AgentResponse
+	return &resp, c.do(ctx, http.MethodPatch, "/agent", patch, &resp)
+}
+
+func (c *client) listEndpoints(ctx context.Context) (*handler.GetEndpointsResponse, error) {
+	var resp handler.GetEndpointsResponse
+	return &resp, c.do(ctx, http.MethodGet, "/endpoints", nil, &resp)
+}
+
+func (c *client) getEndpoint(ctx context.Context, id string) (*handler.EndpointResponse, error) {
+	var resp handler.EndpointResponse
+	return &resp, c.do(ctx, http.MethodGet, "/endpoints/"+url.PathEscape(id), nil, &resp)
+}
+
+func (c *client) createEndpoint(ctx context.Context, req handler.EndpointRequest) (*handler.EndpointResponse, error) {
+	var resp handler.EndpointResponse
+	return &resp, c.do(ctx, http.MethodPost, "/endpoints", req, &resp)
+}
+
+func (c *client) patchEndpoint(ctx context.Context, id string, patch map[string]any) (*handler.EndpointResponse, error) {
+	var resp handler.EndpointResponse
+	return &resp, c.do(ctx, http.MethodPatch, "/endpoints/"+url.PathEscape(id), patch, &resp)
+}
+
+func (c *client) deleteEndpoint(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/endpoints/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *client) detectProtocol(ctx context.Context, req handler.DetectProtocolRequest) (*handler.DetectProtocolResponse, error) {
+	var resp handler.DetectProtocolResponse
+	return &resp, c.do(ctx, http.MethodPost, "/detect_protocol", req, &resp)
+}
+
+func (c *client) diagnostics(ctx context.Context) (*diagnostics.Report, error) {
+	var resp diagnostics.Report
+	return &resp, c.do(ctx, http.MethodGet, "/diagnostics", nil, &resp)
+}
+
+// health returns the checks of GET /healthz, or GET /readyz if ready is set.
+// Failing probes respond 503 with their checks, which is not an error here.
+func (c *client) health(ctx context.Context, ready bool) (*handler.HealthResponse, error) {
+	path := "/healthz"
+	if ready {
+		path = "/readyz"
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach extension backend: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var health handler.HealthResponse
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable ||
+		json.Unmarshal(data, &health) != nil || health.Status == "" {
+		return nil, responseError(resp.StatusCode, data)
+	}
+	return &health, nil
+}
+
+// endpointLogs calls fn with the access log entries of an endpoint selected by
+// query, following new entries if query sets follow
+func (c *client) endpointLogs(ctx context.Context, id string, query url.Values, fn func(accesslog.Entry) error) error {
+	query.Set("format", "ndjson")
+	return c.stream(ctx, "/endpoints/"+url.PathEscape(id)+"/logs?"+query.Encode(), func(line []byte) error {
+		var entry accesslog.Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return fn(entry)
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+)
+
+func (c *cli) detectCommand(ctx context.Context, args []string) error {
+	if err := requireArgs(args, 2, "detect <container> <port>"); err != nil {
+		return err
+	}
+
+	result, err := c.client.detectProtocol(ctx, handler.DetectProtocolRequest{
+		ContainerID: args[0],
+		Port:        args[1],
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.print(result, []string{"TCP", "HTTP", "HTTPS", "TLS"}, func() [][]string {
+		return [][]string{{
+			strconv.FormatBool(result.TCP),
+			strconv.FormatBool(result.HTTP),
+			strconv.FormatBool(result.HTTPS),
+			strconv.FormatBool(result.TLS),
+		}}
+	})
+}
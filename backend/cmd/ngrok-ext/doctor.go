@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+)
+
+func (c *cli) doctorCommand(ctx context.Context, args []string) error {
+	if err := requireArgs(args, 0, "doctor"); err != nil {
+		return err
+	}
+
+	report, err := c.client.diagnostics(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(report, []string{"SCOPE", "CHECK", "STATUS", "MESSAGE"}, func() [][]string {
+		var rows [][]string
+		for _, check := range report.Checks {
+			rows = append(rows, checkRow("-", check))
+		}
+		for _, endpoint := range report.Endpoints {
+			for _, check := range endpoint.Checks {
+				rows = append(rows, checkRow(endpoint.ID, check))
+			}
+		}
+		return rows
+	})
+}
+
+func checkRow(scope string, check diagnostics.Check) []string {
+	return []string{scope, check.Name, string(check.Status), check.Message}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+)
+
+func (c *cli) endpointCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: ngrok-ext endpoint ls|add|rm|start|stop|logs")
+	}
+
+	switch args[0] {
+	case "ls", "list":
+		return c.repeat(ctx, func() error {
+			resp, err := c.client.listEndpoints(ctx)
+			if err != nil {
+				return err
+			}
+			slices.SortFunc(resp.Endpoints, func(a, b handler.EndpointResponse) int {
+				return strings.Compare(a.ID, b.ID)
+			})
+			return c.printEndpoints(resp, resp.Endpoints)
+		})
+	case "add":
+		return c.endpointAdd(ctx, args[1:])
+	case "rm", "remove":
+		if err := requireArgs(args[1:], 1, "endpoint rm <id>"); err != nil {
+			return err
+		}
+		if err := c.client.deleteEndpoint(ctx, args[1]); err != nil {
+			return err
+		}
+		if c.output == "json" {
+			return c.printJSON(map[string]string{"deleted": args[1]})
+		}
+		fmt.Fprintf(c.out, "Removed endpoint %s\n", args[1])
+		return nil
+	case "start", "stop":
+		if err := requireArgs(args[1:], 1, "endpoint "+args[0]+" <id>"); err != nil {
+			return err
+		}
+		expectedState := manager.EndpointStateOnline
+		if args[0] == "stop" {
+			expectedState = manager.EndpointStateOffline
+		}
+		endpoint, err := c.client.patchEndpoint(ctx, args[1], map[string]any{"expectedState": expectedState})
+		if err != nil {
+			return err
+		}
+		return c.printEndpoints(endpoint, []handler.EndpointResponse{*endpoint})
+	case "logs":
+		return c.endpointLogs(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown endpoint command %q", args[0])
+	}
+}
+
+func (c *cli) endpointAdd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("endpoint add", flag.ContinueOnError)
+	url := fs.String("url", "", "endpoint URL, e.g. https://example.ngrok.app, tls://mqtt.example.com or tcp://1.tcp.ngrok.io:12345")
+	binding := fs.String("binding", "", "endpoint binding: public, internal or kubernetes")
+	pooling := fs.Bool("pooling", false, "enable endpoint pooling")
+	policyFile := fs.String("traffic-policy-file", "", "file containing the endpoint's traffic policy")
+	description := fs.String("description", "", "endpoint description")
+	metadata := fs.String("metadata", "", "endpoint metadata")
+	offline := fs.Bool("offline", false, "create the endpoint without starting it")
+	proxyProtocol := fs.String("proxy-protocol", "", "send a PROXY protocol header to the upstream: v1 or v2 (tcp and tls endpoints)")
+	tlsTermination := fs.String("tls-termination", "", "where to terminate TLS: passthrough or edge (tls endpoints)")
+	forwardedFor := fs.String("forwarded-for", "", "X-Forwarded-For sent to the upstream: append, replace or remove (http endpoints)")
+	realIP := fs.Bool("real-ip", false, "set X-Real-IP to the client's address (http endpoints)")
+	hostHeader := fs.String("host-header", "", "Host header sent to the upstream: rewrite, or a host (http endpoints)")
+	upstream := fs.String("upstream", "", "forward to this upstream URL instead of a container port, e.g. http://host.docker.internal:3000")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *upstream != "" {
+		if err := requireArgs(fs.Args(), 0, "endpoint add [flags] --upstream <url>"); err != nil {
+			return err
+		}
+	} else if err := requireArgs(fs.Args(), 2, "endpoint add [flags] <container> <port>"); err != nil {
+		return err
+	}
+
+	req := handler.EndpointRequest{
+		ContainerID:    fs.Arg(0),
+		TargetPort:     fs.Arg(1),
+		Upstream:       *upstream,
+		URL:            *url,
+		Binding:        *binding,
+		PoolingEnabled: *pooling,
+		Description:    *description,
+		Metadata:       *metadata,
+		ProxyProtocol:  *proxyProtocol,
+		TLSTermination: *tlsTermination,
+		ForwardedFor:   *forwardedFor,
+		RealIP:         *realIP,
+		HostHeader:     *hostHeader,
+		ExpectedState:  manager.EndpointStateOnline,
+	}
+	if *offline {
+		req.ExpectedState = manager.EndpointStateOffline
+	}
+	if *policyFile != "" {
+		policy, err := os.ReadFile(*policyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read traffic policy: %w", err)
+		}
+		req.TrafficPolicy = string(policy)
+	}
+
+	endpoint, err := c.client.createEndpoint(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.printEndpoints(endpoint, []handler.EndpointResponse{*endpoint})
+}
+
+// endpointLogs prints the access log of an endpoint, one line per request or
+// connection, and with --follow keeps printing new entries until interrupted
+func (c *cli) endpointLogs(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("endpoint logs", flag.ContinueOnError)
+	follow := fs.Bool("follow", false, "keep printing new entries until interrupted")
+	since := fs.String("since", "", "only entries since a duration ago, e.g. 1h, or an RFC 3339 timestamp")
+	status := fs.String("status", "", "only entries with a status code, e.g. 404, or class, e.g. 5xx")
+	method := fs.String("method", "", "only requests with an HTTP method")
+	path := fs.String("path", "", "only requests whose path starts with a prefix")
+	remoteAddr := fs.String("remote-addr", "", "only entries from a client address")
+	limit := fs.Int("limit", 0, "only the most recent entries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 1, "endpoint logs [flags] <id>"); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *since != "" {
+		if d, err := time.ParseDuration(*since); err == nil {
+			query.Set("since", time.Now().Add(-d).Format(time.RFC3339))
+		} else if _, err := time.Parse(time.RFC3339, *since); err == nil {
+			query.Set("since", *since)
+		} else {
+			return fmt.Errorf("invalid --since %q: must be a duration or an RFC 3339 timestamp", *since)
+		}
+	}
+	for name, value := range map[string]string{"status": *status, "method": *method, "path": *path, "remoteAddr": *remoteAddr} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if *limit > 0 {
+		query.Set("limit", strconv.Itoa(*limit))
+	}
+	if *follow {
+		query.Set("follow", "true")
+	}
+
+	encoder := json.NewEncoder(c.out)
+	return c.client.endpointLogs(ctx, fs.Arg(0), query, func(entry accesslog.Entry) error {
+		if c.output == "json" {
+			return encoder.Encode(entry)
+		}
+		fmt.Fprintln(c.out, formatAccessLogEntry(entry))
+		return nil
+	})
+}
+
+// formatAccessLogEntry formats an access log entry as a line of text
+func formatAccessLogEntry(entry accesslog.Entry) string {
+	fields := []string{entry.Time.Format(time.RFC3339), orDash(entry.RemoteAddr)}
+	if entry.Method != "" {
+		fields = append(fields, entry.Method, entry.Path, strconv.Itoa(entry.Status))
+	} else {
+		fields = append(fields, entry.Protocol)
+	}
+	fields = append(fields,
+		fmt.Sprintf("in=%dB", entry.BytesIn),
+		fmt.Sprintf("out=%dB", entry.BytesOut),
+		fmt.Sprintf("%dms", entry.DurationMs),
+	)
+	if entry.Error != "" {
+		fields = append(fields, fmt.Sprintf("error=%q", entry.Error))
+	}
+	return strings.Join(fields, " ")
+}
+
+// printEndpoints prints v as JSON or the endpoints as a table
+func (c *cli) printEndpoints(v any, endpoints []handler.EndpointResponse) error {
+	return c.print(v, []string{"ID", "EXPECTED", "STATE", "URL", "ERROR"}, func() [][]string {
+		rows := make([][]string, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			url := endpoint.Status.URL
+			if url == "" {
+				url = endpoint.URL
+			}
+			rows = append(rows, []string{
+				endpoint.ID,
+				orDash(endpoint.ExpectedState),
+				orDash(endpoint.Status.State),
+				orDash(url),
+				orDash(endpoint.Status.LastError),
+			})
+		}
+		return rows
+	})
+}
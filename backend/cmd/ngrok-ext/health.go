@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+)
+
+// healthCommand prints the backend's liveness checks, or its readiness checks
+// with --ready, and fails if any of them fails. It is the container's
+// HEALTHCHECK.
+func (c *cli) healthCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	ready := fs.Bool("ready", false, "check readiness instead of liveness")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 0, "health [--ready]"); err != nil {
+		return err
+	}
+
+	health, err := c.client.health(ctx, *ready)
+	if err != nil {
+		return err
+	}
+
+	err = c.print(health, []string{"CHECK", "STATUS", "MESSAGE"}, func() [][]string {
+		var rows [][]string
+		for _, check := range health.Checks {
+			rows = append(rows, []string{check.Name, string(check.Status), check.Message})
+		}
+		return rows
+	})
+	if err != nil {
+		return err
+	}
+
+	if health.Status == diagnostics.StatusFail {
+		if *ready {
+			return errors.New("backend is not ready")
+		}
+		return errors.New("backend is not healthy")
+	}
+	return nil
+}
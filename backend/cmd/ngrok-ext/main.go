@@ -0,0 +1,141 @@
+// Command ngrok-ext drives the ngrok Docker extension backend from a terminal
+// by talking to its Unix domain socket API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const usage = `Usage: ngrok-ext [global flags] <command> [flags] [args]
+
+Commands:
+  agent up [--authtoken TOKEN] [--connect-url URL]
+  agent down
+  agent status
+  endpoint ls
+  endpoint add [flags] <container> <port>
+  endpoint add [flags] --upstream <url>
+  endpoint rm <id>
+  endpoint start <id>
+  endpoint stop <id>
+  endpoint logs [--follow] [--since DURATION] [--status CODE] <id>
+  detect <container> <port>
+  doctor
+  health [--ready]
+
+Global flags:
+`
+
+// cli holds the global options shared by every command
+type cli struct {
+	client   *client
+	out      io.Writer
+	output   string        // "table" | "json"
+	watch    bool          // re-run read commands until interrupted
+	interval time.Duration // refresh interval for --watch
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags and dispatches to a command
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	defaultSocket := os.Getenv("NGROK_EXT_SOCKET")
+	if defaultSocket == "" {
+		defaultSocket = "/run/guest-services/backend.sock"
+	}
+
+	fs := flag.NewFlagSet("ngrok-ext", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	socketPath := fs.String("socket", defaultSocket, "backend Unix domain socket (env NGROK_EXT_SOCKET)")
+	token := fs.String("token", os.Getenv("NGROK_EXT_API_TOKEN"), "API token, one of the backend's NGROK_EXT_API_TOKENS (env NGROK_EXT_API_TOKEN)")
+	output := fs.String("output", "table", "output format: table or json")
+	watch := fs.Bool("watch", false, "keep refreshing status and ls output")
+	interval := fs.Duration("interval", 2*time.Second, "refresh interval for --watch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	c := &cli{
+		client:   newClient(*socketPath, *token),
+		out:      stdout,
+		output:   *output,
+		watch:    *watch,
+		interval: *interval,
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	switch rest[0] {
+	case "agent":
+		return c.agentCommand(ctx, rest[1:])
+	case "endpoint", "endpoints":
+		return c.endpointCommand(ctx, rest[1:])
+	case "detect":
+		return c.detectCommand(ctx, rest[1:])
+	case "doctor":
+		return c.doctorCommand(ctx, rest[1:])
+	case "health":
+		return c.healthCommand(ctx, rest[1:])
+	case "help":
+		fs.Usage()
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", rest[0])
+	}
+}
+
+// repeat runs fn once, or until ctx is done when --watch is set
+func (c *cli) repeat(ctx context.Context, fn func() error) error {
+	for {
+		if err := fn(); err != nil {
+			return err
+		}
+		if !c.watch {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.interval):
+		}
+		if c.output == "table" {
+			// Clear the screen between refreshes
+			fmt.Fprint(c.out, "\033[H\033[2J")
+		}
+	}
+}
+
+// requireArgs checks the number of positional arguments of a subcommand
+func requireArgs(args []string, n int, synopsis string) error {
+	if len(args) != n {
+		return fmt.Errorf("usage: ngrok-ext %s", synopsis)
+	}
+	return nil
+}
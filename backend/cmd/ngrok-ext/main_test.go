@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+)
+
+// serveUnix serves h on a Unix socket in a temp dir and returns its path
+func serveUnix(t *testing.T, h http.Handler) string {
+	socketPath := filepath.Join(t.TempDir(), "backend.sock")
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: h}
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Close() })
+	return socketPath
+}
+
+func TestEndpointList_Table(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /endpoints", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(handler.GetEndpointsResponse{
+			Endpoints: []handler.EndpointResponse{
+				{ID: "web:80", ExpectedState: "online", Status: manager.EndpointStatus{State: "online", URL: "https://web.ngrok.app"}},
+				{ID: "api:8080", ExpectedState: "offline", Status: manager.EndpointStatus{State: "offline"}},
+			},
+		})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "endpoint", "ls"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"ID        EXPECTED  STATE    URL                    ERROR\n"+
+			"api:8080  offline   offline  -                      -\n"+
+			"web:80    online    online   https://web.ngrok.app  -\n",
+		stdout.String())
+}
+
+func TestEndpointStop_SendsPatchAndPrintsJSON(t *testing.T) {
+	var patch map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &patch)
+		json.NewEncoder(w).Encode(handler.EndpointResponse{ID: r.PathValue("id"), ExpectedState: "offline"})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "--output", "json", "endpoint", "stop", "web:80"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"expectedState": "offline"}, patch)
+
+	var endpoint handler.EndpointResponse
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &endpoint))
+	assert.Equal(t, "web:80", endpoint.ID)
+}
+
+func TestEndpointStop_EscapesID(t *testing.T) {
+	var path, id string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		path, id = r.URL.EscapedPath(), r.PathValue("id")
+		json.NewEncoder(w).Encode(handler.EndpointResponse{ID: id, ExpectedState: "offline"})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "endpoint", "stop", "upstream:[::1]:3000"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/endpoints/upstream:%5B::1%5D:3000", path)
+	assert.Equal(t, "upstream:[::1]:3000", id)
+}
+
+func TestEndpointLogs_PrintsAccessLog(t *testing.T) {
+	var query url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /endpoints/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		encoder.Encode(accesslog.Entry{
+			Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), EndpointID: r.PathValue("id"), Protocol: "http",
+			RemoteAddr: "203.0.113.7", Method: "GET", Path: "/missing", Status: 404, BytesOut: 19, DurationMs: 3,
+		})
+		encoder.Encode(accesslog.Entry{
+			Time: time.Date(2025, 1, 1, 0, 0, 1, 0, time.UTC), EndpointID: r.PathValue("id"), Protocol: "http",
+			Error: "dial tcp 172.17.0.1:80: connect: connection refused",
+		})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "endpoint", "logs", "--follow", "--status", "4xx", "web:80"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "true", query.Get("follow"))
+	assert.Equal(t, "4xx", query.Get("status"))
+	assert.Equal(t,
+		"2025-01-01T00:00:00Z 203.0.113.7 GET /missing 404 in=0B out=19B 3ms\n"+
+			"2025-01-01T00:00:01Z - http in=0B out=0B 0ms error=\"dial tcp 172.17.0.1:80: connect: connection refused\"\n",
+		stdout.String())
+}
+
+func TestAPIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Endpoint not found"})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "endpoint", "rm", "missing:80"}, &stdout, &stderr)
+	assert.EqualError(t, err, "Endpoint not found (HTTP 404)")
+}
+
+func TestHealth_NotReady(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(handler.HealthResponse{
+			Status: diagnostics.StatusFail,
+			Checks: []diagnostics.Check{
+				{Name: "initial_convergence", Status: diagnostics.StatusFail, Message: "Initial convergence has not finished"},
+				{Name: "docker", Status: diagnostics.StatusPass, Message: "Docker is reachable"},
+			},
+		})
+	})
+	socketPath := serveUnix(t, mux)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"--socket", socketPath, "health", "--ready"}, &stdout, &stderr)
+	assert.EqualError(t, err, "backend is not ready")
+	assert.Equal(t,
+		"CHECK                STATUS  MESSAGE\n"+
+			"initial_convergence  fail    Initial convergence has not finished\n"+
+			"docker               pass    Docker is reachable\n",
+		stdout.String())
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
 )
 
 // ngrokWrapper provides a concrete implementation of the NgrokSDK interface
@@ -23,3 +28,49 @@ type dockerWrapper struct {
 func (w *dockerWrapper) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	return w.client.ContainerInspect(ctx, containerID)
 }
+
+func (w *dockerWrapper) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+	return w.client.ContainerList(ctx, options)
+}
+
+func (w *dockerWrapper) ContainerExec(ctx context.Context, containerID string, cmd []string) ([]byte, error) {
+	exec, err := w.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := w.client.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+	// Reading the hijacked connection ignores ctx, so close it to unblock
+	stop := context.AfterFunc(ctx, resp.Close)
+	defer stop()
+
+	// The stream multiplexes stdout and stderr
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	inspect, err := w.client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return nil, err
+	}
+	if inspect.ExitCode != 0 {
+		return stdout.Bytes(), &manager.ExecExitError{ExitCode: inspect.ExitCode, Stderr: stderr.String()}
+	}
+	return stdout.Bytes(), nil
+}
+
+func (w *dockerWrapper) Ping(ctx context.Context) (types.Ping, error) {
+	return w.client.Ping(ctx)
+}
@@ -3,27 +3,38 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/apiauth"
+	"github.com/ngrok/ngrok-docker-extension/internal/audit"
 	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/gitops"
 	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
 	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
 )
 
 // ngrokExtension encapsulates all the state and functionality of the ngrok Docker extension
 type ngrokExtension struct {
 	// Configuration
 	socketPath string
+	stateDir   string
+	logs       *logging.Logs
 	logger     *slog.Logger
 
 	// HTTP server components
@@ -31,15 +42,32 @@ type ngrokExtension struct {
 	handler *handler.Handler
 
 	// State management components
-	store   store.Store
-	manager manager.Manager
+	store    store.Store
+	manager  manager.Manager
+	docker   manager.DockerClient
+	detector manager.ProtocolDetector
+	auditLog *audit.Log
+	access   *accesslog.Log
+	gitops   *gitops.Store // set in GitOps mode
+	secrets  *secrets.Store
+
+	// Telemetry
+	propagator        *telemetry.Propagator // set when telemetry is exported
+	telemetryShutdown func(context.Context) error
 }
 
 // newNgrokExtension creates and initializes a new ngrok extension instance
-func newNgrokExtension(socketPath string, logger *slog.Logger) (*ngrokExtension, error) {
+func newNgrokExtension(socketPath string, logs *logging.Logs) (*ngrokExtension, error) {
 	ext := &ngrokExtension{
 		socketPath: socketPath,
-		logger:     logger,
+		stateDir:   stateDir(os.Getenv),
+		logs:       logs,
+		logger:     logs.Logger(""),
+	}
+
+	// Export traces and metrics before anything is instrumented
+	if err := ext.initTelemetry(); err != nil {
+		return nil, fmt.Errorf("failed to initialize telemetry: %w", err)
 	}
 
 	// Initialize store from volume-mounted path
@@ -66,37 +94,183 @@ func newNgrokExtension(socketPath string, logger *slog.Logger) (*ngrokExtension,
 // initRouter sets up the Echo router with middleware and error handling
 func (ext *ngrokExtension) initRouter() error {
 	ext.router = echo.New()
+	httpLogger := ext.logs.Logger(logging.SubsystemHTTP)
 	ext.router.HTTPErrorHandler = func(err error, c echo.Context) {
-		ext.logger.Error("HTTP error", "error", err)
+		httpLogger.Error("HTTP error", "error", err)
 		c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	ext.router.HideBanner = true
+	ext.router.Use(requestLogger(httpLogger))
+	ext.router.Use(telemetry.Middleware())
+
+	// API tokens are optional; when NGROK_EXT_API_TOKENS is unset callers
+	// identify themselves with the client header. The CLI sends a single
+	// token, from NGROK_EXT_API_TOKEN.
+	tokens := apiauth.ParseTokens(os.Getenv("NGROK_EXT_API_TOKENS"))
+	if len(tokens) > 0 {
+		ext.logger.Info("API token authentication enabled", "tokens", len(tokens))
+	}
+	ext.router.Use(apiauth.Middleware(tokens, isHealthCheck))
+
+	ext.auditLog = audit.NewLog(filepath.Join(ext.stateDir, "audit.log"))
+	ext.router.Use(audit.Middleware(ext.auditLog, httpLogger))
+
+	return nil
+}
 
-	logMiddleware := middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Skipper: middleware.DefaultSkipper,
-		Format: `{"time":"${time_rfc3339_nano}","id":"${id}",` +
-			`"host":"${host}","method":"${method}","uri":"${uri}","user_agent":"${user_agent}",` +
-			`"status":${status},"error":"${error}","latency":${latency},"latency_human":"${latency_human}"` +
-			`,"bytes_in":${bytes_in},"bytes_out":${bytes_out}}` + "\n",
-		CustomTimeFormat: "2006-01-02 15:04:05.00000",
-		Output:           os.Stdout,
+// isHealthCheck reports whether a request is a liveness or readiness probe.
+// Probes need no API token, so that the container's HEALTHCHECK can run
+// without one.
+func isHealthCheck(c echo.Context) bool {
+	path := c.Request().URL.Path
+	return path == "/healthz" || path == "/readyz"
+}
+
+// requestLogger logs every API request. Successful reads, such as the
+// dashboard's polling, are only logged at debug level.
+func requestLogger(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogUserAgent: true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case v.Method == http.MethodGet && v.Status < http.StatusBadRequest:
+				level = slog.LevelDebug
+			}
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("user_agent", v.UserAgent),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+			logger.LogAttrs(c.Request().Context(), level, "HTTP request", attrs...)
+			return nil
+		},
 	})
-	ext.router.Use(logMiddleware)
+}
 
+// stateDir returns the directory state is kept in, NGROK_EXT_STATE_DIR
+func stateDir(getenv func(string) string) string {
+	if dir := getenv("NGROK_EXT_STATE_DIR"); dir != "" {
+		return dir
+	}
+	return "/tmp" // fallback for development
+}
+
+// loggingConfig reads the initial log levels: NGROK_EXT_LOG_LEVEL is the
+// default level and NGROK_EXT_LOG_LEVELS sets subsystems apart, e.g.
+// "manager=debug,http=warn"
+func loggingConfig(getenv func(string) string) (logging.Config, error) {
+	config := logging.Config{Level: getenv("NGROK_EXT_LOG_LEVEL")}
+	if v := getenv("NGROK_EXT_LOG_LEVELS"); v != "" {
+		levels, err := logging.ParseSubsystemLevels(v)
+		if err != nil {
+			return config, fmt.Errorf("invalid NGROK_EXT_LOG_LEVELS: %w", err)
+		}
+		config.Subsystems = levels
+	}
+	return config, nil
+}
+
+// initTelemetry exports traces and metrics to the OTLP/HTTP collector at
+// NGROK_EXT_OTLP_ENDPOINT, or the one the standard OTEL_EXPORTER_OTLP_*
+// variables configure. Without either, nothing is exported.
+// NGROK_EXT_TRACE_UPSTREAMS=true also propagates traces to HTTP upstreams.
+func (ext *ngrokExtension) initTelemetry() error {
+	config := telemetry.ConfigFromEnv(os.Getenv, os.Getenv("EXTENSION_VERSION"))
+	shutdown, err := telemetry.Setup(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	ext.telemetryShutdown = shutdown
+	if config.Enabled() {
+		ext.logger.Info("Telemetry export enabled", "endpoint", config.Endpoint, "propagateTraces", config.PropagateTraces)
+		// Trace context is propagated to the upstreams of HTTP endpoints
+		// only on request, since their requests are then proxied in process
+		if config.PropagateTraces {
+			ext.propagator = telemetry.NewPropagator()
+		}
+	}
 	return nil
 }
 
-// initStore initializes the file store from environment variable
+// initStore initializes the store from environment variables.
+// NGROK_EXT_CONFIG_FILE enables GitOps mode, where the desired state is read
+// from that file. Otherwise NGROK_EXT_STATE_BACKEND selects "file" (the
+// default, state.json) or "bolt" (state.db, migrated from state.json on first
+// start).
 func (ext *ngrokExtension) initStore() error {
-	// Get state directory from environment variable
-	stateDir := os.Getenv("NGROK_EXT_STATE_DIR")
-	if stateDir == "" {
-		stateDir = "/tmp" // fallback for development
+	storeLogger := ext.logs.Logger(logging.SubsystemStore)
+	statePath := filepath.Join(ext.stateDir, "state.json")
+
+	// Secrets are kept apart from the desired state in every mode
+	secretStore, err := secrets.NewStore(filepath.Join(ext.stateDir, "secrets.json"))
+	if err != nil {
+		return err
 	}
+	ext.secrets = secretStore
 
-	statePath := filepath.Join(stateDir, "state.json")
-	ext.store = store.NewFileStoreWithLogger(statePath, ext.logger)
+	if configFile := os.Getenv("NGROK_EXT_CONFIG_FILE"); configFile != "" {
+		source, err := gitops.NewStore(configFile, os.Getenv, storeLogger)
+		if err != nil {
+			return err
+		}
+		ext.logger.Info("GitOps mode enabled, the REST API is read-only", "config", configFile)
+		ext.gitops = source
+		ext.store = source
+		return nil
+	}
 
+	switch backend := os.Getenv("NGROK_EXT_STATE_BACKEND"); backend {
+	case "", "file":
+		ext.store = store.NewFileStoreWithLogger(statePath, storeLogger)
+	case "bolt":
+		boltStore, err := store.NewBoltStore(filepath.Join(ext.stateDir, "state.db"))
+		if err != nil {
+			return err
+		}
+		if _, err := store.MigrateFileStore(statePath, boltStore, storeLogger); err != nil {
+			boltStore.Close()
+			return err
+		}
+		ext.store = boltStore
+	default:
+		return fmt.Errorf("unknown state backend %q", backend)
+	}
+
+	return ext.migrateState()
+}
+
+// migrateState upgrades the endpoints saved by earlier versions
+func (ext *ngrokExtension) migrateState() error {
+	state, err := ext.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to migrate state: %w", err)
+	}
+	if len(manager.MigrateTLSTermination(state)) == 0 {
+		return nil
+	}
+
+	var migrated []string
+	if err := ext.store.Update(func(state *store.State) error {
+		migrated = manager.MigrateTLSTermination(state)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to migrate state: %w", err)
+	}
+	ext.logger.Info("TLS endpoints saved without tlsTermination now terminate at the edge, as before", "endpoints", migrated)
 	return nil
 }
 
@@ -108,6 +282,8 @@ func (ext *ngrokExtension) initManager() error {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
+	ext.docker = &dockerWrapper{dockerClient}
+
 	// Create ngrok SDK wrapper
 	ngrokSDK := &ngrokWrapper{}
 
@@ -117,19 +293,97 @@ func (ext *ngrokExtension) initManager() error {
 		extensionVersion = "unknown"
 	}
 
-	// Create protocol detector
-	protocolDetector := detectproto.NewDetector()
+	// Create protocol detector, caching results per container run
+	detectConfig, cacheTTL, err := detectorConfig(os.Getenv)
+	if err != nil {
+		return err
+	}
+	detectConfig.Logger = ext.logs.Logger(logging.SubsystemDetectProto)
+	ext.detector = detectproto.NewCache(detectproto.NewDetectorWithConfig(detectConfig), cacheTTL, ext.containerStartedAt)
+
+	// Record the traffic forwarded to each endpoint
+	ext.access = accesslog.NewLog(filepath.Join(ext.stateDir, "access"), ext.logger)
 
 	// Create manager with extension version and 5 second converge interval
 	convergeInterval := 5 * time.Second
-	ext.manager = manager.NewManager(ext.store, ngrokSDK, &dockerWrapper{dockerClient}, protocolDetector, ext.logger, extensionVersion, convergeInterval)
+	ext.manager = manager.NewManager(ext.store, ngrokSDK, ext.docker, ext.detector, secrets.NewResolver(ext.secrets), ext.access, ext.propagator, ext.logs.Logger(logging.SubsystemManager), extensionVersion, convergeInterval)
 
 	return nil
 }
 
+// detectorConfig reads the protocol detection settings:
+// NGROK_EXT_DETECT_TIMEOUT bounds each probe, NGROK_EXT_DETECT_PROBE_PATHS is a
+// comma-separated list of paths requested to detect HTTP, and
+// NGROK_EXT_DETECT_CACHE_TTL is how long results are reused
+func detectorConfig(getenv func(string) string) (detectproto.Config, time.Duration, error) {
+	config := detectproto.DefaultConfig()
+	cacheTTL := detectproto.DefaultCacheTTL
+
+	if v := getenv("NGROK_EXT_DETECT_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout <= 0 {
+			return config, 0, fmt.Errorf("invalid NGROK_EXT_DETECT_TIMEOUT %q", v)
+		}
+		config.Timeout = timeout
+	}
+	if v := getenv("NGROK_EXT_DETECT_PROBE_PATHS"); v != "" {
+		var paths []string
+		for _, path := range strings.Split(v, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				if !strings.HasPrefix(path, "/") {
+					path = "/" + path
+				}
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) == 0 {
+			return config, 0, fmt.Errorf("invalid NGROK_EXT_DETECT_PROBE_PATHS %q", v)
+		}
+		config.ProbePaths = paths
+	}
+	if v := getenv("NGROK_EXT_DETECT_CACHE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl < 0 {
+			return config, 0, fmt.Errorf("invalid NGROK_EXT_DETECT_CACHE_TTL %q", v)
+		}
+		cacheTTL = ttl
+	}
+	return config, cacheTTL, nil
+}
+
+// containerStartedAt returns when a container was last started
+func (ext *ngrokExtension) containerStartedAt(ctx context.Context, containerID string) (string, error) {
+	info, err := ext.docker.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return "", fmt.Errorf("container %s has no state", containerID)
+	}
+	return info.State.StartedAt, nil
+}
+
 // initHandler creates the HTTP handler with all dependencies
 func (ext *ngrokExtension) initHandler() {
-	ext.handler = handler.New(ext.router, ext.manager, ext.store, ext.logger)
+	opts := []handler.Option{
+		handler.WithAuditLog(ext.auditLog),
+		handler.WithAccessLog(ext.access),
+		handler.WithLogs(ext.logs),
+		handler.WithProtocolDetector(ext.detector),
+		handler.WithSecrets(ext.secrets),
+	}
+	if v := os.Getenv("NGROK_EXT_STUCK_CONVERGE_THRESHOLD"); v != "" {
+		threshold, err := time.ParseDuration(v)
+		if err != nil || threshold <= 0 {
+			ext.logger.Warn("Ignoring invalid NGROK_EXT_STUCK_CONVERGE_THRESHOLD", "value", v)
+		} else {
+			opts = append(opts, handler.WithStuckConvergeThreshold(threshold))
+		}
+	}
+	if ext.gitops != nil {
+		opts = append(opts, handler.WithGitOps(ext.gitops))
+	}
+	ext.handler = handler.New(ext.router, ext.manager, ext.store, ext.docker, ext.logs.Logger(logging.SubsystemHTTP), opts...)
 }
 
 // Run starts the extension and runs until the context is cancelled
@@ -198,5 +452,24 @@ func (ext *ngrokExtension) shutdown() error {
 		return fmt.Errorf("failed to shutdown server gracefully: %w", err)
 	}
 
+	if ext.propagator != nil {
+		if err := ext.propagator.Close(); err != nil {
+			ext.logger.Warn("Error closing trace propagator", "error", err)
+		}
+	}
+	if err := ext.telemetryShutdown(shutdownCtx); err != nil {
+		ext.logger.Warn("Error flushing telemetry", "error", err)
+	}
+
+	if closer, ok := ext.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			ext.logger.Warn("Error closing store", "error", err)
+		}
+	}
+
+	if err := ext.logs.Close(); err != nil {
+		ext.logger.Warn("Error closing log file", "error", err)
+	}
+
 	return nil
 }
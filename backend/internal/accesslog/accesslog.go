@@ -0,0 +1,203 @@
+// Package accesslog records the connections and requests forwarded to the
+// upstream of each endpoint, in a rotating newline delimited JSON file per
+// endpoint.
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/ndjson"
+)
+
+// Protocols of entries
+const (
+	ProtocolHTTP = "http" // one entry per request
+	ProtocolTCP  = "tcp"  // one entry per connection
+)
+
+// Rotation defaults of NewLog
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024 // bytes per file
+	DefaultMaxBackups = 3                // rotated files kept per endpoint
+)
+
+// Entry records one request or connection forwarded to an endpoint's upstream
+type Entry struct {
+	Time       time.Time `json:"time"` // when the request or connection started
+	EndpointID string    `json:"endpoint"`
+	Protocol   string    `json:"protocol"`             // "http" | "tcp"
+	RemoteAddr string    `json:"remoteAddr,omitempty"` // the client's address, when known
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	BytesIn    int64     `json:"bytesIn"`  // body bytes of requests, or bytes sent by the client
+	BytesOut   int64     `json:"bytesOut"` // body bytes of responses, or bytes sent by the upstream
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"` // why the upstream could not be reached
+}
+
+// Filter selects entries from the log. Zero values match everything.
+type Filter struct {
+	Since      time.Time
+	Until      time.Time
+	RemoteAddr string
+	Method     string
+	PathPrefix string
+	Status     string // a status code such as "404" or a class such as "5xx"
+	Limit      int    // keep only the most recent entries
+}
+
+// ParseStatus checks a status filter
+func ParseStatus(status string) error {
+	if len(status) == 3 && status[0] >= '1' && status[0] <= '5' {
+		if strings.ToLower(status[1:]) == "xx" {
+			return nil
+		}
+		if _, err := strconv.Atoi(status); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("status must be a status code such as 404 or a class such as 5xx, got %q", status)
+}
+
+// Matches reports whether entry is selected by f
+func (f Filter) Matches(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	if f.RemoteAddr != "" && entry.RemoteAddr != f.RemoteAddr {
+		return false
+	}
+	if f.Method != "" && !strings.EqualFold(entry.Method, f.Method) {
+		return false
+	}
+	if f.PathPrefix != "" && !strings.HasPrefix(entry.Path, f.PathPrefix) {
+		return false
+	}
+	if f.Status != "" {
+		status := strconv.Itoa(entry.Status)
+		if strings.HasSuffix(strings.ToLower(f.Status), "xx") {
+			return status[0] == f.Status[0]
+		}
+		return status == f.Status
+	}
+	return true
+}
+
+// Log stores the access log of every endpoint under a directory, rotating
+// each endpoint's file once it reaches a size
+type Log struct {
+	dir        string
+	maxSize    int64
+	maxBackups int
+	logger     *slog.Logger // reports entries that could not be recorded
+
+	mu        sync.Mutex
+	followers *ndjson.Followers[Entry] // by endpoint
+}
+
+// NewLog creates a log storing files under dir with the default rotation
+func NewLog(dir string, logger *slog.Logger) *Log {
+	return NewLogWithRotation(dir, DefaultMaxSize, DefaultMaxBackups, logger)
+}
+
+// NewLogWithRotation creates a log storing files under dir, rotating them
+// once they reach maxSize bytes and keeping maxBackups rotated files
+func NewLogWithRotation(dir string, maxSize int64, maxBackups int, logger *slog.Logger) *Log {
+	l := &Log{
+		dir:        dir,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		logger:     logger,
+	}
+	l.followers = ndjson.NewFollowers[Entry](&l.mu)
+	return l
+}
+
+// files returns the file of an endpoint's log and its rotated backups
+func (l *Log) files(endpointID string) ndjson.Files {
+	return ndjson.Files{
+		Path:       filepath.Join(l.dir, url.QueryEscape(endpointID)+".log"),
+		MaxBackups: l.maxBackups,
+	}
+}
+
+// Record appends entry to its endpoint's log and sends it to followers
+func (l *Log) Record(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.followers.Send(entry.EndpointID, entry)
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+	files := l.files(entry.EndpointID)
+	if info, err := os.Stat(files.Path); err == nil && info.Size()+int64(len(data)) > l.maxSize {
+		if err := files.Rotate(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(files.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// Query returns the entries of an endpoint selected by filter, oldest first
+func (l *Log) Query(endpointID string, filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.query(endpointID, filter)
+}
+
+func (l *Log) query(endpointID string, filter Filter) ([]Entry, error) {
+	return ndjson.Read(l.files(endpointID), parseEntry, filter.Matches, filter.Limit)
+}
+
+// parseEntry parses a line of the log
+func parseEntry(line []byte) (Entry, error) {
+	var entry Entry
+	err := json.Unmarshal(line, &entry)
+	return entry, err
+}
+
+// Follow returns the entries of an endpoint selected by filter, like Query,
+// and a channel of those recorded from then on, until ctx is done. Entries
+// are dropped from the channel if the receiver falls behind.
+func (l *Log) Follow(ctx context.Context, endpointID string, filter Filter) ([]Entry, <-chan Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.query(endpointID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, l.followers.Add(ctx, endpointID, filter.Matches), nil
+}
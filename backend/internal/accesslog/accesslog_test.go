@@ -0,0 +1,192 @@
+package accesslog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRotationAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	log := NewLogWithRotation(dir, 300, 1, nil)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 10 {
+		require.NoError(t, log.Record(Entry{
+			Time:       start.Add(time.Duration(i) * time.Minute),
+			EndpointID: "web:80",
+			Protocol:   ProtocolHTTP,
+			Method:     "GET",
+			Path:       fmt.Sprintf("/%d", i),
+			Status:     200 + i%2*300,
+		}))
+	}
+
+	// Only the current file and one backup are kept
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	entries, err := log.Query("web:80", Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Less(t, len(entries), 10)
+	assert.Equal(t, "/9", entries[len(entries)-1].Path)
+	for i := 1; i < len(entries); i++ {
+		assert.True(t, entries[i].Time.After(entries[i-1].Time), "entries are oldest first")
+	}
+
+	entries, err = log.Query("web:80", Filter{Status: "5xx", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/9", entries[0].Path)
+
+	entries, err = log.Query("web:80", Filter{Since: start.Add(8 * time.Minute), Status: "200"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "/8", entries[0].Path)
+
+	entries, err = log.Query("api:8080", Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, status := range []string{"404", "5xx", "2XX"} {
+		assert.NoError(t, ParseStatus(status), status)
+	}
+	for _, status := range []string{"", "40", "6xx", "abc", "4x4"} {
+		assert.Error(t, ParseStatus(status), status)
+	}
+}
+
+func TestDialerHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "got %d bytes", len(body))
+	}))
+	defer server.Close()
+
+	log := NewLog(t.TempDir(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	history, follow, err := log.Follow(ctx, "web:80", Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	conn, err := log.Dialer("web:80", Tap{HTTP: true}).DialContext(ctx, "tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// Two requests on one connection, as ngrok forwards them
+	br := bufio.NewReader(conn)
+	_, err = io.WriteString(conn, "POST /upload?token=secret HTTP/1.1\r\nHost: web\r\nX-Forwarded-For: 10.0.0.1, 203.0.113.7\r\nContent-Length: 5\r\n\r\nhello")
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	_, err = io.WriteString(conn, "GET /missing HTTP/1.1\r\nHost: web\r\n\r\n")
+	require.NoError(t, err)
+	resp, err = http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	var entries []Entry
+	for len(entries) < 2 {
+		select {
+		case entry := <-follow:
+			entries = append(entries, entry)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for access log entries")
+		}
+	}
+	require.NoError(t, conn.Close())
+
+	assert.Equal(t, "POST", entries[0].Method)
+	assert.Equal(t, "/upload", entries[0].Path, "query strings are not recorded")
+	assert.Equal(t, "203.0.113.7", entries[0].RemoteAddr)
+	assert.Equal(t, http.StatusOK, entries[0].Status)
+	assert.Equal(t, int64(5), entries[0].BytesIn)
+	assert.Equal(t, int64(len("got 5 bytes")), entries[0].BytesOut)
+	assert.Equal(t, "/missing", entries[1].Path)
+	assert.Equal(t, http.StatusNotFound, entries[1].Status)
+
+	stored, err := log.Query("web:80", Filter{Method: "get"})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "web:80", stored[0].EndpointID)
+}
+
+func TestDialerTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		_, _ = br.ReadString('\n') // PROXY protocol header
+		line, _ := br.ReadString('\n')
+		_, _ = io.WriteString(conn, strings.ToUpper(line))
+	}()
+
+	log := NewLog(t.TempDir(), nil)
+	conn, err := log.Dialer("db:5432", Tap{ProxyProtocol: true}).Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = io.WriteString(conn, "PROXY TCP4 198.51.100.4 172.17.0.1 40000 5432\r\n")
+	require.NoError(t, err)
+	_, err = io.WriteString(conn, "ping\n")
+	require.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	// The SDK closes upstream connections from both copying goroutines
+	_ = conn.Close()
+
+	entries, err := log.Query("db:5432", Filter{RemoteAddr: "198.51.100.4"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ProtocolTCP, entries[0].Protocol)
+	assert.Equal(t, int64(len("PROXY TCP4 198.51.100.4 172.17.0.1 40000 5432\r\nping\n")), entries[0].BytesIn)
+	assert.Equal(t, int64(len("PING\n")), entries[0].BytesOut)
+
+	// Unreachable upstreams are recorded too
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+	_, err = log.Dialer("db:5432", Tap{}).Dial("tcp", closed.Addr().String())
+	require.Error(t, err)
+	entries, err = log.Query("db:5432", Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.NotEmpty(t, entries[1].Error)
+}
+
+func TestProxyHeaderSource(t *testing.T) {
+	v2 := append([]byte{}, proxyHeaderSignature...)
+	v2 = append(v2, 0x21, 0x11, 0, 12, 198, 51, 100, 4, 172, 17, 0, 1, 0x9c, 0x40, 0x15, 0x38)
+
+	assert.Equal(t, "198.51.100.4", proxyHeaderSource(v2))
+	assert.Equal(t, "2001:db8::1", proxyHeaderSource([]byte("PROXY TCP6 2001:db8::1 ::1 40000 5432\r\n")))
+	assert.Empty(t, proxyHeaderSource([]byte("GET / HTTP/1.1\r\n")))
+	assert.Empty(t, proxyHeaderSource([]byte("PROXY UNKNOWN\r\n")))
+}
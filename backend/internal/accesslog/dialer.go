@@ -0,0 +1,339 @@
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dialTimeout matches the timeout the ngrok SDK dials upstreams with
+const dialTimeout = 3 * time.Second
+
+// tapBuffer is how many reads or writes a connection may be ahead of its
+// parser before it stops being parsed. Forwarding never waits on the log.
+const tapBuffer = 256
+
+// errStopped stops a parser
+var errStopped = errors.New("access log parser stopped")
+
+// Tap configures what a Dialer records of each connection
+type Tap struct {
+	// HTTP records an entry per request. The upstream must speak plain HTTP:
+	// connections the SDK encrypts are recorded as a whole.
+	HTTP bool
+	// ProxyProtocol reads the client's address from the PROXY protocol
+	// header sent ahead of the connection
+	ProxyProtocol bool
+}
+
+// Dialer dials the upstream of an endpoint, recording every connection it
+// forwards in the log. It implements the ngrok SDK's upstream Dialer.
+type Dialer struct {
+	log        *Log
+	endpointID string
+	tap        Tap
+	dialer     net.Dialer
+}
+
+// Dialer returns a dialer recording the connections to an endpoint's upstream
+func (l *Log) Dialer(endpointID string, tap Tap) *Dialer {
+	return &Dialer{
+		log:        l,
+		endpointID: endpointID,
+		tap:        tap,
+		dialer:     net.Dialer{Timeout: dialTimeout},
+	}
+}
+
+// Dial connects to address
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to address, recording the connection once it closes,
+// or the error if the upstream cannot be reached
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	started := time.Now()
+	upstream, err := d.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		d.record(Entry{
+			Time:       started,
+			Protocol:   d.protocol(),
+			DurationMs: time.Since(started).Milliseconds(),
+			Error:      err.Error(),
+		})
+		return nil, err
+	}
+
+	c := &conn{Conn: upstream, dialer: d, started: started}
+	if d.tap.HTTP {
+		c.pending = make(chan *request, tapBuffer)
+		c.done = make(chan struct{})
+		c.requests = newStream(c.parseRequests)
+		c.responses = newStream(c.parseResponses)
+	}
+	return c, nil
+}
+
+func (d *Dialer) protocol() string {
+	if d.tap.HTTP {
+		return ProtocolHTTP
+	}
+	return ProtocolTCP
+}
+
+func (d *Dialer) record(entry Entry) {
+	entry.EndpointID = d.endpointID
+	if err := d.log.Record(entry); err != nil && d.log.logger != nil {
+		d.log.logger.Warn("Failed to record access log entry", "endpoint", d.endpointID, "error", err)
+	}
+}
+
+// conn is an upstream connection. Writes carry the client's bytes to the
+// upstream and reads the upstream's bytes back.
+type conn struct {
+	net.Conn
+	dialer     *Dialer
+	started    time.Time
+	bytesIn    atomic.Int64
+	bytesOut   atomic.Int64
+	remoteAddr atomic.Value // string
+	sawHeader  bool         // only accessed by the writing goroutine
+	closeOnce  sync.Once
+
+	// Set for HTTP
+	requests  *stream
+	responses *stream
+	pending   chan *request // requests waiting for their response
+	done      chan struct{} // closed once responses are no longer parsed
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.bytesIn.Add(int64(n))
+	if c.dialer.tap.ProxyProtocol && !c.sawHeader && n > 0 {
+		c.sawHeader = true
+		c.remoteAddr.Store(proxyHeaderSource(p[:n]))
+	}
+	if c.requests != nil {
+		c.requests.feed(p[:n])
+	}
+	return n, err
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.bytesOut.Add(int64(n))
+	if c.responses != nil {
+		c.responses.feed(p[:n])
+	}
+	return n, err
+}
+
+func (c *conn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() {
+		if c.requests != nil {
+			c.requests.close()
+			c.responses.close()
+			return
+		}
+		remoteAddr, _ := c.remoteAddr.Load().(string)
+		c.dialer.record(Entry{
+			Time:       c.started,
+			Protocol:   ProtocolTCP,
+			RemoteAddr: remoteAddr,
+			BytesIn:    c.bytesIn.Load(),
+			BytesOut:   c.bytesOut.Load(),
+			DurationMs: time.Since(c.started).Milliseconds(),
+		})
+	})
+	return err
+}
+
+// request is a request parsed from the client's bytes
+type request struct {
+	started    time.Time
+	remoteAddr string
+	method     string
+	path       string
+	bytesIn    atomic.Int64
+}
+
+// parseRequests reads the requests sent to the upstream
+func (c *conn) parseRequests(r *io.PipeReader) {
+	defer close(c.pending)
+	defer r.CloseWithError(errStopped)
+
+	br := bufio.NewReader(r)
+	for {
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		pending := &request{
+			started:    time.Now(),
+			remoteAddr: forwardedClient(req.Header),
+			method:     req.Method,
+			path:       req.URL.Path,
+		}
+		select {
+		case c.pending <- pending:
+		case <-c.done:
+			return
+		}
+		n, err := io.Copy(io.Discard, req.Body)
+		pending.bytesIn.Store(n)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseResponses reads the upstream's responses, recording an entry for each
+// request once its response completes
+func (c *conn) parseResponses(r *io.PipeReader) {
+	defer close(c.done)
+	defer r.CloseWithError(errStopped)
+
+	br := bufio.NewReader(r)
+	for req := range c.pending {
+		resp, err := http.ReadResponse(br, &http.Request{Method: req.method})
+		// Interim responses precede the final one
+		for err == nil && resp.StatusCode >= 100 && resp.StatusCode < 200 && resp.StatusCode != http.StatusSwitchingProtocols {
+			resp, err = http.ReadResponse(br, &http.Request{Method: req.method})
+		}
+
+		entry := Entry{
+			Time:       req.started,
+			Protocol:   ProtocolHTTP,
+			RemoteAddr: req.remoteAddr,
+			Method:     req.method,
+			Path:       req.path,
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				entry.BytesIn = req.bytesIn.Load()
+				entry.DurationMs = time.Since(req.started).Milliseconds()
+				entry.Error = "upstream closed the connection without a response"
+				c.dialer.record(entry)
+			}
+			return
+		}
+		entry.Status = resp.StatusCode
+		entry.BytesOut, err = io.Copy(io.Discard, resp.Body)
+		entry.BytesIn = req.bytesIn.Load()
+		entry.DurationMs = time.Since(req.started).Milliseconds()
+		c.dialer.record(entry)
+
+		// Upgraded connections no longer carry HTTP
+		if err != nil || resp.StatusCode == http.StatusSwitchingProtocols {
+			return
+		}
+	}
+}
+
+// stream copies the bytes of one direction of a connection to a parser
+// without ever blocking the connection: if the parser falls behind, it stops
+type stream struct {
+	mu     sync.Mutex
+	ch     chan []byte
+	closed bool
+}
+
+func newStream(parse func(*io.PipeReader)) *stream {
+	r, w := io.Pipe()
+	s := &stream{ch: make(chan []byte, tapBuffer)}
+	go parse(r)
+	go func() {
+		defer w.Close()
+		for p := range s.ch {
+			// Once the parser stops, writes fail and the rest is discarded
+			_, _ = w.Write(p)
+		}
+	}()
+	return s
+}
+
+func (s *stream) feed(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- bytes.Clone(p):
+	default:
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+func (s *stream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// forwardedClient returns the client's address from the headers ngrok adds
+// to requests: the last X-Forwarded-For entry, or X-Real-IP
+func forwardedClient(header http.Header) string {
+	if values := header.Values("X-Forwarded-For"); len(values) > 0 {
+		last := values[len(values)-1]
+		if i := strings.LastIndex(last, ","); i >= 0 {
+			last = last[i+1:]
+		}
+		return strings.TrimSpace(last)
+	}
+	return header.Get("X-Real-IP")
+}
+
+// proxyHeaderSignature starts PROXY protocol v2 headers
+var proxyHeaderSignature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// proxyHeaderSource returns the source address of the PROXY protocol header
+// at the start of p, or "" if there is none
+func proxyHeaderSource(p []byte) string {
+	// v1: PROXY TCP4 <source> <destination> <source port> <destination port>\r\n
+	if line, _, ok := bytes.Cut(p, []byte("\r\n")); ok && bytes.HasPrefix(line, []byte("PROXY ")) {
+		if fields := strings.Fields(string(line)); len(fields) == 6 {
+			return fields[2]
+		}
+		return ""
+	}
+
+	// v2: signature, version and command, family, length, addresses
+	if !bytes.HasPrefix(p, proxyHeaderSignature) || len(p) < 16 {
+		return ""
+	}
+	addresses := p[16:]
+	if length := int(binary.BigEndian.Uint16(p[14:16])); len(addresses) > length {
+		addresses = addresses[:length]
+	}
+	switch p[13] >> 4 {
+	case 1: // IPv4
+		if len(addresses) >= 4 {
+			return net.IP(addresses[:4]).String()
+		}
+	case 2: // IPv6
+		if len(addresses) >= 16 {
+			return net.IP(addresses[:16]).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+// Package apiauth authenticates requests to the backend API and attaches the
+// caller's identity to the request context.
+package apiauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	// ClientHeader lets callers name themselves when no API tokens are
+	// configured, e.g. "docker-desktop-ui" or "ngrok-ext-cli"
+	ClientHeader = "X-Ngrok-Ext-Client"
+
+	// AnonymousIdentity is used when auth is disabled and the caller did
+	// not name itself
+	AnonymousIdentity = "anonymous"
+
+	// defaultTokenName is the identity of a token configured without a name
+	defaultTokenName = "api"
+
+	identityKey = "apiauth.identity"
+)
+
+// Tokens maps API tokens to the identity they authenticate
+type Tokens map[string]string
+
+// ParseTokens parses a comma separated list of name=token pairs. A token
+// without a name authenticates as "api".
+func ParseTokens(s string) Tokens {
+	tokens := make(Tokens)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, token, found := strings.Cut(entry, "=")
+		if !found {
+			name, token = defaultTokenName, entry
+		}
+		tokens[strings.TrimSpace(token)] = strings.TrimSpace(name)
+	}
+	return tokens
+}
+
+// lookup returns the identity for token using constant time comparisons
+func (t Tokens) lookup(token string) (string, bool) {
+	identity, found := "", false
+	for candidate, name := range t {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			identity, found = name, true
+		}
+	}
+	return identity, found
+}
+
+// Middleware requires a valid "Authorization: Bearer <token>" header when
+// tokens is non-empty. Without tokens every request is allowed and the
+// identity comes from ClientHeader.
+func Middleware(tokens Tokens, skipper middleware.Skipper) echo.MiddlewareFunc {
+	if skipper == nil {
+		skipper = middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(tokens) == 0 {
+				identity := strings.TrimSpace(c.Request().Header.Get(ClientHeader))
+				if identity == "" {
+					identity = AnonymousIdentity
+				}
+				c.Set(identityKey, identity)
+				return next(c)
+			}
+
+			if skipper(c) {
+				c.Set(identityKey, AnonymousIdentity)
+				return next(c)
+			}
+
+			scheme, token, _ := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="ngrok-docker-extension"`)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "API token required"})
+			}
+			identity, ok := tokens.lookup(strings.TrimSpace(token))
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid API token"})
+			}
+
+			c.Set(identityKey, identity)
+			return next(c)
+		}
+	}
+}
+
+// Identity returns the identity of the caller, or AnonymousIdentity if the
+// request did not pass through Middleware
+func Identity(c echo.Context) string {
+	if identity, ok := c.Get(identityKey).(string); ok {
+		return identity
+	}
+	return AnonymousIdentity
+}
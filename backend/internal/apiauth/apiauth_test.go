@@ -0,0 +1,85 @@
+package apiauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokens(t *testing.T) {
+	assert.Equal(t, Tokens{"t1": "ui", "t2": "cli"}, ParseTokens("ui=t1, cli=t2"))
+	assert.Equal(t, Tokens{"secret": "api"}, ParseTokens("secret"))
+	assert.Empty(t, ParseTokens(""))
+	assert.Empty(t, ParseTokens(" , "))
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		tokens           Tokens
+		headers          map[string]string
+		expectedCode     int
+		expectedIdentity string
+	}{
+		{
+			name:             "no_tokens_anonymous",
+			expectedCode:     http.StatusOK,
+			expectedIdentity: AnonymousIdentity,
+		},
+		{
+			name:             "no_tokens_client_header",
+			headers:          map[string]string{ClientHeader: "ngrok-ext-cli"},
+			expectedCode:     http.StatusOK,
+			expectedIdentity: "ngrok-ext-cli",
+		},
+		{
+			name:         "token_required",
+			tokens:       Tokens{"secret": "ui"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "wrong_token",
+			tokens:       Tokens{"secret": "ui"},
+			headers:      map[string]string{echo.HeaderAuthorization: "Bearer guess"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "wrong_scheme",
+			tokens:       Tokens{"secret": "ui"},
+			headers:      map[string]string{echo.HeaderAuthorization: "Basic secret"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:             "valid_token",
+			tokens:           Tokens{"secret": "ui"},
+			headers:          map[string]string{echo.HeaderAuthorization: "Bearer secret", ClientHeader: "spoofed"},
+			expectedCode:     http.StatusOK,
+			expectedIdentity: "ui",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(Middleware(tt.tokens, nil))
+			var identity string
+			e.GET("/", func(c echo.Context) error {
+				identity = Identity(c)
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedIdentity, identity)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package audit keeps an append-only record of every mutating API call: who
+// made it, when, and how it changed the persisted configuration.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Entry records one mutating API call
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Identity  string    `json:"identity"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	Endpoints []string  `json:"endpoints,omitempty"` // IDs of the endpoints that changed
+	Changes   []Change  `json:"changes,omitempty"`
+}
+
+// Change is a single field that differs between the configuration before and
+// after a call. Secrets are redacted.
+type Change struct {
+	Resource string `json:"resource"` // "agent" | "endpoint"
+	ID       string `json:"id,omitempty"`
+	Field    string `json:"field"`
+	Before   any    `json:"before,omitempty"`
+	After    any    `json:"after,omitempty"`
+}
+
+// Filter selects entries from the log. Zero values match everything.
+type Filter struct {
+	Since      time.Time
+	Until      time.Time
+	Identity   string
+	EndpointID string
+	Limit      int // keep only the most recent entries
+}
+
+// matches reports whether entry is selected by f
+func (f Filter) matches(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	if f.Identity != "" && entry.Identity != f.Identity {
+		return false
+	}
+	if f.EndpointID != "" && !slices.Contains(entry.Endpoints, f.EndpointID) {
+		return false
+	}
+	return true
+}
+
+// Log is an append-only audit log stored as newline delimited JSON
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog creates a log that appends to the file at path
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append writes entry to the end of the log
+func (l *Log) Append(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Query returns the entries selected by filter, oldest first
+func (l *Log) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Entry{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip a line torn by a crash rather than failing the query
+			continue
+		}
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestLog_AppendAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	log := NewLog(path)
+
+	// An empty log has no entries
+	entries, err := log.Query(Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, entry := range []Entry{
+		{Identity: "ui", Endpoints: []string{"web:80"}},
+		{Identity: "cli", Endpoints: []string{"db:5432"}},
+		{Identity: "ui", Endpoints: []string{"web:80", "db:5432"}},
+	} {
+		entry.Time = start.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, log.Append(entry))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		identities []string
+	}{
+		{"all", Filter{}, []string{"ui", "cli", "ui"}},
+		{"identity", Filter{Identity: "cli"}, []string{"cli"}},
+		{"endpoint", Filter{EndpointID: "db:5432"}, []string{"cli", "ui"}},
+		{"since", Filter{Since: start.Add(time.Hour)}, []string{"cli", "ui"}},
+		{"until", Filter{Until: start.Add(time.Hour)}, []string{"ui", "cli"}},
+		{"limit keeps most recent", Filter{Limit: 1}, []string{"ui"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := log.Query(tt.filter)
+			require.NoError(t, err)
+			var identities []string
+			for _, entry := range entries {
+				identities = append(identities, entry.Identity)
+			}
+			assert.Equal(t, tt.identities, identities)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := &store.State{
+		AgentConfig: store.AgentConfig{AuthToken: "ngrok_old", ExpectedState: "online", ResourceVersion: 1},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80":  {ID: "web:80", ContainerID: "web", TargetPort: "80", Description: "old", ResourceVersion: 2},
+			"db:5432": {ID: "db:5432", ContainerID: "db", TargetPort: "5432"},
+		},
+	}
+	after := &store.State{
+		AgentConfig: store.AgentConfig{AuthToken: "ngrok_new", ExpectedState: "online", ResourceVersion: 3},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", Description: "new", ResourceVersion: 4},
+		},
+	}
+
+	changes := Diff(before, after)
+	assert.Equal(t, []Change{
+		{Resource: "agent", Field: "authToken", Before: redacted, After: redacted},
+		{Resource: "endpoint", ID: "db:5432", Field: "containerId", Before: "db"},
+		{Resource: "endpoint", ID: "db:5432", Field: "id", Before: "db:5432"},
+		{Resource: "endpoint", ID: "db:5432", Field: "targetPort", Before: "5432"},
+		{Resource: "endpoint", ID: "web:80", Field: "description", Before: "old", After: "new"},
+	}, changes)
+	assert.Equal(t, []string{"db:5432", "web:80"}, ChangedEndpoints(changes))
+
+	assert.Empty(t, Diff(after, after))
+}
+
+func TestDiff_RedactsCredentials(t *testing.T) {
+	policy := "on_http_request:\n  - actions:\n      - type: basic-auth\n        config:\n          credentials: ['admin:hunter2']\n      - type: oauth\n        config:\n          provider: google\n          client_id: id\n          client_secret: s3cret\n"
+	after := &store.State{
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", TrafficPolicy: policy, Metadata: `{"apiKey":"k3y"}`},
+		},
+	}
+
+	changes := Diff(nil, after)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "metadata", changes[1].Field)
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, changes[1].After)
+	assert.Equal(t, "trafficPolicy", changes[2].Field)
+	assert.JSONEq(t, `{"on_http_request": [{"actions": [
+		{"type": "basic-auth", "config": {"credentials": "[REDACTED]"}},
+		{"type": "oauth", "config": {"provider": "google", "client_id": "id", "client_secret": "[REDACTED]"}}
+	]}]}`, changes[2].After.(string))
+
+	// A policy that does not parse is hashed as a whole
+	after.EndpointConfigs["web:80"] = store.EndpointConfig{ID: "web:80", TrafficPolicy: "credentials: ['admin:hunter2'"}
+	changes = Diff(nil, after)
+	require.Len(t, changes, 2)
+	assert.Regexp(t, `^sha256:`, changes[1].After)
+}
+
+func TestMiddleware_ConcurrentRequestsKeepTheirChanges(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "audit.log"))
+	st := store.NewMemoryStore(nil)
+	e := echo.New()
+	e.Use(Middleware(log, slog.Default()))
+
+	// A request that hangs after changing the state, e.g. in a converge,
+	// does not hold up other requests
+	entered, release := make(chan struct{}), make(chan struct{})
+	e.POST("/endpoints", func(c echo.Context) error {
+		err := Update(c, st, func(state *store.State) error {
+			state.EndpointConfigs["web:80"] = store.EndpointConfig{ID: "web:80", ContainerID: "web", TargetPort: "80"}
+			return nil
+		})
+		close(entered)
+		<-release
+		return err
+	})
+	e.PUT("/agent", func(c echo.Context) error {
+		return Update(c, st, func(state *store.State) error {
+			state.AgentConfig.ExpectedState = "online"
+			return nil
+		})
+	})
+	e.POST("/probe", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/endpoints", nil))
+		close(done)
+	}()
+	<-entered
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPut, "/agent", nil),
+		httptest.NewRequest(http.MethodPost, "/probe", nil),
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	close(release)
+	<-done
+
+	entries, err := log.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	byPath := map[string]Entry{}
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	assert.Equal(t, []Change{{Resource: "agent", Field: "expectedState", After: "online"}}, byPath["/agent"].Changes)
+	assert.Empty(t, byPath["/probe"].Changes)
+	assert.Equal(t, []string{"web:80"}, byPath["/endpoints"].Endpoints)
+	for _, change := range byPath["/endpoints"].Changes {
+		assert.Equal(t, "endpoint", change.Resource)
+	}
+}
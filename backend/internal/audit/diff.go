@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// redacted replaces the value of secret fields in changes
+const redacted = "[REDACTED]"
+
+// secretFields are never written to the audit log
+var secretFields = map[string]bool{
+	"authToken": true,
+}
+
+// hashedFields may carry credentials and are only written as a hash, so that
+// auditors can still tell values apart
+var hashedFields = map[string]bool{
+	"metadata": true,
+}
+
+// policySecretKeys are the traffic policy action config keys whose values are
+// credentials, e.g. basic-auth credentials and OAuth client secrets
+var policySecretKeys = map[string]bool{
+	"credentials":   true,
+	"client_secret": true,
+	"secret":        true,
+	"password":      true,
+	"token":         true,
+	"api_key":       true,
+	"private_key":   true,
+}
+
+// ignoredFields change on every write and carry no information for auditors
+var ignoredFields = map[string]bool{
+	"resourceVersion": true,
+}
+
+// Diff returns the field level changes between two states. Either state may
+// be nil.
+func Diff(before, after *store.State) []Change {
+	var changes []Change
+
+	var beforeAgent, afterAgent *store.AgentConfig
+	if before != nil {
+		beforeAgent = &before.AgentConfig
+	}
+	if after != nil {
+		afterAgent = &after.AgentConfig
+	}
+	changes = append(changes, diffResource("agent", "", beforeAgent, afterAgent)...)
+
+	beforeEndpoints := endpointConfigs(before)
+	afterEndpoints := endpointConfigs(after)
+	ids := slices.Sorted(maps.Keys(beforeEndpoints))
+	for id := range afterEndpoints {
+		if _, exists := beforeEndpoints[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		var b, a *store.EndpointConfig
+		if config, exists := beforeEndpoints[id]; exists {
+			b = &config
+		}
+		if config, exists := afterEndpoints[id]; exists {
+			a = &config
+		}
+		changes = append(changes, diffResource("endpoint", id, b, a)...)
+	}
+
+	return changes
+}
+
+// ChangedEndpoints returns the IDs of the endpoints touched by changes
+func ChangedEndpoints(changes []Change) []string {
+	var ids []string
+	for _, change := range changes {
+		if change.Resource == "endpoint" && !slices.Contains(ids, change.ID) {
+			ids = append(ids, change.ID)
+		}
+	}
+	return ids
+}
+
+func endpointConfigs(state *store.State) map[string]store.EndpointConfig {
+	if state == nil {
+		return nil
+	}
+	return state.EndpointConfigs
+}
+
+// diffResource compares the JSON fields of two versions of a resource. A nil
+// pointer means the resource does not exist.
+func diffResource[T any](resource, id string, before, after *T) []Change {
+	beforeFields := fields(before)
+	afterFields := fields(after)
+
+	names := slices.Sorted(maps.Keys(beforeFields))
+	for name := range afterFields {
+		if _, exists := beforeFields[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []Change
+	for _, name := range slices.Compact(names) {
+		if ignoredFields[name] {
+			continue
+		}
+		b, a := beforeFields[name], afterFields[name]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		b, a = redactField(name, b), redactField(name, a)
+		changes = append(changes, Change{
+			Resource: resource,
+			ID:       id,
+			Field:    name,
+			Before:   b,
+			After:    a,
+		})
+	}
+	return changes
+}
+
+// fields returns the JSON object fields of v, dropping zero values so that
+// a missing resource and an empty field compare equal
+func fields[T any](v *T) map[string]any {
+	result := make(map[string]any)
+	if v == nil {
+		return result
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return result
+	}
+	for name, value := range raw {
+		if value == nil || value == "" || value == false || value == float64(0) {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// redactField hides the credentials a field's value may hold
+func redactField(name string, v any) any {
+	switch {
+	case v == nil:
+		return nil
+	case secretFields[name]:
+		return redactValue(v)
+	case hashedFields[name]:
+		return hashValue(v)
+	case name == "trafficPolicy":
+		return redactPolicy(v)
+	}
+	return v
+}
+
+// redactPolicy hides the credentials in a traffic policy. A policy that does
+// not parse is hashed as a whole.
+func redactPolicy(v any) any {
+	policy, ok := v.(string)
+	if !ok {
+		return hashValue(v)
+	}
+	var document any
+	if err := yaml.Unmarshal([]byte(policy), &document); err != nil {
+		return hashValue(v)
+	}
+	data, err := json.Marshal(redactPolicyNode(document))
+	if err != nil {
+		return hashValue(v)
+	}
+	return string(data)
+}
+
+func redactPolicyNode(node any) any {
+	switch node := node.(type) {
+	case map[string]any:
+		for key, value := range node {
+			if policySecretKeys[key] {
+				node[key] = redacted
+				continue
+			}
+			node[key] = redactPolicyNode(value)
+		}
+	case []any:
+		for i, value := range node {
+			node[i] = redactPolicyNode(value)
+		}
+	}
+	return node
+}
+
+// hashValue replaces a value with a short hash of it
+func hashValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// redactValue hides a secret while still recording whether it was set
+func redactValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	return redacted
+}
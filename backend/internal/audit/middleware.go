@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/apiauth"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// changesKey keys the changes a request made in its echo context
+const changesKey = "audit.changes"
+
+// Middleware records every mutating request in log, along with the changes
+// it made to the state through Update. It must run after apiauth.Middleware
+// so that the caller's identity is known.
+func Middleware(log *Log, logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !isMutating(c.Request().Method) {
+				return next(c)
+			}
+
+			var changes []Change
+			c.Set(changesKey, &changes)
+
+			err := next(c)
+			if err != nil {
+				// Let echo write the error response so its status is recorded
+				c.Error(err)
+			}
+
+			entry := Entry{
+				Time:      time.Now(),
+				Identity:  apiauth.Identity(c),
+				Method:    c.Request().Method,
+				Path:      c.Request().URL.Path,
+				Status:    c.Response().Status,
+				Changes:   changes,
+				Endpoints: ChangedEndpoints(changes),
+			}
+			if err := log.Append(entry); err != nil {
+				logger.Error("audit: failed to append entry", "error", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// Update runs fn in a transaction of st, like st.Update, and records the
+// changes it made for the audit entry of the request c. The changes are
+// taken from the transaction itself, so concurrent requests never see each
+// other's changes and need not wait for each other.
+func Update(c echo.Context, st store.Store, fn func(*store.State) error) error {
+	var changes []Change
+	err := st.Update(func(state *store.State) error {
+		before, err := state.DeepCopy()
+		if err != nil {
+			return err
+		}
+		if err := fn(state); err != nil {
+			return err
+		}
+		changes = Diff(before, state)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if recorded, ok := c.Get(changesKey).(*[]Change); ok {
+		*recorded = append(*recorded, changes...)
+	}
+	return nil
+}
+
+// isMutating reports whether requests with method may change state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+// Package containers builds the inventory of Docker containers served to the
+// UI: their ports, health and networks, the endpoints attached to them, and the
+// protocols detected on their published ports.
+package containers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Labels set by docker compose
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// Container health values
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthStarting  = "starting"
+	HealthNone      = "none" // the container has no healthcheck
+)
+
+// Container is one entry of the inventory
+type Container struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Image          string   `json:"image"`
+	State          string   `json:"state"`  // e.g. "running", "exited"
+	Status         string   `json:"status"` // e.g. "Up 5 minutes (healthy)"
+	Health         string   `json:"health"`
+	ComposeProject string   `json:"composeProject,omitempty"`
+	ComposeService string   `json:"composeService,omitempty"`
+	Networks       []string `json:"networks"`
+	Ports          []Port   `json:"ports"`
+	Endpoints      []string `json:"endpoints"` // IDs of the endpoints configured for the container
+}
+
+// Port is a port exposed by a container, published on the host or not
+type Port struct {
+	PrivatePort uint16     `json:"privatePort"`
+	PublicPort  uint16     `json:"publicPort,omitempty"` // 0 if the port is only exposed
+	Protocol    string     `json:"protocol"`             // "tcp" | "udp" | "sctp"
+	EndpointID  string     `json:"endpointId,omitempty"` // endpoint forwarding to this port
+	Detected    *Detection `json:"detected,omitempty"`   // published TCP ports only
+}
+
+// Detection is a cached protocol detection result
+type Detection struct {
+	TCP        bool      `json:"tcp"`
+	HTTP       bool      `json:"http"`
+	HTTPS      bool      `json:"https"`
+	TLS        bool      `json:"tls"`
+	Error      string    `json:"error,omitempty"` // why the port could not be reached
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// Lister builds the container inventory
+type Lister struct {
+	Docker   manager.DockerClient
+	Detector manager.ProtocolDetector
+	Store    store.Store
+
+	// BridgeHost is the address where published ports are reachable
+	BridgeHost string
+}
+
+// NewLister creates a Lister that probes ports through the Docker bridge
+// gateway
+func NewLister(docker manager.DockerClient, detector manager.ProtocolDetector, st store.Store) *Lister {
+	return &Lister{
+		Docker:     docker,
+		Detector:   detector,
+		Store:      st,
+		BridgeHost: manager.DockerBridgeHost,
+	}
+}
+
+// List returns the containers sorted by name. Stopped containers are
+// included if all is set.
+func (l *Lister) List(ctx context.Context, all bool) ([]Container, error) {
+	summaries, err := l.Docker.ContainerList(ctx, container.ListOptions{All: all})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	state, err := l.Store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	containers := make([]Container, 0, len(summaries))
+	for _, summary := range summaries {
+		containers = append(containers, newContainer(summary, state.EndpointConfigs))
+	}
+	slices.SortFunc(containers, func(a, b Container) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	l.detectPorts(ctx, containers)
+	return containers, nil
+}
+
+// newContainer converts a Docker container summary
+func newContainer(summary container.Summary, endpoints map[string]store.EndpointConfig) Container {
+	c := Container{
+		ID:             summary.ID,
+		Image:          summary.Image,
+		State:          string(summary.State),
+		Status:         summary.Status,
+		Health:         health(summary.Status),
+		ComposeProject: summary.Labels[composeProjectLabel],
+		ComposeService: summary.Labels[composeServiceLabel],
+		Networks:       []string{},
+		Ports:          []Port{},
+		Endpoints:      []string{},
+	}
+	if len(summary.Names) > 0 {
+		c.Name = strings.TrimPrefix(summary.Names[0], "/")
+	}
+	if summary.NetworkSettings != nil {
+		c.Networks = slices.Sorted(maps.Keys(summary.NetworkSettings.Networks))
+	}
+
+	// Docker lists a published port once per host address family
+	seen := make(map[Port]bool)
+	for _, p := range summary.Ports {
+		port := Port{PrivatePort: p.PrivatePort, PublicPort: p.PublicPort, Protocol: p.Type}
+		if seen[port] {
+			continue
+		}
+		seen[port] = true
+
+		if port.PublicPort != 0 {
+			id := summary.ID + ":" + strconv.Itoa(int(port.PublicPort))
+			if _, exists := endpoints[id]; exists {
+				port.EndpointID = id
+			}
+		}
+		c.Ports = append(c.Ports, port)
+	}
+	slices.SortFunc(c.Ports, func(a, b Port) int {
+		return cmp.Or(cmp.Compare(a.PrivatePort, b.PrivatePort), cmp.Compare(a.PublicPort, b.PublicPort), cmp.Compare(a.Protocol, b.Protocol))
+	})
+
+	for _, id := range slices.Sorted(maps.Keys(endpoints)) {
+		if endpoints[id].ContainerID == summary.ID {
+			c.Endpoints = append(c.Endpoints, id)
+		}
+	}
+	return c
+}
+
+// health extracts the healthcheck state from a container status such as
+// "Up 5 minutes (healthy)"
+func health(status string) string {
+	switch {
+	case strings.Contains(status, "(healthy)"):
+		return HealthHealthy
+	case strings.Contains(status, "(unhealthy)"):
+		return HealthUnhealthy
+	case strings.Contains(status, "(health: starting)"):
+		return HealthStarting
+	}
+	return HealthNone
+}
+
+// detectPorts fills in the detection results of published TCP ports of
+// running containers
+func (l *Lister) detectPorts(ctx context.Context, containers []Container) {
+	var wg sync.WaitGroup
+	for i := range containers {
+		if containers[i].State != string(container.StateRunning) {
+			continue
+		}
+		for j := range containers[i].Ports {
+			port := &containers[i].Ports[j]
+			if port.PublicPort == 0 || port.Protocol != "tcp" {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				port.Detected = l.detect(ctx, containers[i].ID, l.BridgeHost, strconv.Itoa(int(port.PublicPort)))
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// detect returns the detection result for a port of a container, which the
+// detector caches, or nil if the port could not be probed
+func (l *Lister) detect(ctx context.Context, containerID, host, port string) *Detection {
+	result, err := l.Detector.DetectContainer(ctx, containerID, host, port)
+	if err != nil {
+		return nil
+	}
+	return &Detection{
+		TCP:        result.TCP,
+		HTTP:       result.HTTP,
+		HTTPS:      result.HTTPS,
+		TLS:        result.TLS,
+		Error:      result.Error,
+		DetectedAt: result.DetectedAt,
+	}
+}
@@ -0,0 +1,51 @@
+package containers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+)
+
+func TestHealth(t *testing.T) {
+	assert.Equal(t, HealthHealthy, health("Up 5 minutes (healthy)"))
+	assert.Equal(t, HealthUnhealthy, health("Up 5 minutes (unhealthy)"))
+	assert.Equal(t, HealthStarting, health("Up 2 seconds (health: starting)"))
+	assert.Equal(t, HealthNone, health("Up 5 minutes"))
+}
+
+func TestIsLoopback(t *testing.T) {
+	assert.True(t, isLoopback("0100007F"))
+	assert.True(t, isLoopback("00000000000000000000000001000000"))
+	assert.True(t, isLoopback("0000000000000000FFFF00000100007F"))
+	assert.False(t, isLoopback("00000000"))
+	assert.False(t, isLoopback("050011AC"))
+	assert.False(t, isLoopback("00000000000000000000000000000000"))
+}
+
+func TestScanListening_ExitCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	docker := mocks.NewMockDockerClient(ctrl)
+	l := &Lister{Docker: docker}
+	cmd := []string{"cat", "/proc/net/tcp", "/proc/net/tcp6"}
+
+	// Without IPv6, cat fails after printing /proc/net/tcp
+	docker.EXPECT().ContainerExec(gomock.Any(), "web", cmd).Return([]byte(
+		"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+			"   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n"),
+		&manager.ExecExitError{ExitCode: 1, Stderr: "cat: /proc/net/tcp6: No such file or directory"})
+	ports, err := l.scanListening(context.Background(), "web")
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{80}, ports)
+
+	// Without cat, there is nothing to parse
+	docker.EXPECT().ContainerExec(gomock.Any(), "scratch", cmd).Return(nil,
+		&manager.ExecExitError{ExitCode: 127, Stderr: "exec: \"cat\": executable file not found"})
+	_, err = l.scanListening(context.Background(), "scratch")
+	assert.EqualError(t, err, "command exited with code 127: exec: \"cat\": executable file not found")
+}
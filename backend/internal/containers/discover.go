@@ -0,0 +1,295 @@
+package containers
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+)
+
+// Sources of a discovered port
+const (
+	SourceExposed   = "exposed"   // EXPOSE in the image or --expose
+	SourcePublished = "published" // published on the host
+	SourceListening = "listening" // found listening by scanning /proc/net/tcp
+)
+
+// wellKnownHTTPPorts are ports commonly served by web applications, which
+// rank higher than other ports speaking the same protocol
+var wellKnownHTTPPorts = map[uint16]bool{
+	80: true, 443: true, 3000: true, 4200: true, 5000: true, 5173: true,
+	8000: true, 8080: true, 8443: true, 8888: true, 9000: true,
+}
+
+// ErrNotRunning is returned when discovering the ports of a stopped container
+var ErrNotRunning = errors.New("container is not running")
+
+// scanTimeout bounds the scan of listening ports
+const scanTimeout = time.Second
+
+// DiscoveredPort is a candidate port of a container, with the endpoint
+// suggested for it
+type DiscoveredPort struct {
+	Port       uint16      `json:"port"`                 // port inside the container
+	PublicPort uint16      `json:"publicPort,omitempty"` // port published on the host, 0 if unpublished
+	Sources    []string    `json:"sources"`
+	Address    string      `json:"address"` // where the port was probed
+	Detected   *Detection  `json:"detected,omitempty"`
+	Suggestion *Suggestion `json:"suggestion,omitempty"` // nil if nothing answered
+	Score      int         `json:"score"`                // higher ranks first
+}
+
+// Suggestion is the endpoint suggested for a discovered port
+type Suggestion struct {
+	EndpointType   string `json:"endpointType"`   // "http" | "tls" | "tcp"
+	URLScheme      string `json:"urlScheme"`      // scheme of the endpoint URL
+	UpstreamScheme string `json:"upstreamScheme"` // scheme used to reach the container
+	TargetPort     string `json:"targetPort,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+// Discovery is the result of discovering the ports of a container
+type Discovery struct {
+	ContainerID string           `json:"containerId"`
+	Ports       []DiscoveredPort `json:"ports"`              // ranked, best first
+	Warnings    []string         `json:"warnings,omitempty"` // e.g. why the scan was skipped
+}
+
+// Discover enumerates the candidate ports of a container from its exposed
+// and published ports, and optionally from the sockets listening inside it,
+// probes them concurrently and ranks them
+func (l *Lister) Discover(ctx context.Context, containerID string, scan bool) (*Discovery, error) {
+	info, err := l.Docker.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if info.ContainerJSONBase == nil || info.State == nil || !info.State.Running {
+		return nil, fmt.Errorf("%w: %s", ErrNotRunning, containerID)
+	}
+
+	discovery := &Discovery{ContainerID: info.ID, Ports: []DiscoveredPort{}}
+	candidates := make(map[uint16]*DiscoveredPort)
+	candidate := func(port uint16, source string) *DiscoveredPort {
+		c, ok := candidates[port]
+		if !ok {
+			c = &DiscoveredPort{Port: port}
+			candidates[port] = c
+		}
+		if !slices.Contains(c.Sources, source) {
+			c.Sources = append(c.Sources, source)
+		}
+		return c
+	}
+
+	if info.Config != nil {
+		for port := range info.Config.ExposedPorts {
+			if port.Proto() == "tcp" {
+				candidate(uint16(port.Int()), SourceExposed)
+			}
+		}
+	}
+	if info.NetworkSettings != nil {
+		for port, bindings := range info.NetworkSettings.Ports {
+			if port.Proto() != "tcp" {
+				continue
+			}
+			for _, binding := range bindings {
+				if hostPort, err := strconv.ParseUint(binding.HostPort, 10, 16); err == nil {
+					candidate(uint16(port.Int()), SourcePublished).PublicPort = uint16(hostPort)
+					break
+				}
+			}
+		}
+	}
+	if scan {
+		ports, err := l.scanListening(ctx, containerID)
+		if err != nil {
+			discovery.Warnings = append(discovery.Warnings, fmt.Sprintf("listening ports were not scanned: %v", err))
+		}
+		for _, port := range ports {
+			candidate(port, SourceListening)
+		}
+	}
+
+	containerIP := containerAddress(info)
+	var wg sync.WaitGroup
+	for _, c := range candidates {
+		switch {
+		case c.PublicPort != 0:
+			c.Address = net.JoinHostPort(l.BridgeHost, strconv.Itoa(int(c.PublicPort)))
+		case containerIP != "":
+			c.Address = net.JoinHostPort(containerIP, strconv.Itoa(int(c.Port)))
+		default:
+			c.Address = net.JoinHostPort(l.BridgeHost, strconv.Itoa(int(c.Port)))
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			host, port, _ := net.SplitHostPort(c.Address)
+			c.Detected = l.detect(ctx, containerID, host, port)
+			c.Suggestion = suggest(c)
+			c.Score = score(c)
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range candidates {
+		discovery.Ports = append(discovery.Ports, *c)
+	}
+	slices.SortFunc(discovery.Ports, func(a, b DiscoveredPort) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Port, b.Port))
+	})
+	return discovery, nil
+}
+
+// containerAddress returns the IP address of the container on its first
+// network, or "" if it has none (e.g. host networking)
+func containerAddress(info types.ContainerJSON) string {
+	if info.NetworkSettings == nil {
+		return ""
+	}
+	names := make([]string, 0, len(info.NetworkSettings.Networks))
+	for name := range info.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if settings := info.NetworkSettings.Networks[name]; settings != nil && settings.IPAddress != "" {
+			return settings.IPAddress
+		}
+	}
+	return ""
+}
+
+// suggest picks the endpoint type for a port the way the manager builds
+// upstreams: HTTP endpoints for HTTP(S) services, TLS passthrough for other
+// TLS services and TCP for the rest
+func suggest(c *DiscoveredPort) *Suggestion {
+	d := c.Detected
+	if d == nil || !d.TCP {
+		return nil
+	}
+
+	var s Suggestion
+	switch {
+	case d.HTTPS:
+		s = Suggestion{EndpointType: "http", URLScheme: "https", UpstreamScheme: "https", Reason: "serves HTTPS"}
+	case d.HTTP:
+		s = Suggestion{EndpointType: "http", URLScheme: "https", UpstreamScheme: "http", Reason: "serves HTTP"}
+	case d.TLS:
+		s = Suggestion{EndpointType: "tls", URLScheme: "tls", UpstreamScheme: "tls", Reason: "accepts TLS connections"}
+	default:
+		s = Suggestion{EndpointType: "tcp", URLScheme: "tcp", UpstreamScheme: "tcp", Reason: "accepts TCP connections"}
+	}
+
+	// Endpoints forward to published ports
+	if c.PublicPort != 0 {
+		s.TargetPort = strconv.Itoa(int(c.PublicPort))
+	} else {
+		s.Reason += "; publish the port to create an endpoint for it"
+	}
+	return &s
+}
+
+// score ranks published ports above unpublished ones, and HTTP above TLS
+// above plain TCP
+func score(c *DiscoveredPort) int {
+	var score int
+	if c.PublicPort != 0 {
+		score += 100
+	}
+	if d := c.Detected; d != nil {
+		switch {
+		case d.HTTP || d.HTTPS:
+			score += 30
+		case d.TLS:
+			score += 20
+		case d.TCP:
+			score += 10
+		}
+	}
+	if wellKnownHTTPPorts[c.Port] {
+		score += 5
+	}
+	if slices.Contains(c.Sources, SourceExposed) {
+		score++
+	}
+	return score
+}
+
+// listenState is the socket state of listening sockets in /proc/net/tcp
+const listenState = "0A"
+
+// scanListening lists the TCP ports listening on non-loopback addresses
+// inside the container
+func (l *Lister) scanListening(ctx context.Context, containerID string) ([]uint16, error) {
+	ctx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+
+	// cat fails when the container has no IPv6, after printing /proc/net/tcp
+	out, err := l.Docker.ContainerExec(ctx, containerID, []string{"cat", "/proc/net/tcp", "/proc/net/tcp6"})
+	var exitErr *manager.ExecExitError
+	if err != nil && !(errors.As(err, &exitErr) && bytes.Contains(out, []byte("local_address"))) {
+		return nil, err
+	}
+	ports := parseProcNetTCP(out)
+	if len(ports) == 0 && !bytes.Contains(out, []byte("local_address")) {
+		return nil, fmt.Errorf("could not read /proc/net/tcp in the container")
+	}
+	return ports, nil
+}
+
+// parseProcNetTCP returns the sorted ports of the listening sockets in the
+// contents of /proc/net/tcp and /proc/net/tcp6
+func parseProcNetTCP(data []byte) []uint16 {
+	seen := make(map[uint16]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != listenState {
+			continue
+		}
+		addr, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok || isLoopback(addr) {
+			continue
+		}
+		port, err := strconv.ParseUint(hexPort, 16, 16)
+		if err != nil {
+			continue
+		}
+		seen[uint16(port)] = true
+	}
+
+	ports := make([]uint16, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	slices.Sort(ports)
+	return ports
+}
+
+// isLoopback reports whether a hex address from /proc/net/tcp is a loopback
+// address, which cannot be reached from outside the container
+func isLoopback(addr string) bool {
+	switch len(addr) {
+	case 8: // IPv4, little-endian: 127.x.x.x ends in 7F
+		return strings.HasSuffix(addr, "7F")
+	case 32: // IPv6 ::1, or ::ffff:127.x.x.x
+		return addr == "00000000000000000000000001000000" ||
+			(strings.HasPrefix(addr, "0000000000000000FFFF0000") && strings.HasSuffix(addr, "7F"))
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package detectproto
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long detection results are reused by default
+const DefaultCacheTTL = 30 * time.Second
+
+// StartedAtFunc returns when a container was last started, so that cached
+// results are discarded when it restarts
+type StartedAtFunc func(ctx context.Context, containerID string) (string, error)
+
+// Cache remembers the detection results of container ports until their TTL
+// expires or the container restarts. Ports that could not be reached are
+// probed again on every call.
+type Cache struct {
+	detector  Detector
+	ttl       time.Duration
+	startedAt StartedAtFunc // nil ignores restarts
+
+	// Now returns the current time
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+type cacheEntry struct {
+	result  Result
+	expires time.Time
+}
+
+// cacheKey identifies a port of one run of a container
+type cacheKey struct {
+	containerID string
+	startedAt   string
+	host        string
+	port        string
+}
+
+// NewCache creates a cache in front of detector
+func NewCache(detector Detector, ttl time.Duration, startedAt StartedAtFunc) *Cache {
+	return &Cache{
+		detector:  detector,
+		ttl:       ttl,
+		startedAt: startedAt,
+		Now:       time.Now,
+		entries:   make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Detect probes a port without using the cache
+func (c *Cache) Detect(ctx context.Context, host, port string) (*Result, error) {
+	return c.detector.Detect(ctx, host, port)
+}
+
+// DetectContainer returns the cached result for a port of a container,
+// probing it if the result is missing or stale
+func (c *Cache) DetectContainer(ctx context.Context, containerID, host, port string) (*Result, error) {
+	key := cacheKey{containerID: containerID, host: host, port: port}
+	if c.startedAt != nil {
+		startedAt, err := c.startedAt(ctx, containerID)
+		if err != nil {
+			// Without a start time the result cannot be keyed safely
+			return c.detector.Detect(ctx, host, port)
+		}
+		key.startedAt = startedAt
+	}
+
+	now := c.Now()
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		result := cached.result
+		return &result, nil
+	}
+
+	detected, err := c.detector.Detect(ctx, host, port)
+	if err != nil {
+		return nil, err
+	}
+	result := *detected
+	if result.DetectedAt.IsZero() {
+		result.DetectedAt = now
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if result.TCP {
+		c.entries[key] = cacheEntry{result: result, expires: now.Add(c.ttl)}
+	}
+	return &result, nil
+}
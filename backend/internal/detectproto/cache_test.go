@@ -0,0 +1,80 @@
+package detectproto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDetector returns canned results and counts the probes
+type fakeDetector struct {
+	result Result
+	probes int
+}
+
+func (f *fakeDetector) Detect(ctx context.Context, host, port string) (*Result, error) {
+	f.probes++
+	result := f.result
+	return &result, nil
+}
+
+func TestCache_Expires(t *testing.T) {
+	detector := &fakeDetector{result: Result{TCP: true}}
+	cache := NewCache(detector, 30*time.Second, nil)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.Now = func() time.Time { return now }
+
+	first, err := cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	assert.False(t, first.HTTP)
+	assert.Equal(t, now, first.DetectedAt)
+
+	now = now.Add(29 * time.Second)
+	cached, err := cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+	assert.Equal(t, 1, detector.probes)
+
+	now = now.Add(2 * time.Second)
+	detector.result = Result{TCP: true, HTTP: true}
+	second, err := cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	assert.True(t, second.HTTP)
+	assert.Equal(t, now, second.DetectedAt)
+	assert.Equal(t, 2, detector.probes)
+}
+
+func TestCache_ContainerRestart(t *testing.T) {
+	detector := &fakeDetector{result: Result{TCP: true}}
+	startedAt := "2025-01-01T00:00:00Z"
+	cache := NewCache(detector, time.Minute, func(ctx context.Context, containerID string) (string, error) {
+		return startedAt, nil
+	})
+
+	_, err := cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	_, err = cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	assert.Equal(t, 1, detector.probes)
+
+	startedAt = "2025-01-01T00:05:00Z"
+	_, err = cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+	require.NoError(t, err)
+	assert.Equal(t, 2, detector.probes)
+}
+
+func TestCache_UnreachableNotCached(t *testing.T) {
+	detector := &fakeDetector{result: Result{Error: "connection refused"}}
+	cache := NewCache(detector, time.Minute, nil)
+
+	for range 2 {
+		result, err := cache.DetectContainer(context.Background(), "web", "172.17.0.1", "8080")
+		require.NoError(t, err)
+		assert.False(t, result.TCP)
+		assert.Equal(t, "connection refused", result.Error)
+	}
+	assert.Equal(t, 2, detector.probes)
+}
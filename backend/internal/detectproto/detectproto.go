@@ -3,8 +3,29 @@ package detectproto
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"log/slog"
 	"net"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
+)
+
+var (
+	tracer = otel.Tracer(telemetry.InstrumentationName)
+
+	detectDuration, _ = otel.Meter(telemetry.InstrumentationName).Float64Histogram("ngrok_ext.detect.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of protocol detection probes"),
+	)
 )
 
 // Result represents all protocols detected on the TCP port
@@ -13,6 +34,34 @@ type Result struct {
 	HTTP  bool // Port accepts HTTP requests
 	HTTPS bool // Port accepts HTTPS requests (TLS + HTTP)
 	TLS   bool // Port accepts TLS connections (may or may not be HTTP)
+
+	// HTTPPath is the first probe path that got a response other than a 404,
+	// empty if every path got a 404
+	HTTPPath string
+
+	Error      string    // Why the port could not be reached, if TCP is false
+	DetectedAt time.Time // When the port was probed
+}
+
+// Config configures a detector
+type Config struct {
+	// Timeout bounds each detection, in addition to the context deadline
+	Timeout time.Duration
+	// ProbePaths are requested in order while they get a 404, so that later
+	// paths are fallbacks for upstreams that do not serve earlier ones, and
+	// the first one served is reported as Result.HTTPPath. Any HTTP
+	// response, including a 404, detects HTTP.
+	ProbePaths []string
+	// Logger receives the result of each detection at debug level, if set
+	Logger *slog.Logger
+}
+
+// DefaultConfig returns the configuration used by NewDetector
+func DefaultConfig() Config {
+	return Config{
+		Timeout:    time.Second,
+		ProbePaths: []string{"/ngrok-docker-extension-probe"},
+	}
 }
 
 // Detector interface for protocol detection
@@ -21,25 +70,69 @@ type Detector interface {
 }
 
 // detector is the concrete implementation of the Detector interface
-type detector struct{}
+type detector struct {
+	config Config
+}
 
-// NewDetector creates a new protocol detector
+// NewDetector creates a new protocol detector with the default configuration
 func NewDetector() Detector {
-	return &detector{}
+	return NewDetectorWithConfig(DefaultConfig())
 }
 
-// Detect probes the TCP port and returns which protocols are supported. Be sure
-// to set a context deadline or control the cancellation otherwise this function
-// could hang forever with an unresponsive port.
+// NewDetectorWithConfig creates a new protocol detector
+func NewDetectorWithConfig(config Config) Detector {
+	if len(config.ProbePaths) == 0 {
+		config.ProbePaths = DefaultConfig().ProbePaths
+	}
+	return &detector{config: config}
+}
+
+// Detect probes the TCP port and returns which protocols are supported. Without
+// a configured timeout, be sure to set a context deadline or control the
+// cancellation otherwise this function could hang forever with an
+// unresponsive port.
 func (d *detector) Detect(ctx context.Context, host, port string) (*Result, error) {
-	result := &Result{}
+	ctx, span := tracer.Start(ctx, "detect", trace.WithAttributes(
+		attribute.String("server.address", host),
+		attribute.String("server.port", port),
+	))
+	defer span.End()
+	started := time.Now()
+
+	result, err := d.detect(ctx, host, port)
+	if err == nil && d.config.Logger != nil {
+		d.config.Logger.Debug("Detected protocols", "host", host, "port", port,
+			"tcp", result.TCP, "http", result.HTTP, "https", result.HTTPS, "tls", result.TLS, "httpPath", result.HTTPPath,
+			"error", result.Error, "duration", time.Since(started))
+	}
+	if err == nil {
+		span.SetAttributes(
+			attribute.Bool("ngrok_ext.detect.tcp", result.TCP),
+			attribute.Bool("ngrok_ext.detect.http", result.HTTP),
+			attribute.Bool("ngrok_ext.detect.tls", result.TLS),
+		)
+	}
+	detectDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+		attribute.Bool("reachable", err == nil && result.TCP),
+	))
+	return result, err
+}
+
+// detect runs the probes, traced by Detect
+func (d *detector) detect(ctx context.Context, host, port string) (*Result, error) {
+	if d.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.config.Timeout)
+		defer cancel()
+	}
+	result := &Result{DetectedAt: time.Now()}
 
 	// Run HTTP and TLS tests concurrently
 	httpChan := make(chan httpResult, 1)
 	tlsChan := make(chan tlsResult, 1)
 
 	go func() {
-		httpChan <- tryHTTP(ctx, host, port)
+		httpChan <- tryHTTP(ctx, host, port, d.config.ProbePaths)
 	}()
 
 	go func() {
@@ -53,10 +146,13 @@ func (d *detector) Detect(ctx context.Context, host, port string) (*Result, erro
 	// Interpret results - TCP is successful if either test established TCP connection
 	if httpRes.tcpSuccess || tlsRes.tcpSuccess {
 		result.TCP = true
+	} else if httpRes.err != nil {
+		result.Error = httpRes.err.Error()
 	}
 
 	if httpRes.httpSuccess {
 		result.HTTP = true
+		result.HTTPPath = httpRes.path
 	}
 
 	if tlsRes.tlsSuccess {
@@ -72,6 +168,8 @@ func (d *detector) Detect(ctx context.Context, host, port string) (*Result, erro
 type httpResult struct {
 	tcpSuccess  bool
 	httpSuccess bool
+	status      int    // status code of the last HTTP response
+	path        string // the path that got a response other than a 404
 	err         error
 }
 
@@ -126,7 +224,30 @@ func tryTLS(ctx context.Context, host, port string) tlsResult {
 	return result
 }
 
-func tryHTTP(ctx context.Context, host, port string) httpResult {
+// tryHTTP requests each path in turn, moving on to the next one while they
+// get a 404 or no HTTP response
+func tryHTTP(ctx context.Context, host, port string, paths []string) httpResult {
+	var result httpResult
+	for _, path := range paths {
+		res := tryHTTPPath(ctx, host, port, path)
+		result.tcpSuccess = result.tcpSuccess || res.tcpSuccess
+		result.httpSuccess = result.httpSuccess || res.httpSuccess
+		result.err = res.err
+		if res.httpSuccess {
+			result.status = res.status
+			if res.status != http.StatusNotFound {
+				result.path = path
+				break
+			}
+		}
+		if !res.tcpSuccess {
+			break
+		}
+	}
+	return result
+}
+
+func tryHTTPPath(ctx context.Context, host, port, path string) httpResult {
 	// First establish TCP connection
 	var dialer net.Dialer
 	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
@@ -143,7 +264,7 @@ func tryHTTP(ctx context.Context, host, port string) httpResult {
 	result := httpResult{tcpSuccess: true}
 
 	// Send distinctive HTTP request for log identification
-	request := "GET /ngrok-docker-extension-probe HTTP/1.1\r\n" +
+	request := fmt.Sprintf("GET %s HTTP/1.1\r\n", path) +
 		"Host: ngrok-docker-extension-probe.local\r\n" +
 		"User-Agent: ngrok-docker-extension/1.0\r\n" +
 		"Connection: close\r\n\r\n"
@@ -169,10 +290,13 @@ func tryHTTP(ctx context.Context, host, port string) httpResult {
 		return result
 	}
 
-	// Look for HTTP response pattern
+	// Look for HTTP response pattern: "HTTP/1.1 200 OK"
 	responseStr := string(response[:n])
 	if strings.HasPrefix(responseStr, "HTTP/") {
 		result.httpSuccess = true
+		if fields := strings.Fields(responseStr); len(fields) > 1 {
+			result.status, _ = strconv.Atoi(fields[1])
+		}
 	}
 
 	return result
@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -151,7 +152,7 @@ func TestProtocolDetection(t *testing.T) {
 		{
 			name:     "HTTP server",
 			setup:    func(t *testing.T) (int, func()) { return startHTTPServer(t) },
-			expected: Result{TCP: true, HTTP: true, HTTPS: false, TLS: false},
+			expected: Result{TCP: true, HTTP: true, HTTPS: false, TLS: false, HTTPPath: "/ngrok-docker-extension-probe"},
 		},
 		{
 			name:     "HTTPS server with HTTP ALPN",
@@ -180,6 +181,11 @@ func TestProtocolDetection(t *testing.T) {
 
 			result, err := detector.Detect(ctx, "127.0.0.1", fmt.Sprintf("%d", port))
 			require.NoError(t, err)
+			assert.False(t, result.DetectedAt.IsZero())
+			// Only unreachable ports record an error
+			assert.Equal(t, !tt.expected.TCP, result.Error != "", result.Error)
+
+			result.DetectedAt, result.Error = time.Time{}, ""
 			assert.Equal(t, tt.expected, *result)
 		})
 	}
@@ -250,3 +256,34 @@ func TestInvalidInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestTryHTTP_ProbePathsFallBackOn404(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path != "/healthz" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Probing stops at the first path that is not a 404
+	result := tryHTTP(ctx, host, port, []string{"/missing", "/healthz", "/never"})
+	assert.True(t, result.httpSuccess)
+	assert.Equal(t, http.StatusOK, result.status)
+	assert.Equal(t, "/healthz", result.path)
+	assert.Equal(t, []string{"/missing", "/healthz"}, requested)
+
+	// A 404 on every path is still HTTP
+	requested = nil
+	result = tryHTTP(ctx, host, port, []string{"/missing", "/gone"})
+	assert.True(t, result.httpSuccess)
+	assert.Equal(t, http.StatusNotFound, result.status)
+	assert.Empty(t, result.path)
+	assert.Equal(t, []string{"/missing", "/gone"}, requested)
+}
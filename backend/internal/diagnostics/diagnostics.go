@@ -0,0 +1,433 @@
+// Package diagnostics runs preflight checks against the extension's
+// dependencies and configured endpoints, so that problems can be diagnosed
+// without exec-ing into the container.
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// severity orders statuses from best to worst
+var severity = map[Status]int{StatusPass: 0, StatusWarn: 1, StatusFail: 2}
+
+// worst returns the more severe of two statuses
+func worst(a, b Status) Status {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
+
+// Check is the result of a single diagnostic check
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// EndpointReport holds the checks run for one endpoint
+type EndpointReport struct {
+	ID     string  `json:"id"`
+	Status Status  `json:"status"` // worst status of its checks
+	Checks []Check `json:"checks"`
+}
+
+// Report is the result of a diagnostics run
+type Report struct {
+	Status    Status           `json:"status"` // worst status of all checks
+	Checks    []Check          `json:"checks"`
+	Endpoints []EndpointReport `json:"endpoints"`
+}
+
+// slowLatency is the agent heartbeat latency above which a warning is given
+const slowLatency = 500 * time.Millisecond
+
+// checkTimeout bounds each network check
+const checkTimeout = time.Second
+
+// authtokenPattern matches the format of ngrok authtokens
+var authtokenPattern = regexp.MustCompile(`^[A-Za-z0-9]{20,}_[A-Za-z0-9]{10,}$`)
+
+// Checker runs diagnostics against the extension's dependencies
+type Checker struct {
+	Store    store.Store
+	Manager  manager.Manager
+	Docker   manager.DockerClient
+	Detector manager.ProtocolDetector
+
+	// BridgeHost is the address where container ports are reachable
+	BridgeHost string
+	// Dial is used to probe the bridge gateway
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// NewChecker creates a Checker that probes the Docker bridge gateway
+func NewChecker(st store.Store, mgr manager.Manager, docker manager.DockerClient, detector manager.ProtocolDetector) *Checker {
+	var dialer net.Dialer
+	return &Checker{
+		Store:      st,
+		Manager:    mgr,
+		Docker:     docker,
+		Detector:   detector,
+		BridgeHost: manager.DockerBridgeHost,
+		Dial:       dialer.DialContext,
+	}
+}
+
+// Run executes every check and returns the report
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{Status: StatusPass, Endpoints: []EndpointReport{}}
+	add := func(check Check) {
+		report.Checks = append(report.Checks, check)
+		report.Status = worst(report.Status, check.Status)
+	}
+
+	dockerCheck := c.checkDocker(ctx)
+	add(dockerCheck)
+	add(c.checkBridgeGateway(ctx))
+
+	stateCheck, state := c.checkStateFile()
+	add(stateCheck)
+	if state == nil {
+		return report
+	}
+	add(checkAuthtoken(state.AgentConfig))
+	add(c.checkAgent(state.AgentConfig))
+
+	statuses := c.Manager.EndpointStatus()
+	for _, id := range slices.Sorted(maps.Keys(state.EndpointConfigs)) {
+		endpoint := c.checkEndpoint(ctx, state.EndpointConfigs[id], state.EndpointConfigs, statuses, dockerCheck.Status != StatusFail)
+		report.Endpoints = append(report.Endpoints, endpoint)
+		report.Status = worst(report.Status, endpoint.Status)
+	}
+
+	return report
+}
+
+func (c *Checker) checkDocker(ctx context.Context) Check {
+	check := Check{Name: "docker"}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	ping, err := c.Docker.Ping(ctx)
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("Docker socket is unreachable: %v", err)
+		return check
+	}
+	check.Status = StatusPass
+	check.Message = fmt.Sprintf("Docker API %s is reachable", ping.APIVersion)
+	return check
+}
+
+func (c *Checker) checkBridgeGateway(ctx context.Context) Check {
+	check := Check{Name: "bridge_gateway"}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	// Any answer from the gateway, including a refused connection, shows
+	// that it is reachable
+	conn, err := c.Dial(ctx, "tcp", net.JoinHostPort(c.BridgeHost, "1"))
+	if err == nil {
+		conn.Close()
+	}
+	if err == nil || errors.Is(err, syscall.ECONNREFUSED) {
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("Docker bridge gateway %s is reachable", c.BridgeHost)
+		return check
+	}
+	check.Status = StatusFail
+	check.Message = fmt.Sprintf("Docker bridge gateway %s is unreachable: %v", c.BridgeHost, err)
+	return check
+}
+
+// checkStateFile verifies that the state can be read and that its directory
+// is writable, without writing the state itself. It returns the loaded state,
+// or nil if it could not be read.
+func (c *Checker) checkStateFile() (Check, *store.State) {
+	check := Check{Name: "state_file"}
+
+	state, err := c.Store.Load()
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("failed to read state: %v", err)
+		return check, nil
+	}
+
+	check.Status = StatusPass
+	ro, readOnly := c.Store.(store.ReadOnlyStore)
+	readOnly = readOnly && ro.ReadOnly()
+	file, fileBacked := c.Store.(store.FileBacked)
+	switch {
+	case readOnly && fileBacked:
+		check.Message = fmt.Sprintf("state can be read and is read-only (managed by %s)", file.Path())
+	case readOnly:
+		check.Message = "state can be read and is read-only"
+	case !fileBacked:
+		check.Message = "state can be read and is kept in memory"
+	default:
+		dir := filepath.Dir(file.Path())
+		if err := checkWritable(dir); err != nil {
+			check.Status = StatusFail
+			check.Message = fmt.Sprintf("state directory %s is not writable: %v", dir, err)
+			return check, state
+		}
+		check.Message = "state can be read and its directory is writable"
+	}
+	return check, state
+}
+
+// checkWritable creates and removes a temporary file in dir
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".diagnostics-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func checkAuthtoken(config store.AgentConfig) Check {
+	check := Check{Name: "authtoken"}
+	switch {
+	case config.AuthToken == "" && config.ExpectedState == manager.AgentStateOnline:
+		check.Status = StatusFail
+		check.Message = "no authtoken is configured"
+	case config.AuthToken == "":
+		check.Status = StatusWarn
+		check.Message = "no authtoken is configured"
+	case !authtokenPattern.MatchString(config.AuthToken):
+		check.Status = StatusWarn
+		check.Message = "authtoken does not look like an ngrok authtoken"
+	default:
+		check.Status = StatusPass
+		check.Message = "authtoken is well formed"
+	}
+	return check
+}
+
+func (c *Checker) checkAgent(config store.AgentConfig) Check {
+	check := Check{Name: "agent"}
+	status := c.Manager.AgentStatus()
+
+	switch status.State {
+	case manager.AgentStateOnline:
+		if status.Latency > slowLatency {
+			check.Status = StatusWarn
+			check.Message = fmt.Sprintf("agent is online with high latency (%s)", status.Latency)
+		} else {
+			check.Status = StatusPass
+			check.Message = fmt.Sprintf("agent is online (latency %s)", status.Latency)
+		}
+	case manager.AgentStateConnecting:
+		check.Status = StatusWarn
+		check.Message = withError("agent is connecting", status.LastError)
+	default:
+		if config.ExpectedState == manager.AgentStateOnline {
+			check.Status = StatusFail
+		} else {
+			check.Status = StatusWarn
+		}
+		check.Message = withError("agent is offline", status.LastError)
+	}
+	return check
+}
+
+// checkEndpoint runs the checks for one endpoint. Container checks are skipped
+// when Docker is unreachable and for endpoints targeting an upstream URL.
+// Routers are checked through the endpoints they forward to.
+func (c *Checker) checkEndpoint(ctx context.Context, config store.EndpointConfig, configs map[string]store.EndpointConfig, statuses map[string]manager.EndpointStatus, dockerReachable bool) EndpointReport {
+	report := EndpointReport{ID: config.ID, Status: StatusPass}
+	add := func(check Check) {
+		report.Checks = append(report.Checks, check)
+		report.Status = worst(report.Status, check.Status)
+	}
+
+	status := statuses[config.ID]
+	if status.State == manager.EndpointStateFailed {
+		add(Check{Name: "status", Status: StatusFail, Message: withError("endpoint failed to start", status.LastError)})
+	}
+
+	if len(config.Router) > 0 {
+		add(checkRouter(config, configs, statuses))
+		if config.TrafficPolicy != "" {
+			add(checkTrafficPolicy(config.TrafficPolicy))
+		}
+		return report
+	}
+
+	if dockerReachable && config.ContainerID != "" {
+		running := c.checkContainer(ctx, config)
+		add(running)
+		if running.Status == StatusFail {
+			return report
+		}
+	}
+
+	port, detected := c.checkPort(ctx, config)
+	add(port)
+	if detected != nil {
+		add(checkProtocol(config, detected))
+	}
+	if config.TrafficPolicy != "" {
+		add(checkTrafficPolicy(config.TrafficPolicy))
+	}
+
+	return report
+}
+
+// checkRouter checks that the endpoints a router forwards to exist and are
+// online
+func checkRouter(config store.EndpointConfig, configs map[string]store.EndpointConfig, statuses map[string]manager.EndpointStatus) Check {
+	check := Check{Name: "router", Status: StatusPass}
+	var problems []string
+	for _, rule := range config.Router {
+		if _, exists := configs[rule.Endpoint]; !exists {
+			check.Status = StatusFail
+			problems = append(problems, fmt.Sprintf("endpoint %s does not exist", rule.Endpoint))
+			continue
+		}
+		if state := statuses[rule.Endpoint].State; state != manager.EndpointStateOnline {
+			check.Status = worst(check.Status, StatusWarn)
+			if state == "" {
+				state = manager.EndpointStateOffline
+			}
+			problems = append(problems, fmt.Sprintf("endpoint %s is %s", rule.Endpoint, state))
+		}
+	}
+	if len(problems) > 0 {
+		check.Message = strings.Join(problems, "; ")
+	} else {
+		check.Message = fmt.Sprintf("all %d endpoints the router forwards to are online", len(config.Router))
+	}
+	return check
+}
+
+func (c *Checker) checkContainer(ctx context.Context, config store.EndpointConfig) Check {
+	check := Check{Name: "container"}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	info, err := c.Docker.ContainerInspect(ctx, config.ContainerID)
+	switch {
+	case err != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("failed to inspect container %s: %v", config.ContainerID, err)
+	case info.ContainerJSONBase == nil || info.State == nil || !info.State.Running:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("container %s is not running", config.ContainerID)
+	default:
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("container %s is running", config.ContainerID)
+	}
+	return check
+}
+
+// checkPort probes the target port or upstream and returns the detected
+// protocols, or nil if detection failed
+func (c *Checker) checkPort(ctx context.Context, config store.EndpointConfig) (Check, *protocols) {
+	check := Check{Name: "port"}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	host, port := c.BridgeHost, config.TargetPort
+	if config.Upstream != "" {
+		u, err := manager.ParseUpstream(config.Upstream)
+		if err != nil {
+			check.Status = StatusFail
+			check.Message = err.Error()
+			return check, nil
+		}
+		host, port = u.Hostname(), u.Port()
+	}
+
+	address := net.JoinHostPort(host, port)
+	result, err := c.Detector.Detect(ctx, host, port)
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("failed to probe %s: %v", address, err)
+		return check, nil
+	}
+	if !result.TCP {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("nothing is listening on %s", address)
+		return check, nil
+	}
+
+	check.Status = StatusPass
+	check.Message = fmt.Sprintf("%s accepts connections", address)
+	return check, &protocols{http: result.HTTP, https: result.HTTPS, tls: result.TLS}
+}
+
+// protocols are the protocols detected on a target port
+type protocols struct {
+	http, https, tls bool
+}
+
+func (p *protocols) String() string {
+	switch {
+	case p.https:
+		return "HTTPS"
+	case p.http:
+		return "HTTP"
+	case p.tls:
+		return "TLS"
+	}
+	return "TCP"
+}
+
+// checkProtocol compares the detected upstream protocol with the endpoint's
+// URL scheme
+func checkProtocol(config store.EndpointConfig, detected *protocols) Check {
+	check := Check{Name: "protocol", Status: StatusPass}
+
+	scheme := manager.EndpointScheme(config)
+
+	switch scheme {
+	case "http", "https":
+		if !detected.http && !detected.https {
+			check.Status = StatusWarn
+			check.Message = fmt.Sprintf("%s endpoint forwards to an upstream that speaks %s, not HTTP", scheme, detected)
+			return check
+		}
+	case "tls":
+		// Upstreams of endpoints terminating TLS at the edge may speak either
+		if !detected.tls && config.TLSTermination != manager.TLSTerminationEdge {
+			check.Status = StatusWarn
+			check.Message = fmt.Sprintf("tls endpoint passes TLS through to an upstream that speaks %s, not TLS; set tlsTermination to edge to decrypt it first", detected)
+			return check
+		}
+	}
+	check.Message = fmt.Sprintf("%s endpoint forwards to a %s upstream", scheme, detected)
+	return check
+}
+
+func withError(message, lastError string) string {
+	if lastError == "" {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, lastError)
+}
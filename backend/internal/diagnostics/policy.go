@@ -0,0 +1,65 @@
+package diagnostics
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// trafficPolicyPhases are the phases a traffic policy may define rules for
+var trafficPolicyPhases = map[string]bool{
+	"on_http_request":  true,
+	"on_http_response": true,
+	"on_tcp_connect":   true,
+}
+
+// trafficPolicy is the structure of a traffic policy document. JSON policies
+// are valid YAML, so both formats parse into it.
+type trafficPolicy map[string][]struct {
+	Name        string   `yaml:"name"`
+	Expressions []string `yaml:"expressions"`
+	Actions     []struct {
+		Type string `yaml:"type"`
+	} `yaml:"actions"`
+}
+
+// checkTrafficPolicy validates the structure of a traffic policy. The ngrok
+// cloud validates actions and expressions in depth when the endpoint starts.
+func checkTrafficPolicy(policy string) Check {
+	check := Check{Name: "traffic_policy", Status: StatusFail}
+
+	var parsed trafficPolicy
+	if err := yaml.Unmarshal([]byte(policy), &parsed); err != nil {
+		check.Message = fmt.Sprintf("traffic policy is not valid YAML or JSON: %v", err)
+		return check
+	}
+	if len(parsed) == 0 {
+		check.Message = "traffic policy defines no phases"
+		return check
+	}
+
+	for _, phase := range slices.Sorted(maps.Keys(parsed)) {
+		if !trafficPolicyPhases[phase] {
+			check.Message = fmt.Sprintf("traffic policy has unknown phase %q", phase)
+			return check
+		}
+		for i, rule := range parsed[phase] {
+			if len(rule.Actions) == 0 {
+				check.Message = fmt.Sprintf("rule %d of %s has no actions", i+1, phase)
+				return check
+			}
+			for _, action := range rule.Actions {
+				if action.Type == "" {
+					check.Message = fmt.Sprintf("rule %d of %s has an action without a type", i+1, phase)
+					return check
+				}
+			}
+		}
+	}
+
+	check.Status = StatusPass
+	check.Message = "traffic policy is well formed"
+	return check
+}
@@ -0,0 +1,180 @@
+// Package gitops derives the desired state from a declarative configuration
+// file, which then owns it: the REST API cannot change it, and differences
+// between the file and the runtime are reported as drift.
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Config is the structure of ngrok-extension.yaml
+type Config struct {
+	Agent     AgentConfig      `yaml:"agent"`
+	Endpoints []EndpointConfig `yaml:"endpoints"`
+}
+
+// AgentConfig is the agent section of the configuration file. Secrets are
+// read from the environment rather than written in the file.
+type AgentConfig struct {
+	AuthTokenEnv  string `yaml:"authTokenEnv"` // environment variable holding the authtoken
+	ConnectURL    string `yaml:"connectURL"`
+	ExpectedState string `yaml:"expectedState"` // defaults to "online"
+}
+
+// EndpointConfig is one entry of the endpoints section of the configuration
+// file
+type EndpointConfig struct {
+	ContainerID    string        `yaml:"containerId"`
+	TargetPort     string        `yaml:"targetPort"`
+	Upstream       string        `yaml:"upstream"` // instead of containerId and targetPort
+	URL            string        `yaml:"url"`
+	Binding        string        `yaml:"binding"`
+	PoolingEnabled bool          `yaml:"poolingEnabled"`
+	TrafficPolicy  string        `yaml:"trafficPolicy"`
+	Description    string        `yaml:"description"`
+	Metadata       string        `yaml:"metadata"`
+	ExpectedState  string        `yaml:"expectedState"` // defaults to "online"
+	ProxyProtocol  string        `yaml:"proxyProtocol"`
+	TLSTermination string        `yaml:"tlsTermination"`
+	ForwardedFor   string        `yaml:"forwardedFor"`
+	RealIP         bool          `yaml:"realIP"`
+	HostHeader     string        `yaml:"hostHeader"`
+	Routes         []RouteConfig `yaml:"routes"`
+
+	// Router makes the entry a router, identified by its url, forwarding to
+	// the internal endpoints of the file
+	Router []RouterRuleConfig `yaml:"router"`
+}
+
+// RouteConfig sends the requests of an endpoint matching a path prefix to
+// another port of its container
+type RouteConfig struct {
+	Path       string `yaml:"path"`
+	TargetPort string `yaml:"targetPort"`
+}
+
+// RouterRuleConfig forwards the requests of a router matching a host and
+// path to an internal endpoint, given by its ID
+type RouterRuleConfig struct {
+	Host     string `yaml:"host"`
+	Path     string `yaml:"path"`
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Parse derives the desired state from a configuration file. getenv looks up
+// the secrets the file refers to.
+func Parse(data []byte, getenv func(string) string) (*store.State, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	state := &store.State{
+		EndpointConfigs: make(map[string]store.EndpointConfig),
+		Version:         1,
+	}
+
+	agentState, err := expectedState(config.Agent.ExpectedState)
+	if err != nil {
+		return nil, fmt.Errorf("agent: %w", err)
+	}
+	state.AgentConfig = store.AgentConfig{
+		ConnectURL:    config.Agent.ConnectURL,
+		ExpectedState: agentState,
+	}
+	if config.Agent.AuthTokenEnv != "" {
+		state.AgentConfig.AuthToken = getenv(config.Agent.AuthTokenEnv)
+		if state.AgentConfig.AuthToken == "" {
+			return nil, fmt.Errorf("agent: environment variable %s is not set", config.Agent.AuthTokenEnv)
+		}
+	}
+
+	for i, endpoint := range config.Endpoints {
+		id, upstream, err := endpointID(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("endpoints[%d]: %w", i, err)
+		}
+		binding, err := manager.EndpointBinding(endpoint.URL, endpoint.Binding)
+		if err != nil {
+			return nil, fmt.Errorf("endpoints[%d]: %w", i, err)
+		}
+		endpointState, err := expectedState(endpoint.ExpectedState)
+		if err != nil {
+			return nil, fmt.Errorf("endpoints[%d]: %w", i, err)
+		}
+
+		if _, exists := state.EndpointConfigs[id]; exists {
+			return nil, fmt.Errorf("endpoints[%d]: duplicate endpoint %s", i, id)
+		}
+		config := store.EndpointConfig{
+			ID:             id,
+			ContainerID:    endpoint.ContainerID,
+			TargetPort:     endpoint.TargetPort,
+			Upstream:       upstream,
+			URL:            endpoint.URL,
+			Binding:        binding,
+			PoolingEnabled: endpoint.PoolingEnabled,
+			TrafficPolicy:  endpoint.TrafficPolicy,
+			Description:    endpoint.Description,
+			Metadata:       endpoint.Metadata,
+			ExpectedState:  endpointState,
+			ProxyProtocol:  endpoint.ProxyProtocol,
+			TLSTermination: endpoint.TLSTermination,
+			ForwardedFor:   endpoint.ForwardedFor,
+			RealIP:         endpoint.RealIP,
+			HostHeader:     endpoint.HostHeader,
+		}
+		for _, route := range endpoint.Routes {
+			config.Routes = append(config.Routes, store.Route{Path: route.Path, TargetPort: route.TargetPort})
+		}
+		for _, rule := range endpoint.Router {
+			config.Router = append(config.Router, store.RouterRule{Host: rule.Host, Path: rule.Path, Endpoint: rule.Endpoint})
+		}
+		if err := manager.ValidateEndpoint(config); err != nil {
+			return nil, fmt.Errorf("endpoints[%d]: %w", i, err)
+		}
+		state.EndpointConfigs[id] = config
+	}
+
+	// Routers may refer to endpoints listed after them
+	for _, id := range slices.Sorted(maps.Keys(state.EndpointConfigs)) {
+		if err := manager.ValidateReferences(state.EndpointConfigs, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// endpointID derives the ID of an endpoint entry from its target, or from its
+// url for routers. The upstream URL is returned normalized.
+func endpointID(endpoint EndpointConfig) (id string, upstream string, err error) {
+	if len(endpoint.Router) > 0 {
+		id, err := manager.RouterID(endpoint.URL)
+		return id, "", err
+	}
+	return manager.EndpointTarget(endpoint.ContainerID, endpoint.TargetPort, endpoint.Upstream)
+}
+
+// expectedState validates an expectedState field, defaulting to "online"
+func expectedState(s string) (string, error) {
+	switch s {
+	case "":
+		return "online", nil
+	case "online", "offline":
+		return s, nil
+	}
+	return "", fmt.Errorf("expectedState must be 'online' or 'offline', got %q", s)
+}
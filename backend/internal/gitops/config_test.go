@@ -0,0 +1,163 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestParse(t *testing.T) {
+	config := `
+agent:
+  authTokenEnv: NGROK_AUTHTOKEN
+  connectURL: connect.example.com:443
+endpoints:
+  - containerId: web
+    targetPort: "80"
+    url: https://web.example.com
+    trafficPolicy: |
+      on_http_request: []
+  - containerId: db
+    targetPort: "5432"
+    url: tcp://1.tcp.ngrok.io:12345
+    expectedState: offline
+  - upstream: http://host.docker.internal:3000
+`
+	state, err := Parse([]byte(config), env(map[string]string{"NGROK_AUTHTOKEN": "secret"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, &store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "secret",
+			ConnectURL:    "connect.example.com:443",
+			ExpectedState: "online",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {
+				ID: "web:80", ContainerID: "web", TargetPort: "80",
+				URL:           "https://web.example.com",
+				TrafficPolicy: "on_http_request: []\n",
+				ExpectedState: "online",
+			},
+			"db:5432": {
+				ID: "db:5432", ContainerID: "db", TargetPort: "5432",
+				URL:           "tcp://1.tcp.ngrok.io:12345",
+				ExpectedState: "offline",
+			},
+			"upstream:host.docker.internal:3000": {
+				ID:            "upstream:host.docker.internal:3000",
+				Upstream:      "http://host.docker.internal:3000",
+				ExpectedState: "online",
+			},
+		},
+		Version: 1,
+	}, state)
+}
+
+func TestParse_Router(t *testing.T) {
+	config := `
+endpoints:
+  - url: https://example.com
+    router:
+      - path: /api/*
+        endpoint: api:8080
+      - endpoint: web:80
+  - containerId: api
+    targetPort: "8080"
+    url: https://api.internal
+  - containerId: web
+    targetPort: "80"
+    url: https://web.internal
+    binding: internal
+`
+	state, err := Parse([]byte(config), env(nil))
+	require.NoError(t, err)
+
+	router := state.EndpointConfigs["router:example.com"]
+	assert.Equal(t, []store.RouterRule{
+		{Path: "/api/*", Endpoint: "api:8080"},
+		{Endpoint: "web:80"},
+	}, router.Router)
+	// .internal URLs imply the internal binding
+	assert.Equal(t, "internal", state.EndpointConfigs["api:8080"].Binding)
+	assert.Equal(t, "internal", state.EndpointConfigs["web:80"].Binding)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "unknown field",
+			config: "agent:\n  authToken: secret\n",
+			err:    "field authToken not found",
+		},
+		{
+			name:   "unset authtoken variable",
+			config: "agent:\n  authTokenEnv: MISSING\n",
+			err:    "environment variable MISSING is not set",
+		},
+		{
+			name:   "missing target port",
+			config: "endpoints:\n  - containerId: web\n",
+			err:    "endpoints[0]: targetPort is required",
+		},
+		{
+			name:   "upstream without port",
+			config: "endpoints:\n  - upstream: tcp://db\n",
+			err:    "endpoints[0]: invalid upstream \"tcp://db\": tcp upstreams require a port",
+		},
+		{
+			name:   "bad expected state",
+			config: "endpoints:\n  - containerId: web\n    targetPort: \"80\"\n    expectedState: paused\n",
+			err:    `expectedState must be 'online' or 'offline', got "paused"`,
+		},
+		{
+			name:   "duplicate endpoint",
+			config: "endpoints:\n  - {containerId: web, targetPort: \"80\"}\n  - {containerId: web, targetPort: \"80\"}\n",
+			err:    "endpoints[1]: duplicate endpoint web:80",
+		},
+		{
+			name:   "internal binding without internal url",
+			config: "endpoints:\n  - {containerId: web, targetPort: \"80\", binding: internal}\n",
+			err:    "endpoints[0]: internal endpoints require a url ending in .internal",
+		},
+		{
+			name:   "unreserved tcp address",
+			config: "endpoints:\n  - {containerId: db, targetPort: \"5432\", url: \"tcp://db.example.com:5432\"}\n",
+			err:    "endpoints[0]: tcp url tcp://db.example.com:5432 is not a reserved address",
+		},
+		{
+			name:   "router without url",
+			config: "endpoints:\n  - router: [{endpoint: web:80}]\n",
+			err:    "endpoints[0]: routers require a url",
+		},
+		{
+			name:   "router to missing endpoint",
+			config: "endpoints:\n  - {url: https://example.com, router: [{endpoint: web:80}]}\n",
+			err:    "router:example.com: router[0] forwards to endpoint web:80, which does not exist",
+		},
+		{
+			name:   "router to public endpoint",
+			config: "endpoints:\n  - {url: https://example.com, router: [{endpoint: web:80}]}\n  - {containerId: web, targetPort: \"80\"}\n",
+			err:    "router:example.com: router[0] forwards to endpoint web:80, which is not internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.config), env(nil))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
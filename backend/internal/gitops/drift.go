@@ -0,0 +1,73 @@
+package gitops
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// absent is the desired state of runtime endpoints missing from the file
+const absent = "absent"
+
+// Drift is a difference between the state declared in the configuration file
+// and the runtime
+type Drift struct {
+	Resource string `json:"resource"` // "agent" | "endpoint"
+	ID       string `json:"id,omitempty"`
+	Desired  string `json:"desired"`          // expectedState from the file, or "absent"
+	Actual   string `json:"actual"`           // runtime state
+	Reason   string `json:"reason,omitempty"` // last runtime error
+}
+
+// DetectDrift compares the desired state with the runtime status of the
+// agent and endpoints
+func DetectDrift(state *store.State, agent manager.AgentStatus, endpoints map[string]manager.EndpointStatus) []Drift {
+	drift := []Drift{}
+
+	if agent.State != state.AgentConfig.ExpectedState {
+		drift = append(drift, Drift{
+			Resource: "agent",
+			Desired:  state.AgentConfig.ExpectedState,
+			Actual:   agent.State,
+			Reason:   agent.LastError,
+		})
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(state.EndpointConfigs)) {
+		desired := state.EndpointConfigs[id].ExpectedState
+		status, exists := endpoints[id]
+		actual := status.State
+		if !exists {
+			actual = manager.EndpointStateOffline
+		}
+		if actual != desired {
+			drift = append(drift, Drift{
+				Resource: "endpoint",
+				ID:       id,
+				Desired:  desired,
+				Actual:   actual,
+				Reason:   status.LastError,
+			})
+		}
+	}
+
+	// Endpoints removed from the file must have been stopped
+	for _, id := range slices.Sorted(maps.Keys(endpoints)) {
+		if _, declared := state.EndpointConfigs[id]; declared {
+			continue
+		}
+		if status := endpoints[id]; status.State != manager.EndpointStateOffline {
+			drift = append(drift, Drift{
+				Resource: "endpoint",
+				ID:       id,
+				Desired:  absent,
+				Actual:   status.State,
+				Reason:   status.LastError,
+			})
+		}
+	}
+
+	return drift
+}
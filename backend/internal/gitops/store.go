@@ -0,0 +1,194 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Store is a read-only store.Store whose state is derived from a
+// configuration file. An invalid file does not replace the last valid
+// configuration; the error is reported by Status instead.
+type Store struct {
+	path   string
+	getenv func(string) string
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	data     []byte // last valid file contents
+	loadedAt time.Time
+	err      error // why the current file contents were rejected
+}
+
+// Status describes the configuration file backing a Store
+type Status struct {
+	Path     string    `json:"path"`
+	LoadedAt time.Time `json:"loadedAt"`        // when the last valid contents were read
+	Error    string    `json:"error,omitempty"` // why the current contents are rejected
+}
+
+// NewStore creates a store for the configuration file at path, which must
+// exist and be valid
+func NewStore(path string, getenv func(string) string, logger *slog.Logger) (*Store, error) {
+	s := &Store{path: path, getenv: getenv, logger: logger}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path returns the path of the configuration file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Status reports when the configuration was loaded and whether the file
+// currently holds an invalid configuration
+func (s *Store) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := Status{Path: s.path, LoadedAt: s.loadedAt}
+	if s.err != nil {
+		status.Error = s.err.Error()
+	}
+	return status
+}
+
+// Load re-reads the configuration file, falling back to the last valid
+// configuration if it is now invalid
+func (s *Store) Load() (*store.State, error) {
+	// A rejected file is reported by Status
+	s.reload()
+	return s.current()
+}
+
+// current parses the last valid configuration
+func (s *Store) current() (*store.State, error) {
+	return Parse(s.validData(), s.getenv)
+}
+
+// ReadOnly reports that the state can only be changed by editing the file
+func (s *Store) ReadOnly() bool {
+	return true
+}
+
+func (s *Store) Save(*store.State) error {
+	return s.readOnlyError()
+}
+
+func (s *Store) Update(func(*store.State) error) error {
+	return s.readOnlyError()
+}
+
+func (s *Store) readOnlyError() error {
+	return fmt.Errorf("%w: managed by %s", store.ErrReadOnly, s.path)
+}
+
+// Watch delivers the new state whenever the configuration file changes to a
+// valid configuration
+func (s *Store) Watch(ctx context.Context) <-chan *store.State {
+	ch := make(chan *store.State, 1)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		// Watch the directory: editors and mounted ConfigMaps replace the
+		// file rather than write to it
+		if err = watcher.Add(filepath.Dir(s.path)); err != nil {
+			watcher.Close()
+		}
+	}
+	if err != nil {
+		s.logger.Warn("Failed to watch configuration file, changes will only be picked up by polling",
+			"path", s.path, "error", err)
+		go func() {
+			<-ctx.Done()
+			close(ch)
+		}()
+		return ch
+	}
+
+	// Load also reloads the file, so changes are tracked per watcher
+	last := s.validData()
+	go func() {
+		defer close(ch)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				s.logger.Warn("Configuration file watch error", "path", s.path, "error", err)
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				s.reload()
+				data := s.validData()
+				if bytes.Equal(data, last) {
+					continue
+				}
+				last = data
+				state, err := Parse(data, s.getenv)
+				if err != nil {
+					continue
+				}
+				// Replace a state the receiver has not picked up yet
+				select {
+				case <-ch:
+				default:
+				}
+				ch <- state
+			}
+		}
+	}()
+	return ch
+}
+
+// validData returns the contents of the last valid configuration file
+func (s *Store) validData() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// reload reads the configuration file and keeps it if it is valid
+func (s *Store) reload() error {
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		_, err = Parse(data, s.getenv)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		if s.err == nil || s.err.Error() != err.Error() {
+			s.logger.Warn("Rejected invalid configuration file, keeping the last valid configuration",
+				"path", s.path, "error", err)
+		}
+		s.err = err
+		return err
+	}
+
+	s.err = nil
+	if !bytes.Equal(data, s.data) {
+		s.data = data
+		s.loadedAt = time.Now()
+		s.logger.Info("Loaded configuration file", "path", s.path)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gitops
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func writeConfig(t *testing.T, path, config string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+}
+
+func TestStore_InvalidFileKeepsLastValidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ngrok-extension.yaml")
+	writeConfig(t, path, "endpoints:\n  - {containerId: web, targetPort: \"80\"}\n")
+
+	s, err := NewStore(path, env(nil), slog.Default())
+	require.NoError(t, err)
+	assert.Empty(t, s.Status().Error)
+
+	writeConfig(t, path, "endpoints:\n  - {containerId: web}\n")
+	state, err := s.Load()
+	require.NoError(t, err)
+	assert.Contains(t, state.EndpointConfigs, "web:80")
+	assert.Contains(t, s.Status().Error, "targetPort is required")
+
+	writeConfig(t, path, "endpoints:\n  - {containerId: api, targetPort: \"8080\"}\n")
+	state, err = s.Load()
+	require.NoError(t, err)
+	assert.Contains(t, state.EndpointConfigs, "api:8080")
+	assert.NotContains(t, state.EndpointConfigs, "web:80")
+	assert.Empty(t, s.Status().Error)
+}
+
+func TestStore_IsReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ngrok-extension.yaml")
+	writeConfig(t, path, "")
+
+	s, err := NewStore(path, env(nil), slog.Default())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, s.Save(&store.State{}), store.ErrReadOnly)
+	assert.ErrorIs(t, s.Update(func(*store.State) error { return nil }), store.ErrReadOnly)
+}
+
+func TestNewStore_RejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ngrok-extension.yaml")
+	writeConfig(t, path, "agent: [")
+
+	_, err := NewStore(path, env(nil), slog.Default())
+	assert.Error(t, err)
+}
+
+func TestStore_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ngrok-extension.yaml")
+	writeConfig(t, path, "agent:\n  expectedState: offline\n")
+
+	s, err := NewStore(path, env(nil), slog.Default())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := s.Watch(ctx)
+
+	// An invalid edit is not delivered; the next valid one is
+	writeConfig(t, path, "agent:\n  expectedState: sleeping\n")
+	writeConfig(t, path, "agent:\n  expectedState: online\n")
+
+	select {
+	case state := <-changes:
+		assert.Equal(t, "online", state.AgentConfig.ExpectedState)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for configuration change")
+	}
+
+	cancel()
+	for range changes {
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+)
+
+// ndjsonContentType is the media type of newline delimited JSON
+const ndjsonContentType = "application/x-ndjson"
+
+// GetEndpointLogsResponse defines the response body for GET /endpoints/{id}/logs
+type GetEndpointLogsResponse struct {
+	Entries []accesslog.Entry `json:"entries"`
+}
+
+// GetEndpointLogs returns the access log of an endpoint, oldest first. Query
+// parameters since and until (RFC 3339), remoteAddr, method, path (a prefix),
+// status (e.g. 404 or 5xx) and limit filter the entries. format=ndjson exports
+// them as newline delimited JSON, and follow=true streams them, then every
+// matching entry recorded until the client disconnects.
+func (h *Handler) GetEndpointLogs(c echo.Context) error {
+	endpointID := endpointIDParam(c)
+	if endpointID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID is required"})
+	}
+
+	var filter accesslog.Filter
+	var err error
+
+	if since := c.QueryParam("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "since must be an RFC 3339 timestamp"})
+		}
+	}
+	if until := c.QueryParam("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "until must be an RFC 3339 timestamp"})
+		}
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be a non-negative integer"})
+		}
+	}
+	if filter.Status = c.QueryParam("status"); filter.Status != "" {
+		if err := accesslog.ParseStatus(filter.Status); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	filter.RemoteAddr = c.QueryParam("remoteAddr")
+	filter.Method = c.QueryParam("method")
+	filter.PathPrefix = c.QueryParam("path")
+
+	follow := false
+	if v := c.QueryParam("follow"); v != "" {
+		if follow, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "follow must be true or false"})
+		}
+	}
+	format := c.QueryParam("format")
+	switch format {
+	case "", "json", "ndjson":
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or ndjson"})
+	}
+
+	state, err := h.Store.Load()
+	if err != nil {
+		return h.internalServerError(c, "Failed to load configuration")
+	}
+	if _, exists := state.EndpointConfigs[endpointID]; !exists {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Endpoint not found"})
+	}
+
+	// The access log is optional; without one there is nothing to report
+	if h.AccessLog == nil {
+		if follow || format == "ndjson" {
+			c.Response().Header().Set(echo.HeaderContentType, ndjsonContentType)
+			return c.NoContent(http.StatusOK)
+		}
+		return c.JSON(http.StatusOK, GetEndpointLogsResponse{Entries: []accesslog.Entry{}})
+	}
+
+	if follow {
+		return h.followEndpointLogs(c, endpointID, filter)
+	}
+
+	entries, err := h.AccessLog.Query(endpointID, filter)
+	if err != nil {
+		h.logger.Error("failed to query access log", "endpoint", endpointID, "error", err)
+		return h.internalServerError(c, "Failed to read access log")
+	}
+
+	if format != "ndjson" {
+		return c.JSON(http.StatusOK, GetEndpointLogsResponse{Entries: entries})
+	}
+
+	filename := strings.NewReplacer(":", "_", "/", "_").Replace(endpointID) + ".ndjson"
+	c.Response().Header().Set(echo.HeaderContentType, ndjsonContentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return streamNDJSON(c, entries, nil)
+}
+
+// followEndpointLogs streams the entries of an endpoint as newline delimited
+// JSON until the client disconnects
+func (h *Handler) followEndpointLogs(c echo.Context, endpointID string, filter accesslog.Filter) error {
+	ctx := c.Request().Context()
+	entries, follow, err := h.AccessLog.Follow(ctx, endpointID, filter)
+	if err != nil {
+		h.logger.Error("failed to query access log", "endpoint", endpointID, "error", err)
+		return h.internalServerError(c, "Failed to read access log")
+	}
+
+	return streamNDJSON(c, entries, follow)
+}
+
+// streamNDJSON writes entries as newline delimited JSON, then each entry
+// received from follow until it is closed. A nil follow ends the stream after
+// entries.
+func streamNDJSON[T any](c echo.Context, entries []T, follow <-chan T) error {
+	c.Response().Header().Set(echo.HeaderContentType, ndjsonContentType)
+	c.Response().WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(c.Response())
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	c.Response().Flush()
+	if follow == nil {
+		return nil
+	}
+
+	for entry := range follow {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+		c.Response().Flush()
+	}
+	return nil
+}
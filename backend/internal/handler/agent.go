@@ -10,10 +10,11 @@ import (
 
 // AgentResponse combines config and runtime state for API
 type AgentResponse struct {
-	AuthToken     string              `json:"authToken"`
-	ConnectURL    string              `json:"connectURL,omitempty"`
-	ExpectedState string              `json:"expectedState"`
-	Status        manager.AgentStatus `json:"status"`
+	AuthToken       string              `json:"authToken"`
+	ConnectURL      string              `json:"connectURL,omitempty"`
+	ExpectedState   string              `json:"expectedState"`
+	ResourceVersion int64               `json:"resourceVersion"` // also returned as the ETag header
+	Status          manager.AgentStatus `json:"status"`
 }
 
 func (h *Handler) PutAgent(c echo.Context) error {
@@ -25,20 +26,18 @@ func (h *Handler) PutAgent(c echo.Context) error {
 	}
 
 	// Update the agent configuration
-	if err := h.Store.Update(func(state *store.State) error {
-		state.AgentConfig = config
-
-		// if you explicitly set the agent to be offline, we set all endpoints
-		// to be offline
-		if config.ExpectedState == manager.AgentStateOffline {
-			for id, cfg := range state.EndpointConfigs {
-				cfg.ExpectedState = manager.EndpointStateOffline
-				state.EndpointConfigs[id] = cfg
-			}
+	pre := preconditionFromRequest(c, config.ResourceVersion)
+	if err := h.updateState(c, func(state *store.State) error {
+		if err := pre.check(true, state.AgentConfig.ResourceVersion); err != nil {
+			return err
 		}
 
+		applyAgentConfig(state, config)
 		return nil
 	}); err != nil {
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
 		return h.internalServerError(c, "Failed to save configuration")
 	}
 
@@ -51,6 +50,24 @@ func (h *Handler) PutAgent(c echo.Context) error {
 	return h.buildAgentResponse(c)
 }
 
+// applyAgentConfig stores config as the agent configuration in state
+func applyAgentConfig(state *store.State, config store.AgentConfig) {
+	config.ResourceVersion = state.NextResourceVersion()
+	state.AgentConfig = config
+
+	// if you explicitly set the agent to be offline, we set all endpoints
+	// to be offline
+	if config.ExpectedState == manager.AgentStateOffline {
+		for id, cfg := range state.EndpointConfigs {
+			if cfg.ExpectedState != manager.EndpointStateOffline {
+				cfg.ExpectedState = manager.EndpointStateOffline
+				cfg.ResourceVersion = state.NextResourceVersion()
+			}
+			state.EndpointConfigs[id] = cfg
+		}
+	}
+}
+
 func (h *Handler) GetAgent(c echo.Context) error {
 	// Build and return agent response
 	return h.buildAgentResponse(c)
@@ -69,11 +86,13 @@ func (h *Handler) buildAgentResponse(c echo.Context) error {
 
 	// Build response combining configuration and runtime status
 	response := AgentResponse{
-		AuthToken:     state.AgentConfig.AuthToken,
-		ConnectURL:    state.AgentConfig.ConnectURL,
-		ExpectedState: state.AgentConfig.ExpectedState,
-		Status:        agentStatus,
+		AuthToken:       state.AgentConfig.AuthToken,
+		ConnectURL:      state.AgentConfig.ConnectURL,
+		ExpectedState:   state.AgentConfig.ExpectedState,
+		ResourceVersion: state.AgentConfig.ResourceVersion,
+		Status:          agentStatus,
 	}
 
+	setETag(c, response.ResourceVersion)
 	return c.JSON(http.StatusOK, response)
 }
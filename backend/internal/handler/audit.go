@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/audit"
+)
+
+// GetAuditResponse defines the response body for GET /audit
+type GetAuditResponse struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+// GetAudit returns audit log entries, oldest first. Query parameters since
+// and until (RFC 3339), identity, endpoint and limit filter the entries.
+func (h *Handler) GetAudit(c echo.Context) error {
+	var filter audit.Filter
+	var err error
+
+	if since := c.QueryParam("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "since must be an RFC 3339 timestamp"})
+		}
+	}
+	if until := c.QueryParam("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "until must be an RFC 3339 timestamp"})
+		}
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be a non-negative integer"})
+		}
+	}
+	filter.Identity = c.QueryParam("identity")
+	filter.EndpointID = c.QueryParam("endpoint")
+
+	// Auditing is optional; without a log there is nothing to report
+	if h.Audit == nil {
+		return c.JSON(http.StatusOK, GetAuditResponse{Entries: []audit.Entry{}})
+	}
+
+	entries, err := h.Audit.Query(filter)
+	if err != nil {
+		h.logger.Error("failed to query audit log", "error", err)
+		return h.internalServerError(c, "Failed to read audit log")
+	}
+
+	return c.JSON(http.StatusOK, GetAuditResponse{Entries: entries})
+}
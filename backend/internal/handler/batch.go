@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Batch operation names accepted by POST /endpoints:batch
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+	BatchOpStart  = "start"
+	BatchOpStop   = "stop"
+)
+
+// Labels docker compose applies to the containers it manages
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+var errBatchAborted = errors.New("atomic batch aborted")
+
+// BatchRequest defines the request body for POST /endpoints:batch
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+	// Atomic discards every operation if any one of them fails
+	Atomic bool `json:"atomic,omitempty"`
+}
+
+// BatchOperation is a single create/update/delete/start/stop operation.
+// create and update take an Endpoint; delete, start and stop take either an
+// ID or a Selector.
+type BatchOperation struct {
+	Op       string            `json:"op"`
+	ID       string            `json:"id,omitempty"`
+	Selector *EndpointSelector `json:"selector,omitempty"`
+	Endpoint *EndpointRequest  `json:"endpoint,omitempty"`
+
+	// ResourceVersion, if set, must match the version of the endpoint named
+	// by ID. create and update use Endpoint.ResourceVersion instead.
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
+}
+
+// EndpointSelector matches existing endpoints. All non-empty fields must
+// match, and at least one must be set.
+type EndpointSelector struct {
+	ContainerID    string `json:"containerId,omitempty"`
+	ComposeProject string `json:"composeProject,omitempty"`
+	ComposeService string `json:"composeService,omitempty"`
+}
+
+// BatchResponse holds one result per requested operation, in request order
+type BatchResponse struct {
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult reports the outcome of a single batch operation
+type BatchResult struct {
+	Op        string             `json:"op"`
+	Status    int                `json:"status"`
+	Error     string             `json:"error,omitempty"`
+	IDs       []string           `json:"ids"`
+	Endpoints []EndpointResponse `json:"endpoints,omitempty"`
+}
+
+func (h *Handler) PostEndpointsBatch(c echo.Context) error {
+	// Parse request
+	var req BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(req.Operations) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "operations are required"})
+	}
+
+	// Validate every operation, and look up the compose labels selectors
+	// need before taking the store lock: containers are never inspected
+	// inside the transaction
+	results := make([]BatchResult, len(req.Operations))
+	needsLabels := false
+	for i, op := range req.Operations {
+		results[i] = resolveBatchOperation(op)
+		needsLabels = needsLabels || (results[i].Status == 0 && op.Selector != nil && op.Selector.needsLabels())
+	}
+	labels := make(containerLabels)
+	if needsLabels {
+		state, err := h.Store.Load()
+		if err != nil {
+			return h.internalServerError(c, "Failed to load configuration")
+		}
+		for _, config := range state.EndpointConfigs {
+			h.lookupLabels(c.Request().Context(), labels, config.ContainerID)
+		}
+		// Endpoints created earlier in the batch may be selected too
+		for i, op := range req.Operations {
+			if results[i].Status == 0 && op.Endpoint != nil {
+				h.lookupLabels(c.Request().Context(), labels, op.Endpoint.ContainerID)
+			}
+		}
+	}
+
+	// Select the targets and apply every operation in a single transaction,
+	// so that selectors match the state they change
+	applied := false
+	err := h.updateState(c, func(state *store.State) error {
+		failed := false
+		for i, op := range req.Operations {
+			if results[i].Status != 0 {
+				failed = true
+				continue
+			}
+			if op.Selector != nil {
+				results[i].IDs = selectEndpoints(state, *op.Selector, labels)
+				if len(results[i].IDs) == 0 {
+					results[i].Status = http.StatusNotFound
+					results[i].Error = "no endpoints matched the selector"
+					failed = true
+					continue
+				}
+			}
+			if err := applyBatchOperation(state, op, &results[i]); err != nil {
+				failed = true
+				continue
+			}
+			applied = true
+		}
+		if failed && req.Atomic {
+			return errBatchAborted
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		return h.internalServerError(c, "Failed to save endpoint configuration")
+	}
+	if errors.Is(err, errBatchAborted) {
+		applied = false
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = "not applied because another operation in the atomic batch failed"
+			}
+		}
+		return c.JSON(http.StatusUnprocessableEntity, BatchResponse{Applied: false, Results: results})
+	}
+
+	// Trigger a single convergence for the whole batch
+	if applied {
+		if err := h.Manager.Converge(c.Request().Context()); err != nil {
+			h.logger.Warn("convergence failed", "err", err)
+		}
+	}
+
+	// Fill in the resulting endpoints for successful operations
+	state, err := h.Store.Load()
+	if err != nil {
+		return h.internalServerError(c, "Failed to load configuration")
+	}
+	endpointStatuses := h.Manager.EndpointStatus()
+	for i := range results {
+		if results[i].Error != "" || results[i].Op == BatchOpDelete {
+			continue
+		}
+		for _, id := range results[i].IDs {
+			if config, exists := state.EndpointConfigs[id]; exists {
+				results[i].Endpoints = append(results[i].Endpoints, h.buildEndpointResponseNoLoad(config, endpointStatuses))
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, BatchResponse{Applied: applied, Results: results})
+}
+
+// resolveBatchOperation validates op and determines the endpoint IDs it
+// targets, except for selectors, which are matched when the operation is
+// applied. A non-zero Status in the returned result means the operation
+// failed and must not be applied.
+func resolveBatchOperation(op BatchOperation) BatchResult {
+	result := BatchResult{Op: op.Op, IDs: []string{}}
+	fail := func(status int, msg string) BatchResult {
+		result.Status = status
+		result.Error = msg
+		return result
+	}
+
+	switch op.Op {
+	case BatchOpCreate, BatchOpUpdate:
+		if op.Endpoint == nil {
+			return fail(http.StatusBadRequest, "endpoint is required")
+		}
+		req := op.Endpoint
+		endpointID, err := endpointRequestID(req)
+		if err != nil {
+			return fail(http.StatusBadRequest, err.Error())
+		}
+		if op.ID != "" && op.ID != endpointID {
+			return fail(http.StatusBadRequest, errEndpointIDMismatch.Error())
+		}
+		result.IDs = []string{endpointID}
+	case BatchOpDelete, BatchOpStart, BatchOpStop:
+		if (op.ID == "") == (op.Selector == nil) {
+			return fail(http.StatusBadRequest, "exactly one of id or selector is required")
+		}
+		if op.ID != "" {
+			result.IDs = []string{op.ID}
+			break
+		}
+		if *op.Selector == (EndpointSelector{}) {
+			return fail(http.StatusBadRequest, "selector must set at least one field")
+		}
+	default:
+		return fail(http.StatusBadRequest, fmt.Sprintf("unknown op %q", op.Op))
+	}
+
+	return result
+}
+
+// needsLabels reports whether matching the selector needs container labels
+func (s EndpointSelector) needsLabels() bool {
+	return s.ComposeProject != "" || s.ComposeService != ""
+}
+
+// containerLabels caches the labels of containers by ID
+type containerLabels map[string]map[string]string
+
+// selectEndpoints returns the sorted IDs of the endpoints in state matching
+// selector. Containers missing from labels have no labels: they were added
+// by another request after the labels were looked up.
+func selectEndpoints(state *store.State, selector EndpointSelector, labels containerLabels) []string {
+	needsLabels := selector.needsLabels()
+
+	ids := []string{}
+	for id, config := range state.EndpointConfigs {
+		if selector.ContainerID != "" && config.ContainerID != selector.ContainerID {
+			continue
+		}
+		if needsLabels {
+			// Upstream endpoints belong to no compose project
+			if config.ContainerID == "" {
+				continue
+			}
+			containerLabels := labels[config.ContainerID]
+			if selector.ComposeProject != "" && containerLabels[composeProjectLabel] != selector.ComposeProject {
+				continue
+			}
+			if selector.ComposeService != "" && containerLabels[composeServiceLabel] != selector.ComposeService {
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// lookupLabels returns the labels of a container, inspecting it unless they
+// are cached. Containers that no longer exist have no labels.
+func (h *Handler) lookupLabels(ctx context.Context, labels containerLabels, containerID string) map[string]string {
+	if containerID == "" {
+		return nil
+	}
+	if cached, exists := labels[containerID]; exists {
+		return cached
+	}
+	labels[containerID] = h.inspectLabels(ctx, containerID)
+	return labels[containerID]
+}
+
+func (h *Handler) inspectLabels(ctx context.Context, containerID string) map[string]string {
+	info, err := h.Docker.ContainerInspect(ctx, containerID)
+	if err != nil {
+		h.logger.Warn("failed to inspect container for selector", "containerId", containerID, "error", err)
+		return nil
+	}
+	if info.Config == nil {
+		return nil
+	}
+	return info.Config.Labels
+}
+
+// applyBatchOperation applies a resolved operation to state, recording any
+// failure in result
+func applyBatchOperation(state *store.State, op BatchOperation, result *BatchResult) error {
+	fail := func(status int, err error) error {
+		result.Status = status
+		result.Error = err.Error()
+		return err
+	}
+
+	resourceVersion := op.ResourceVersion
+	if op.Endpoint != nil {
+		resourceVersion = op.Endpoint.ResourceVersion
+	}
+
+	// Check every target before changing anything so that an operation is
+	// never partially applied
+	for _, id := range result.IDs {
+		config, exists := state.EndpointConfigs[id]
+		if op.Op == BatchOpCreate && exists {
+			return fail(http.StatusConflict, fmt.Errorf("endpoint %s already exists", id))
+		}
+		if op.Op != BatchOpCreate && !exists {
+			return fail(http.StatusNotFound, fmt.Errorf("endpoint %s: %w", id, errEndpointNotFound))
+		}
+		pre := precondition{resourceVersion: resourceVersion}
+		if err := pre.check(exists, config.ResourceVersion); err != nil {
+			return fail(http.StatusConflict, fmt.Errorf("endpoint %s: %w", id, err))
+		}
+	}
+
+	// Remember the targets so that an operation breaking a router's
+	// references can be undone
+	previous := make(map[string]store.EndpointConfig, len(result.IDs))
+	for _, id := range result.IDs {
+		if config, exists := state.EndpointConfigs[id]; exists {
+			previous[id] = config
+		}
+	}
+
+	for _, id := range result.IDs {
+		config := state.EndpointConfigs[id]
+		switch op.Op {
+		case BatchOpCreate, BatchOpUpdate:
+			applyEndpointRequest(state, id, *op.Endpoint)
+		case BatchOpDelete:
+			delete(state.EndpointConfigs, id)
+		case BatchOpStart:
+			config.ExpectedState = manager.EndpointStateOnline
+			config.LastStarted = time.Now().Format(time.RFC3339)
+			config.ResourceVersion = state.NextResourceVersion()
+			state.EndpointConfigs[id] = config
+			setAgentOnline(state)
+		case BatchOpStop:
+			config.ExpectedState = manager.EndpointStateOffline
+			config.ResourceVersion = state.NextResourceVersion()
+			state.EndpointConfigs[id] = config
+		}
+	}
+
+	for _, id := range result.IDs {
+		if err := manager.ValidateReferences(state.EndpointConfigs, id); err != nil {
+			for _, id := range result.IDs {
+				if config, existed := previous[id]; existed {
+					state.EndpointConfigs[id] = config
+				} else {
+					delete(state.EndpointConfigs, id)
+				}
+			}
+			status := http.StatusBadRequest
+			if op.Op == BatchOpDelete {
+				status = http.StatusConflict
+			}
+			return fail(status, err)
+		}
+	}
+
+	if op.Op == BatchOpCreate {
+		result.Status = http.StatusCreated
+	} else {
+		result.Status = http.StatusOK
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	// errPreconditionFailed means the If-Match header did not match the
+	// current resource version
+	errPreconditionFailed = errors.New("resource was modified: If-Match does not match the current ETag")
+	// errVersionConflict means the resourceVersion in the request body did
+	// not match the current resource version
+	errVersionConflict = errors.New("resource was modified: resourceVersion does not match the current version")
+)
+
+// precondition holds the optimistic concurrency requirements of a request.
+// Clients may send an If-Match header, or a resourceVersion in the body for
+// clients that cannot set headers.
+type precondition struct {
+	ifMatch         string
+	resourceVersion int64
+}
+
+// preconditionFromRequest reads the If-Match header and the body's
+// resourceVersion (0 if absent) into a precondition
+func preconditionFromRequest(c echo.Context, resourceVersion int64) precondition {
+	return precondition{
+		ifMatch:         strings.TrimSpace(c.Request().Header.Get("If-Match")),
+		resourceVersion: resourceVersion,
+	}
+}
+
+// check verifies the precondition against the current state of a resource
+func (p precondition) check(exists bool, currentVersion int64) error {
+	if p.ifMatch != "" && !ifMatchSatisfied(p.ifMatch, exists, currentVersion) {
+		return errPreconditionFailed
+	}
+	if p.resourceVersion != 0 && (!exists || p.resourceVersion != currentVersion) {
+		return errVersionConflict
+	}
+	return nil
+}
+
+// ifMatchSatisfied reports whether an If-Match header value matches the
+// resource, following RFC 9110 section 13.1.1
+func ifMatchSatisfied(ifMatch string, exists bool, currentVersion int64) bool {
+	if !exists {
+		return false
+	}
+	if ifMatch == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		// If-Match uses strong comparison, so weak tags never match
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+		if err == nil && version == currentVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// etag formats a resource version as a strong entity tag
+func etag(resourceVersion int64) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(resourceVersion, 10))
+}
+
+// setETag sets the ETag response header for a resource version
+func setETag(c echo.Context, resourceVersion int64) {
+	c.Response().Header().Set("ETag", etag(resourceVersion))
+}
+
+// preconditionErrorResponse writes the response for a failed precondition and
+// reports whether err was one
+func preconditionErrorResponse(c echo.Context, err error) (bool, error) {
+	switch {
+	case errors.Is(err, errPreconditionFailed):
+		return true, c.JSON(http.StatusPreconditionFailed, map[string]string{"error": err.Error()})
+	case errors.Is(err, errVersionConflict):
+		return true, c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return false, nil
+}
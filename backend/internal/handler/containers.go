@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/containers"
+)
+
+// ListContainersResponse defines the response body for GET /containers
+type ListContainersResponse struct {
+	Containers []containers.Container `json:"containers"`
+}
+
+// ListContainers returns the Docker container inventory. Stopped containers
+// are included with ?all=true.
+func (h *Handler) ListContainers(c echo.Context) error {
+	var all bool
+	if value := c.QueryParam("all"); value != "" {
+		var err error
+		if all, err = strconv.ParseBool(value); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "all must be a boolean"})
+		}
+	}
+
+	list, err := h.Containers.List(c.Request().Context(), all)
+	if err != nil {
+		h.logger.Error("failed to list containers", "error", err)
+		return h.internalServerError(c, "Failed to list containers")
+	}
+	return c.JSON(http.StatusOK, ListContainersResponse{Containers: list})
+}
+
+// DiscoverRequest defines the optional request body for
+// POST /containers/:id/discover
+type DiscoverRequest struct {
+	// ScanListening also lists the ports listening inside the container,
+	// by reading /proc/net/tcp with docker exec
+	ScanListening bool `json:"scanListening"`
+}
+
+// DiscoverContainerPorts probes the candidate ports of a container and
+// suggests an endpoint for each
+func (h *Handler) DiscoverContainerPorts(c echo.Context) error {
+	var req DiscoverRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	discovery, err := h.Containers.Discover(c.Request().Context(), c.Param("id"), req.ScanListening)
+	switch {
+	case errdefs.IsNotFound(err):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Container not found"})
+	case errors.Is(err, containers.ErrNotRunning):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case err != nil:
+		h.logger.Error("failed to discover container ports", "container", c.Param("id"), "error", err)
+		return h.internalServerError(c, "Failed to inspect container")
+	}
+	return c.JSON(http.StatusOK, discovery)
+}
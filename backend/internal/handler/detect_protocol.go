@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"context"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
-	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
 )
 
 // Detect Protocol Types
@@ -17,10 +16,13 @@ type DetectProtocolRequest struct {
 }
 
 type DetectProtocolResponse struct {
-	TCP   bool `json:"tcp"`
-	HTTP  bool `json:"http"`
-	HTTPS bool `json:"https"`
-	TLS   bool `json:"tls"`
+	TCP        bool      `json:"tcp"`
+	HTTP       bool      `json:"http"`
+	HTTPS      bool      `json:"https"`
+	TLS        bool      `json:"tls"`
+	HTTPPath   string    `json:"httpPath,omitempty"` // the first probe path served without a 404
+	Error      string    `json:"error,omitempty"`    // why the port could not be reached
+	DetectedAt time.Time `json:"detectedAt"`
 }
 
 func (h *Handler) DetectProtocol(c echo.Context) error {
@@ -37,22 +39,20 @@ func (h *Handler) DetectProtocol(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "port required"})
 	}
 
-	// Detect protocols on the port with 250ms timeout
-	detectCtx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
-	defer cancel()
-
-	// 172.17.0.1 is docker's bridge IP where containers listen
-	d := detectproto.NewDetector()
-	result, err := d.Detect(detectCtx, "172.17.0.1", req.Port)
+	// Detect protocols on the port, reusing the result the manager sees
+	result, err := h.Detector.DetectContainer(c.Request().Context(), req.ContainerID, manager.DockerBridgeHost, req.Port)
 	if err != nil {
 		return h.internalServerError(c, err.Error())
 	}
 
 	response := DetectProtocolResponse{
-		TCP:   result.TCP,
-		HTTP:  result.HTTP,
-		HTTPS: result.HTTPS,
-		TLS:   result.TLS,
+		TCP:        result.TCP,
+		HTTP:       result.HTTP,
+		HTTPS:      result.HTTPS,
+		TLS:        result.TLS,
+		HTTPPath:   result.HTTPPath,
+		Error:      result.Error,
+		DetectedAt: result.DetectedAt,
 	}
 
 	return c.JSON(http.StatusOK, response)
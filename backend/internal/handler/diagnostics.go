@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// diagnosticsTimeout bounds a full diagnostics run
+const diagnosticsTimeout = 15 * time.Second
+
+// GetDiagnostics runs preflight checks against Docker, the state file, the
+// agent and every configured endpoint. Failed checks are reported in the
+// body; the response status is always 200.
+func (h *Handler) GetDiagnostics(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), diagnosticsTimeout)
+	defer cancel()
+
+	return c.JSON(http.StatusOK, h.Diagnostics.Run(ctx))
+}
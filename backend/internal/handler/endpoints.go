@@ -15,6 +15,14 @@ import (
 
 var errEndpointNotFound = errors.New("endpoint not found")
 
+// errEndpointIDMismatch is returned when the ID of an endpoint does not match
+// the target in the request
+var errEndpointIDMismatch = errors.New("endpoint ID must match containerId:targetPort, upstream:host:port for upstream endpoints or router:host for routers")
+
+// errInvalidReference is returned when a change would leave a router
+// forwarding to an endpoint that does not exist or is not internal
+var errInvalidReference = errors.New("invalid reference")
+
 // GET /endpoints Types (new state management)
 type GetEndpointsResponse struct {
 	Endpoints []EndpointResponse `json:"endpoints"`
@@ -24,8 +32,10 @@ type GetEndpointsResponse struct {
 type EndpointResponse struct {
 	// Configuration fields (from persistent store)
 	ID             string `json:"id"`
+	TargetType     string `json:"targetType"` // "container" | "upstream" | "router"
 	ContainerID    string `json:"containerId"`
 	TargetPort     string `json:"targetPort"`
+	Upstream       string `json:"upstream,omitempty"`
 	URL            string `json:"url,omitempty"`
 	Binding        string `json:"binding,omitempty"`
 	PoolingEnabled bool   `json:"poolingEnabled"`
@@ -34,15 +44,34 @@ type EndpointResponse struct {
 	Metadata       string `json:"metadata,omitempty"`
 	ExpectedState  string `json:"expectedState"`
 	LastStarted    string `json:"lastStarted,omitempty"`
+	ProxyProtocol  string `json:"proxyProtocol,omitempty"`
+	TLSTermination string `json:"tlsTermination,omitempty"`
+	ForwardedFor   string `json:"forwardedFor,omitempty"`
+	RealIP         bool   `json:"realIP,omitempty"`
+	HostHeader     string `json:"hostHeader,omitempty"`
+
+	// Routes send requests matching a path prefix to other ports of the
+	// container
+	Routes []store.Route `json:"routes,omitempty"`
+
+	// Router forwards requests to internal endpoints by host and path
+	Router []store.RouterRule `json:"router,omitempty"`
+
+	// ResourceVersion is also returned as the ETag header by single-endpoint
+	// routes
+	ResourceVersion int64 `json:"resourceVersion"`
 
 	// Runtime state (from endpoint manager)
 	Status manager.EndpointStatus `json:"status"`
 }
 
-// EndpointRequest defines the request body for POST /endpoints and PUT /endpoints/:id
+// EndpointRequest defines the request body for POST /endpoints and PUT /endpoints/:id.
+// An endpoint targets either a container port or an upstream URL, or is a
+// router forwarding to internal endpoints.
 type EndpointRequest struct {
 	ContainerID    string `json:"containerId"`
 	TargetPort     string `json:"targetPort"`
+	Upstream       string `json:"upstream,omitempty"` // e.g. http://host.docker.internal:3000 or tcp://db:5432
 	URL            string `json:"url,omitempty"`
 	Binding        string `json:"binding,omitempty"`
 	PoolingEnabled bool   `json:"poolingEnabled,omitempty"`
@@ -50,6 +79,27 @@ type EndpointRequest struct {
 	Description    string `json:"description,omitempty"`
 	Metadata       string `json:"metadata,omitempty"`
 	ExpectedState  string `json:"expectedState"`
+
+	// TCP and TLS endpoints only
+	ProxyProtocol  string `json:"proxyProtocol,omitempty"`  // "v1" | "v2"
+	TLSTermination string `json:"tlsTermination,omitempty"` // "passthrough" (default) | "edge"
+
+	// HTTP endpoints only
+	ForwardedFor string `json:"forwardedFor,omitempty"` // "append" (default) | "replace" | "remove"
+	RealIP       bool   `json:"realIP,omitempty"`
+	HostHeader   string `json:"hostHeader,omitempty"` // "rewrite" or a host
+
+	// Routes send requests matching a path prefix to other ports of the
+	// container; other requests go to TargetPort
+	Routes []store.Route `json:"routes,omitempty"`
+
+	// Router makes the endpoint a router forwarding to internal endpoints by
+	// host and path. Routers are identified by their URL.
+	Router []store.RouterRule `json:"router,omitempty"`
+
+	// ResourceVersion, if set, must match the stored endpoint's version.
+	// Equivalent to If-Match for clients that cannot set headers.
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
 }
 
 func (h *Handler) PostEndpoints(c echo.Context) error {
@@ -59,16 +109,21 @@ func (h *Handler) PostEndpoints(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	// Validate required fields
-	if err := h.validateEndpointRequest(c, req.ContainerID, req.TargetPort, req.ExpectedState); err != nil {
-		return err
+	// Validate required fields and derive the endpoint ID from the target
+	endpointID, err := endpointRequestID(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	// Create endpoint ID as containerID:targetPort
-	endpointID := fmt.Sprintf("%s:%s", req.ContainerID, req.TargetPort)
-
 	// Update state atomically
-	if err := h.updateEndpointConfigInStore(endpointID, req); err != nil {
+	pre := preconditionFromRequest(c, req.ResourceVersion)
+	if err := h.updateEndpointConfigInStore(c, endpointID, req, pre); err != nil {
+		if errors.Is(err, errInvalidReference) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
 		return h.internalServerError(c, "Failed to save endpoint configuration")
 	}
 
@@ -83,6 +138,7 @@ func (h *Handler) PostEndpoints(c echo.Context) error {
 		return h.internalServerError(c, err.Error())
 	}
 
+	setETag(c, endpoint.ResourceVersion)
 	return c.JSON(http.StatusCreated, endpoint)
 }
 
@@ -122,7 +178,7 @@ func (h *Handler) GetEndpoints(c echo.Context) error {
 
 func (h *Handler) GetEndpointByID(c echo.Context) error {
 	// Get endpoint ID from URL parameter
-	endpointID := c.Param("id")
+	endpointID := endpointIDParam(c)
 	if endpointID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID is required"})
 	}
@@ -136,12 +192,13 @@ func (h *Handler) GetEndpointByID(c echo.Context) error {
 		return h.internalServerError(c, err.Error())
 	}
 
+	setETag(c, endpoint.ResourceVersion)
 	return c.JSON(http.StatusOK, endpoint)
 }
 
 func (h *Handler) PutEndpointByID(c echo.Context) error {
 	// Get endpoint ID from URL parameter
-	endpointID := c.Param("id")
+	endpointID := endpointIDParam(c)
 	if endpointID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID is required"})
 	}
@@ -153,18 +210,25 @@ func (h *Handler) PutEndpointByID(c echo.Context) error {
 	}
 
 	// Validate required fields
-	if err := h.validateEndpointRequest(c, req.ContainerID, req.TargetPort, req.ExpectedState); err != nil {
-		return err
+	expectedID, err := endpointRequestID(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	// Verify that the endpoint ID matches containerID:targetPort
-	expectedID := fmt.Sprintf("%s:%s", req.ContainerID, req.TargetPort)
+	// Verify that the endpoint ID matches the target
 	if endpointID != expectedID {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID must match containerId:targetPort"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": errEndpointIDMismatch.Error()})
 	}
 
 	// Update endpoint configuration
-	if err := h.updateEndpointConfigInStore(endpointID, req); err != nil {
+	pre := preconditionFromRequest(c, req.ResourceVersion)
+	if err := h.updateEndpointConfigInStore(c, endpointID, req, pre); err != nil {
+		if errors.Is(err, errInvalidReference) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
 		return h.internalServerError(c, "Failed to save endpoint configuration")
 	}
 
@@ -179,30 +243,38 @@ func (h *Handler) PutEndpointByID(c echo.Context) error {
 		return h.internalServerError(c, err.Error())
 	}
 
+	setETag(c, endpoint.ResourceVersion)
 	return c.JSON(http.StatusOK, endpoint)
 }
 
 func (h *Handler) DeleteEndpointByID(c echo.Context) error {
 	// Get endpoint ID from URL parameter
-	endpointID := c.Param("id")
+	endpointID := endpointIDParam(c)
 	if endpointID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID is required"})
 	}
 
 	// Update state atomically to remove the endpoint
-	err := h.Store.Update(func(state *store.State) error {
+	pre := preconditionFromRequest(c, 0)
+	err := h.updateState(c, func(state *store.State) error {
 		// Check if endpoint exists
 		if state.EndpointConfigs == nil {
 			return errEndpointNotFound
 		}
 
-		_, exists := state.EndpointConfigs[endpointID]
+		config, exists := state.EndpointConfigs[endpointID]
 		if !exists {
 			return errEndpointNotFound
 		}
+		if err := pre.check(exists, config.ResourceVersion); err != nil {
+			return err
+		}
 
-		// Remove the endpoint configuration
+		// Remove the endpoint configuration, unless a router forwards to it
 		delete(state.EndpointConfigs, endpointID)
+		if err := manager.ValidateReferences(state.EndpointConfigs, endpointID); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidReference, err)
+		}
 
 		return nil
 	})
@@ -210,6 +282,12 @@ func (h *Handler) DeleteEndpointByID(c echo.Context) error {
 		if errors.Is(err, errEndpointNotFound) {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "Endpoint not found"})
 		}
+		if errors.Is(err, errInvalidReference) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
 		return h.internalServerError(c, "Failed to remove endpoint configuration")
 	}
 
@@ -225,66 +303,136 @@ func (h *Handler) DeleteEndpointByID(c echo.Context) error {
 // Helper functions
 
 // updateEndpointConfigInStore creates/updates endpoint configuration in store
-func (h *Handler) updateEndpointConfigInStore(endpointID string, req EndpointRequest) error {
-	return h.Store.Update(func(state *store.State) error {
-		// Initialize EndpointConfigs map if nil
-		if state.EndpointConfigs == nil {
-			state.EndpointConfigs = make(map[string]store.EndpointConfig)
+func (h *Handler) updateEndpointConfigInStore(c echo.Context, endpointID string, req EndpointRequest, pre precondition) error {
+	return h.updateState(c, func(state *store.State) error {
+		existing, exists := state.EndpointConfigs[endpointID]
+		if err := pre.check(exists, existing.ResourceVersion); err != nil {
+			return err
 		}
-
-		// Get existing config to preserve LastStarted field
-		existingConfig, exists := state.EndpointConfigs[endpointID]
-
-		// Create endpoint configuration
-		endpointConfig := store.EndpointConfig{
-			ID:             endpointID,
-			ContainerID:    req.ContainerID,
-			TargetPort:     req.TargetPort,
-			ExpectedState:  req.ExpectedState,
-			URL:            req.URL,
-			Binding:        req.Binding,
-			PoolingEnabled: req.PoolingEnabled,
-			TrafficPolicy:  req.TrafficPolicy,
-			Description:    req.Description,
-			Metadata:       req.Metadata,
+		applyEndpointRequest(state, endpointID, req)
+		if err := manager.ValidateReferences(state.EndpointConfigs, endpointID); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidReference, err)
 		}
+		return nil
+	})
+}
 
-		// Handle LastStarted field
-		if req.ExpectedState == manager.EndpointStateOnline {
-			// Set LastStarted to current time when going online
-			endpointConfig.LastStarted = time.Now().Format(time.RFC3339)
-		} else if exists {
-			// Preserve existing LastStarted value when going offline
-			endpointConfig.LastStarted = existingConfig.LastStarted
-		}
+// applyEndpointRequest writes the endpoint configuration described by req into
+// state, preserving LastStarted and bringing the agent online as needed
+func applyEndpointRequest(state *store.State, endpointID string, req EndpointRequest) {
+	// Initialize EndpointConfigs map if nil
+	if state.EndpointConfigs == nil {
+		state.EndpointConfigs = make(map[string]store.EndpointConfig)
+	}
 
-		// Store the endpoint configuration
-		state.EndpointConfigs[endpointID] = endpointConfig
+	// Get existing config to preserve LastStarted field
+	existingConfig, exists := state.EndpointConfigs[endpointID]
+
+	// Create endpoint configuration
+	endpointConfig := store.EndpointConfig{
+		ID:             endpointID,
+		ContainerID:    req.ContainerID,
+		TargetPort:     req.TargetPort,
+		Upstream:       req.Upstream,
+		ExpectedState:  req.ExpectedState,
+		URL:            req.URL,
+		Binding:        req.Binding,
+		PoolingEnabled: req.PoolingEnabled,
+		TrafficPolicy:  req.TrafficPolicy,
+		Description:    req.Description,
+		Metadata:       req.Metadata,
+		ProxyProtocol:  req.ProxyProtocol,
+		TLSTermination: req.TLSTermination,
+		ForwardedFor:   req.ForwardedFor,
+		RealIP:         req.RealIP,
+		HostHeader:     req.HostHeader,
+		Routes:         req.Routes,
+		Router:         req.Router,
+
+		ResourceVersion: state.NextResourceVersion(),
+	}
 
-		// Ensure agent is set to online when endpoints with expectedState=online are created/updated
-		if endpointConfig.ExpectedState == manager.EndpointStateOnline {
-			state.AgentConfig.ExpectedState = manager.EndpointStateOnline
-		}
+	// Handle LastStarted field
+	if req.ExpectedState == manager.EndpointStateOnline {
+		// Set LastStarted to current time when going online
+		endpointConfig.LastStarted = time.Now().Format(time.RFC3339)
+	} else if exists {
+		// Preserve existing LastStarted value when going offline
+		endpointConfig.LastStarted = existingConfig.LastStarted
+	}
 
-		return nil
-	})
+	// Store the endpoint configuration
+	state.EndpointConfigs[endpointID] = endpointConfig
+
+	// Ensure agent is set to online when endpoints with expectedState=online are created/updated
+	if endpointConfig.ExpectedState == manager.EndpointStateOnline {
+		setAgentOnline(state)
+	}
+}
+
+// setAgentOnline sets the agent's expected state to online, bumping its
+// resource version if that changes it
+func setAgentOnline(state *store.State) {
+	if state.AgentConfig.ExpectedState == manager.AgentStateOnline {
+		return
+	}
+	state.AgentConfig.ExpectedState = manager.AgentStateOnline
+	state.AgentConfig.ResourceVersion = state.NextResourceVersion()
 }
 
-// validateEndpointRequest validates required fields for endpoint requests
-func (h *Handler) validateEndpointRequest(c echo.Context, containerID, targetPort, expectedState string) error {
-	if containerID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "containerId is required"})
+// endpointRequestID validates an endpoint request, normalizing its upstream
+// URL and binding, and returns the ID of the endpoint it describes
+func endpointRequestID(req *EndpointRequest) (string, error) {
+	var id string
+	if len(req.Router) > 0 {
+		routerID, err := manager.RouterID(req.URL)
+		if err != nil {
+			return "", err
+		}
+		id = routerID
+	} else {
+		targetID, upstream, err := manager.EndpointTarget(req.ContainerID, req.TargetPort, req.Upstream)
+		if err != nil {
+			return "", err
+		}
+		id = targetID
+		req.Upstream = upstream
+	}
+
+	binding, err := manager.EndpointBinding(req.URL, req.Binding)
+	if err != nil {
+		return "", err
+	}
+	req.Binding = binding
+
+	config := store.EndpointConfig{
+		ContainerID:    req.ContainerID,
+		TargetPort:     req.TargetPort,
+		Upstream:       req.Upstream,
+		URL:            req.URL,
+		Binding:        req.Binding,
+		TrafficPolicy:  req.TrafficPolicy,
+		Metadata:       req.Metadata,
+		ProxyProtocol:  req.ProxyProtocol,
+		TLSTermination: req.TLSTermination,
+		ForwardedFor:   req.ForwardedFor,
+		RealIP:         req.RealIP,
+		HostHeader:     req.HostHeader,
+		Routes:         req.Routes,
+		Router:         req.Router,
 	}
-	if targetPort == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "targetPort is required"})
+	if err := manager.ValidateEndpoint(config); err != nil {
+		return "", err
 	}
-	if expectedState == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expectedState is required"})
+	// Stored explicitly, since TLS endpoints without one predate the option
+	req.TLSTermination = manager.EndpointTLSTermination(config)
+	if req.ExpectedState == "" {
+		return "", errors.New("expectedState is required")
 	}
-	if expectedState != manager.EndpointStateOnline && expectedState != manager.EndpointStateOffline {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expectedState must be 'online' or 'offline'"})
+	if req.ExpectedState != manager.EndpointStateOnline && req.ExpectedState != manager.EndpointStateOffline {
+		return "", errors.New("expectedState must be 'online' or 'offline'")
 	}
-	return nil
+	return id, nil
 }
 
 // getEndpointResponse loads state, finds endpoint config, and builds response
@@ -325,8 +473,10 @@ func (h *Handler) buildEndpointResponseNoLoad(config store.EndpointConfig, endpo
 
 	return EndpointResponse{
 		ID:             config.ID,
+		TargetType:     manager.TargetType(config),
 		ContainerID:    config.ContainerID,
 		TargetPort:     config.TargetPort,
+		Upstream:       config.Upstream,
 		URL:            config.URL,
 		Binding:        config.Binding,
 		PoolingEnabled: config.PoolingEnabled,
@@ -335,6 +485,15 @@ func (h *Handler) buildEndpointResponseNoLoad(config store.EndpointConfig, endpo
 		Metadata:       config.Metadata,
 		ExpectedState:  config.ExpectedState,
 		LastStarted:    config.LastStarted,
-		Status:         status,
+		ProxyProtocol:  config.ProxyProtocol,
+		TLSTermination: config.TLSTermination,
+		ForwardedFor:   config.ForwardedFor,
+		RealIP:         config.RealIP,
+		HostHeader:     config.HostHeader,
+		Routes:         config.Routes,
+		Router:         config.Router,
+
+		ResourceVersion: config.ResourceVersion,
+		Status:          status,
 	}
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/gitops"
+)
+
+// GetGitOpsResponse defines the response body for GET /gitops
+type GetGitOpsResponse struct {
+	Enabled bool           `json:"enabled"`
+	Source  *gitops.Status `json:"source,omitempty"`
+	Drift   []gitops.Drift `json:"drift"`
+}
+
+// GetGitOps reports whether the configuration file owns the desired state
+// and how the runtime differs from it
+func (h *Handler) GetGitOps(c echo.Context) error {
+	if h.GitOps == nil {
+		return c.JSON(http.StatusOK, GetGitOpsResponse{Drift: []gitops.Drift{}})
+	}
+
+	state, err := h.GitOps.Load()
+	if err != nil {
+		h.logger.Error("failed to load configuration file", "error", err)
+		return h.internalServerError(c, "Failed to load configuration")
+	}
+
+	status := h.GitOps.Status()
+	return c.JSON(http.StatusOK, GetGitOpsResponse{
+		Enabled: true,
+		Source:  &status,
+		Drift:   gitops.DetectDrift(state, h.Manager.AgentStatus(), h.Manager.EndpointStatus()),
+	})
+}
+
+// requireWritable rejects changes to the desired state while it is owned by
+// a configuration file
+func (h *Handler) requireWritable(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if h.GitOps != nil {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": fmt.Sprintf("configuration is managed by %s (GitOps mode); edit that file instead", h.GitOps.Path()),
+			})
+		}
+		return next(c)
+	}
+}
@@ -3,9 +3,19 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/audit"
+	"github.com/ngrok/ngrok-docker-extension/internal/containers"
+	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/gitops"
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
 	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
 )
 
@@ -13,26 +23,141 @@ type Handler struct {
 	logger  *slog.Logger
 	Manager manager.Manager
 	Store   store.Store
+	Docker  manager.DockerClient
+	Audit   *audit.Log
+	GitOps  *gitops.Store
+	Secrets *secrets.Store
+
+	// AccessLog must be the log the manager records endpoint traffic to
+	AccessLog *accesslog.Log
+
+	// Logs must be the logs the backend's loggers write to
+	Logs *logging.Logs
+
+	// Detector is used by diagnostics and the container inventory to probe
+	// ports
+	Detector    manager.ProtocolDetector
+	Diagnostics *diagnostics.Checker
+	Containers  *containers.Lister
+
+	// StuckConvergeThreshold is how long a convergence may run before
+	// GET /healthz fails
+	StuckConvergeThreshold time.Duration
+}
+
+// updateState applies fn to the state in a single transaction, recording
+// its changes in the audit log entry of the request c
+func (h *Handler) updateState(c echo.Context, fn func(*store.State) error) error {
+	return audit.Update(c, h.Store, fn)
+}
+
+// endpointIDParam returns the endpoint ID of the request path. Clients escape
+// IDs such as upstream:[::1]:3000, and Echo leaves path parameters as sent.
+func endpointIDParam(c echo.Context) string {
+	id, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return c.Param("id")
+	}
+	return id
+}
+
+// Option configures optional Handler dependencies
+type Option func(*Handler)
+
+// WithProtocolDetector overrides the detector used to probe endpoint ports
+func WithProtocolDetector(detector manager.ProtocolDetector) Option {
+	return func(h *Handler) {
+		h.Detector = detector
+	}
+}
+
+// WithGitOps makes the configuration file behind source the owner of the
+// desired state. Mutating routes return 409.
+func WithGitOps(source *gitops.Store) Option {
+	return func(h *Handler) {
+		h.GitOps = source
+	}
 }
 
-func New(e *echo.Echo, mgr manager.Manager, store store.Store, logger *slog.Logger) *Handler {
+// WithSecrets serves /secrets from secrets, which must be the store the
+// manager resolves references from
+func WithSecrets(secretStore *secrets.Store) Option {
+	return func(h *Handler) {
+		h.Secrets = secretStore
+	}
+}
+
+// WithAccessLog serves the access logs of endpoints from log
+func WithAccessLog(log *accesslog.Log) Option {
+	return func(h *Handler) {
+		h.AccessLog = log
+	}
+}
+
+// WithLogs serves the backend's logs and their levels from logs
+func WithLogs(logs *logging.Logs) Option {
+	return func(h *Handler) {
+		h.Logs = logs
+	}
+}
+
+// WithAuditLog serves GET /audit from log
+func WithAuditLog(log *audit.Log) Option {
+	return func(h *Handler) {
+		h.Audit = log
+	}
+}
+
+func New(e *echo.Echo, mgr manager.Manager, store store.Store, docker manager.DockerClient, logger *slog.Logger, opts ...Option) *Handler {
 	h := &Handler{
-		logger:  logger,
-		Manager: mgr,
-		Store:   store,
+		logger:   logger,
+		Manager:  mgr,
+		Store:    store,
+		Docker:   docker,
+		Detector: detectproto.NewCache(detectproto.NewDetector(), detectproto.DefaultCacheTTL, nil),
+		Secrets:  secrets.NewMemoryStore(),
+
+		StuckConvergeThreshold: DefaultStuckConvergeThreshold,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	h.Diagnostics = diagnostics.NewChecker(store, mgr, docker, h.Detector)
+	h.Containers = containers.NewLister(docker, h.Detector, store)
 
 	// State management routes
-	e.PUT("/agent", h.PutAgent)
+	e.PUT("/agent", h.PutAgent, h.requireWritable)
 	e.GET("/agent", h.GetAgent)
-	e.POST("/endpoints", h.PostEndpoints)
+	e.PATCH("/agent", h.PatchAgent, h.requireWritable)
+	e.POST("/endpoints", h.PostEndpoints, h.requireWritable)
 	e.GET("/endpoints", h.GetEndpoints)
 	e.GET("/endpoints/:id", h.GetEndpointByID)
-	e.PUT("/endpoints/:id", h.PutEndpointByID)
-	e.DELETE("/endpoints/:id", h.DeleteEndpointByID)
+	e.PUT("/endpoints/:id", h.PutEndpointByID, h.requireWritable)
+	e.PATCH("/endpoints/:id", h.PatchEndpointByID, h.requireWritable)
+	e.DELETE("/endpoints/:id", h.DeleteEndpointByID, h.requireWritable)
+	e.GET("/endpoints/:id/logs", h.GetEndpointLogs)
+	e.POST("/endpoints\\:batch", h.PostEndpointsBatch, h.requireWritable)
+
+	// Secret routes. Secrets are not part of the desired state, so they stay
+	// writable in GitOps mode.
+	e.GET("/secrets", h.ListSecrets)
+	e.PUT("/secrets/:name", h.PutSecret)
+	e.DELETE("/secrets/:name", h.DeleteSecret)
 
 	// Utility routes
 	e.POST("/detect_protocol", h.DetectProtocol)
+	e.GET("/openapi.json", h.GetOpenAPI)
+	e.GET("/audit", h.GetAudit)
+	e.GET("/logs", h.GetLogs)
+	e.GET("/logging", h.GetLogging)
+	e.PUT("/logging", h.PutLogging)
+	e.GET("/diagnostics", h.GetDiagnostics)
+	e.GET("/healthz", h.GetHealthz)
+	e.GET("/readyz", h.GetReadyz)
+	e.GET("/gitops", h.GetGitOps)
+	e.GET("/topology", h.GetTopology)
+	e.GET("/containers", h.ListContainers)
+	e.POST("/containers/:id/discover", h.DiscoverContainerPorts)
 
 	return h
 }
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+)
+
+// DefaultStuckConvergeThreshold is how long a convergence may hold the
+// manager's lock before the backend is reported unhealthy
+const DefaultStuckConvergeThreshold = 2 * time.Minute
+
+// healthCheckTimeout bounds each check that calls a dependency
+const healthCheckTimeout = 2 * time.Second
+
+// HealthResponse defines the response body for GET /healthz and GET /readyz
+type HealthResponse struct {
+	Status diagnostics.Status  `json:"status"` // "pass" | "fail"
+	Checks []diagnostics.Check `json:"checks"`
+}
+
+// WithStuckConvergeThreshold overrides how long a convergence may run before
+// GET /healthz fails
+func WithStuckConvergeThreshold(threshold time.Duration) Option {
+	return func(h *Handler) {
+		h.StuckConvergeThreshold = threshold
+	}
+}
+
+// GetHealthz reports whether the backend is alive: it fails when a
+// convergence has held the manager's lock for longer than the stuck converge
+// threshold, for example because an ngrok SDK call hangs. Endpoint operations
+// the watchdog reported as slow are a warning.
+func (h *Handler) GetHealthz(c echo.Context) error {
+	return h.healthResponse(c, h.checkConvergeLoop(), h.checkEndpointOperations())
+}
+
+// GetReadyz reports whether the backend is ready to serve: the initial
+// convergence has finished, the stored state loads and Docker is reachable
+func (h *Handler) GetReadyz(c echo.Context) error {
+	return h.healthResponse(c,
+		h.checkConvergeLoop(),
+		h.checkInitialConvergence(),
+		h.checkStore(),
+		h.checkDocker(c.Request().Context()),
+	)
+}
+
+// healthResponse responds 200 when every check passes and 503 otherwise
+func (h *Handler) healthResponse(c echo.Context, checks ...diagnostics.Check) error {
+	resp := HealthResponse{Status: diagnostics.StatusPass, Checks: checks}
+	for _, check := range checks {
+		if check.Status == diagnostics.StatusFail {
+			resp.Status = diagnostics.StatusFail
+		}
+	}
+	if resp.Status == diagnostics.StatusFail {
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) checkConvergeLoop() diagnostics.Check {
+	check := diagnostics.Check{Name: "converge_loop", Status: diagnostics.StatusPass}
+	running := h.Manager.ConvergeStatus().RunningFor(time.Now())
+	if running > h.StuckConvergeThreshold {
+		check.Status = diagnostics.StatusFail
+		check.Message = fmt.Sprintf("Convergence has been running for %s", running.Round(time.Second))
+		return check
+	}
+	check.Message = "Convergence is not stuck"
+	return check
+}
+
+func (h *Handler) checkEndpointOperations() diagnostics.Check {
+	check := diagnostics.Check{Name: "endpoint_operations", Status: diagnostics.StatusPass}
+	var slow []string
+	for _, op := range h.Manager.ConvergeStatus().Operations {
+		if op.Slow {
+			slow = append(slow, fmt.Sprintf("%s of %s", op.Kind, op.EndpointID))
+		}
+	}
+	if len(slow) > 0 {
+		check.Status = diagnostics.StatusWarn
+		check.Message = fmt.Sprintf("Slow endpoint operations: %s", strings.Join(slow, ", "))
+		return check
+	}
+	check.Message = "No endpoint operation is slow"
+	return check
+}
+
+func (h *Handler) checkInitialConvergence() diagnostics.Check {
+	check := diagnostics.Check{Name: "initial_convergence"}
+	status := h.Manager.ConvergeStatus()
+	switch {
+	case !status.Converged:
+		check.Status = diagnostics.StatusFail
+		check.Message = "Initial convergence has not finished"
+	case status.LastError != "":
+		// Later convergences retry, so an error does not make the backend
+		// unready
+		check.Status = diagnostics.StatusPass
+		check.Message = fmt.Sprintf("Last convergence failed: %s", status.LastError)
+	default:
+		check.Status = diagnostics.StatusPass
+		check.Message = "Initial convergence has finished"
+	}
+	return check
+}
+
+func (h *Handler) checkStore() diagnostics.Check {
+	check := diagnostics.Check{Name: "store"}
+	if _, err := h.Store.Load(); err != nil {
+		check.Status = diagnostics.StatusFail
+		check.Message = fmt.Sprintf("State cannot be loaded: %v", err)
+		return check
+	}
+	check.Status = diagnostics.StatusPass
+	check.Message = "State loads"
+	return check
+}
+
+func (h *Handler) checkDocker(ctx context.Context) diagnostics.Check {
+	check := diagnostics.Check{Name: "docker"}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if _, err := h.Docker.Ping(ctx); err != nil {
+		check.Status = diagnostics.StatusFail
+		check.Message = fmt.Sprintf("Docker socket is unreachable: %v", err)
+		return check
+	}
+	check.Status = diagnostics.StatusPass
+	check.Message = "Docker is reachable"
+	return check
+}
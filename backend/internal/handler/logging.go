@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
+)
+
+// GetLogsResponse defines the response body for GET /logs
+type GetLogsResponse struct {
+	Entries []logging.Entry `json:"entries"`
+}
+
+// GetLogging returns the levels of the backend's logs
+func (h *Handler) GetLogging(c echo.Context) error {
+	if h.Logs == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Logging is not configured"})
+	}
+	return c.JSON(http.StatusOK, h.Logs.Config())
+}
+
+// PutLogging replaces the levels of the backend's logs until it restarts.
+// Subsystems without a level of their own follow the default level.
+func (h *Handler) PutLogging(c echo.Context) error {
+	if h.Logs == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Logging is not configured"})
+	}
+
+	var req logging.Config
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.Logs.SetConfig(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	config := h.Logs.Config()
+	h.logger.Info("Log levels changed", "default", config.Level, "subsystems", config.Subsystems)
+	return c.JSON(http.StatusOK, config)
+}
+
+// GetLogs returns the backend's logs, oldest first. Query parameters since
+// (RFC 3339), level (the minimum), subsystem and limit filter the entries.
+// follow=true streams them as newline delimited JSON, then every matching
+// entry written until the client disconnects.
+func (h *Handler) GetLogs(c echo.Context) error {
+	var filter logging.Filter
+	var err error
+
+	if since := c.QueryParam("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "since must be an RFC 3339 timestamp"})
+		}
+	}
+	if level := c.QueryParam("level"); level != "" {
+		minimum, err := logging.ParseLevel(level)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		filter.Level = minimum
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be a non-negative integer"})
+		}
+	}
+	filter.Subsystem = c.QueryParam("subsystem")
+
+	follow := false
+	if v := c.QueryParam("follow"); v != "" {
+		if follow, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "follow must be true or false"})
+		}
+	}
+
+	if h.Logs == nil {
+		if follow {
+			c.Response().Header().Set(echo.HeaderContentType, ndjsonContentType)
+			return c.NoContent(http.StatusOK)
+		}
+		return c.JSON(http.StatusOK, GetLogsResponse{Entries: []logging.Entry{}})
+	}
+
+	if !follow {
+		entries, err := h.Logs.Query(filter)
+		if err != nil {
+			h.logger.Error("failed to query logs", "error", err)
+			return h.internalServerError(c, "Failed to read logs")
+		}
+		return c.JSON(http.StatusOK, GetLogsResponse{Entries: entries})
+	}
+
+	ctx := c.Request().Context()
+	entries, follower, err := h.Logs.Follow(ctx, filter)
+	if err != nil {
+		h.logger.Error("failed to query logs", "error", err)
+		return h.internalServerError(c, "Failed to read logs")
+	}
+
+	return streamNDJSON(c, entries, follower)
+}
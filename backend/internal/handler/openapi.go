@@ -0,0 +1,24 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// openAPISpec is the OpenAPI 3 document describing every route registered by
+// New. handler_tests checks it against the registered routes and types, so
+// update it along with any API change.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPISpec returns the OpenAPI 3 document for the backend API
+func OpenAPISpec() []byte {
+	return openAPISpec
+}
+
+func (h *Handler) GetOpenAPI(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, openAPISpec)
+}
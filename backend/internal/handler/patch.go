@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// errInvalidPatch wraps problems with a merge patch or the document it produces
+var errInvalidPatch = errors.New("invalid patch")
+
+// PatchEndpointByID applies a JSON merge patch (RFC 7396) to an endpoint's
+// configuration. Fields absent from the patch are left untouched and null
+// clears a field.
+func (h *Handler) PatchEndpointByID(c echo.Context) error {
+	// Get endpoint ID from URL parameter
+	endpointID := endpointIDParam(c)
+	if endpointID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "endpoint ID is required"})
+	}
+
+	patch, err := readMergePatch(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ifMatch := preconditionFromRequest(c, 0).ifMatch
+	err = h.updateState(c, func(state *store.State) error {
+		existing, exists := state.EndpointConfigs[endpointID]
+		if !exists {
+			return errEndpointNotFound
+		}
+
+		var req EndpointRequest
+		if err := applyMergePatch(endpointRequestFromConfig(existing), patch, &req); err != nil {
+			return err
+		}
+
+		// A resourceVersion in the patch is checked like one in a PUT body;
+		// otherwise the merged document carries the current version
+		pre := precondition{ifMatch: ifMatch, resourceVersion: req.ResourceVersion}
+		if err := pre.check(exists, existing.ResourceVersion); err != nil {
+			return err
+		}
+
+		id, err := endpointRequestID(&req)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInvalidPatch, err)
+		}
+		if id != endpointID {
+			return fmt.Errorf("%w: the endpoint's target cannot be changed", errInvalidPatch)
+		}
+
+		applyEndpointRequest(state, endpointID, req)
+
+		// Unlike PUT, patching an endpoint that is already online does not
+		// count as starting it again
+		if existing.ExpectedState == manager.EndpointStateOnline && req.ExpectedState == manager.EndpointStateOnline {
+			config := state.EndpointConfigs[endpointID]
+			config.LastStarted = existing.LastStarted
+			state.EndpointConfigs[endpointID] = config
+		}
+
+		if err := manager.ValidateReferences(state.EndpointConfigs, endpointID); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidPatch, err)
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errEndpointNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Endpoint not found"})
+		case errors.Is(err, errInvalidPatch):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
+		return h.internalServerError(c, "Failed to save endpoint configuration")
+	}
+
+	// Trigger convergence to apply the configuration
+	if err := h.Manager.Converge(c.Request().Context()); err != nil {
+		h.logger.Warn("convergence failed", "err", err)
+	}
+
+	// Get endpoint response using shared helper
+	endpoint, err := h.buildEndpointResponse(endpointID)
+	if err != nil {
+		return h.internalServerError(c, err.Error())
+	}
+
+	setETag(c, endpoint.ResourceVersion)
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+// PatchAgent applies a JSON merge patch (RFC 7396) to the agent configuration
+func (h *Handler) PatchAgent(c echo.Context) error {
+	patch, err := readMergePatch(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ifMatch := preconditionFromRequest(c, 0).ifMatch
+	err = h.updateState(c, func(state *store.State) error {
+		var config store.AgentConfig
+		if err := applyMergePatch(state.AgentConfig, patch, &config); err != nil {
+			return err
+		}
+
+		pre := precondition{ifMatch: ifMatch, resourceVersion: config.ResourceVersion}
+		if err := pre.check(true, state.AgentConfig.ResourceVersion); err != nil {
+			return err
+		}
+
+		applyAgentConfig(state, config)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidPatch) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if handled, respErr := preconditionErrorResponse(c, err); handled {
+			return respErr
+		}
+		return h.internalServerError(c, "Failed to save configuration")
+	}
+
+	// Trigger convergence to apply the configuration
+	if err := h.Manager.Converge(c.Request().Context()); err != nil {
+		h.logger.Warn("convergence failed", "err", err)
+	}
+
+	// Build and return agent response
+	return h.buildAgentResponse(c)
+}
+
+// endpointRequestFromConfig returns the request that would produce config
+func endpointRequestFromConfig(config store.EndpointConfig) EndpointRequest {
+	return EndpointRequest{
+		ContainerID:     config.ContainerID,
+		TargetPort:      config.TargetPort,
+		Upstream:        config.Upstream,
+		URL:             config.URL,
+		Binding:         config.Binding,
+		PoolingEnabled:  config.PoolingEnabled,
+		TrafficPolicy:   config.TrafficPolicy,
+		Description:     config.Description,
+		Metadata:        config.Metadata,
+		ExpectedState:   config.ExpectedState,
+		ProxyProtocol:   config.ProxyProtocol,
+		TLSTermination:  config.TLSTermination,
+		ForwardedFor:    config.ForwardedFor,
+		RealIP:          config.RealIP,
+		HostHeader:      config.HostHeader,
+		Routes:          config.Routes,
+		Router:          config.Router,
+		ResourceVersion: config.ResourceVersion,
+	}
+}
+
+// readMergePatch reads a request body that must be a JSON object
+func readMergePatch(c echo.Context) (map[string]any, error) {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	var patch map[string]any
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, errors.New("request body must be a JSON merge patch object")
+	}
+	return patch, nil
+}
+
+// applyMergePatch applies patch to the JSON encoding of target and decodes
+// the result into out
+func applyMergePatch(target any, patch map[string]any, out any) error {
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(merged, out); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidPatch, err)
+	}
+	return nil
+}
+
+// mergePatch implements the RFC 7396 MergePatch algorithm for JSON objects
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = make(map[string]any)
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]any); ok {
+			targetObject, _ := target[key].(map[string]any)
+			target[key] = mergePatch(targetObject, patchObject)
+			continue
+		}
+		target[key] = value
+	}
+	return target
+}
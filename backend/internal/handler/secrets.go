@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
+)
+
+// ListSecretsResponse defines the response body for GET /secrets. Secret
+// values are write-only and never returned.
+type ListSecretsResponse struct {
+	Secrets []secrets.Info `json:"secrets"`
+}
+
+// PutSecretRequest defines the request body for PUT /secrets/:name
+type PutSecretRequest struct {
+	Value string `json:"value"`
+}
+
+func (h *Handler) ListSecrets(c echo.Context) error {
+	return c.JSON(http.StatusOK, ListSecretsResponse{Secrets: h.Secrets.List()})
+}
+
+// PutSecret creates or replaces a secret and re-converges, so that endpoints
+// referring to it pick up the new value
+func (h *Handler) PutSecret(c echo.Context) error {
+	name := c.Param("name")
+	if !secrets.ValidName(name) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "secret name must contain only letters, digits, '_', '-' and '.'"})
+	}
+
+	var req PutSecretRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.Value == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "value is required"})
+	}
+
+	info, created, err := h.Secrets.Set(name, req.Value)
+	if err != nil {
+		h.logger.Error("failed to save secret", "name", name, "error", err)
+		return h.internalServerError(c, "Failed to save secret")
+	}
+
+	// Trigger convergence to recreate the endpoints that use the secret
+	if err := h.Manager.Converge(c.Request().Context()); err != nil {
+		h.logger.Warn("convergence failed", "err", err)
+	}
+
+	if created {
+		return c.JSON(http.StatusCreated, info)
+	}
+	return c.JSON(http.StatusOK, info)
+}
+
+func (h *Handler) DeleteSecret(c echo.Context) error {
+	name := c.Param("name")
+
+	existed, err := h.Secrets.Delete(name)
+	if err != nil {
+		h.logger.Error("failed to delete secret", "name", name, "error", err)
+		return h.internalServerError(c, "Failed to delete secret")
+	}
+	if !existed {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Secret not found"})
+	}
+
+	// Endpoints that use the secret fail until it is recreated
+	if err := h.Manager.Converge(c.Request().Context()); err != nil {
+		h.logger.Warn("convergence failed", "err", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
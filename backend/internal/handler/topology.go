@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+)
+
+// GetTopology returns the graph of endpoints, the routers and routes
+// forwarding between them and the containers and upstreams they target
+func (h *Handler) GetTopology(c echo.Context) error {
+	state, err := h.Store.Load()
+	if err != nil {
+		return h.internalServerError(c, "Failed to load configuration")
+	}
+	return c.JSON(http.StatusOK, manager.BuildTopology(state.EndpointConfigs, h.Manager.EndpointStatus()))
+}
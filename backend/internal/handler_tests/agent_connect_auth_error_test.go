@@ -0,0 +1,82 @@
+package handler_tests
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// ngrokCloudError implements ngrok.Error like the errors returned by the SDK
+type ngrokCloudError struct {
+	code    string
+	message string
+}
+
+func (e *ngrokCloudError) Error() string { return e.message }
+func (e *ngrokCloudError) Code() string  { return e.code }
+
+func TestPutAgent_ConnectErrorIsClassified(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.expectNewAgent().Times(1)
+	env.MockAgent.EXPECT().
+		Connect(gomock.Any()).
+		Return(fmt.Errorf("failed to connect: %w", &ngrokCloudError{
+			code:    "ERR_NGROK_105",
+			message: "The authtoken you specified is invalid.",
+		})).
+		Times(1)
+
+	response := env.putAgent(store.AgentConfig{
+		AuthToken:     "ngrok_bad_token",
+		ExpectedState: "online",
+	})
+
+	assert.Equal(t, manager.AgentStateOffline, response.Status.State)
+	require.NotNil(t, response.Status.Error)
+	assert.Equal(t, manager.ErrorCodeAuthFailed, response.Status.Error.Code)
+	assert.Equal(t, "ERR_NGROK_105", response.Status.Error.NgrokCode)
+	assert.Equal(t, "failed to connect: The authtoken you specified is invalid.", response.Status.Error.Message)
+	assert.Equal(t, response.Status.LastError, response.Status.Error.Message)
+	assert.NotEmpty(t, response.Status.Error.Remediation)
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code manager.ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"url in use", &ngrokCloudError{code: "ERR_NGROK_334", message: "endpoint is already online"}, manager.ErrorCodeURLInUse},
+		{"unknown ngrok code falls back to message", &ngrokCloudError{code: "ERR_NGROK_9999", message: "invalid traffic policy"}, manager.ErrorCodePolicyInvalid},
+		{"session limit", &ngrokCloudError{code: "ERR_NGROK_108", message: "limited to 1 simultaneous session"}, manager.ErrorCodeQuotaExceeded},
+		{"upstream", &ngrokCloudError{code: "ERR_NGROK_8012", message: "traffic successfully tunneled but upstream failed"}, manager.ErrorCodeUpstreamUnreachable},
+		{"dns failure", fmt.Errorf("dial: %w", &net.DNSError{Err: "no such host", Name: "connect.ngrok-agent.com"}), manager.ErrorCodeNetworkDown},
+		{"other", errors.New("something else"), manager.ErrorCodeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusErr := manager.ClassifyError(tt.err)
+			if tt.err == nil {
+				assert.Nil(t, statusErr)
+				return
+			}
+			require.NotNil(t, statusErr)
+			assert.Equal(t, tt.code, statusErr.Code)
+			assert.Equal(t, tt.err.Error(), statusErr.Message)
+		})
+	}
+}
@@ -51,9 +51,11 @@ func TestPutAgent_OfflineToOnline_ConnectError(t *testing.T) {
 	// Verify state was persisted with online expected state (despite connection failure)
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_test_token",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_test_token",
+			ExpectedState:   "online",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
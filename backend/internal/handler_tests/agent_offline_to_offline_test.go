@@ -45,9 +45,11 @@ func TestPutAgent_OfflineToOffline_ExpectNoSDKCalls(t *testing.T) {
 	// Verify state was persisted with updated config
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_updated_token",
-			ExpectedState: "offline",
+			AuthToken:       "ngrok_updated_token",
+			ExpectedState:   "offline",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
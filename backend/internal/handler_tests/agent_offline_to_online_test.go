@@ -46,9 +46,11 @@ func TestPutAgent_OfflineToOnline_ExpectConnect(t *testing.T) {
 	// Verify state was persisted with online expected state
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_test_token",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_test_token",
+			ExpectedState:   "online",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
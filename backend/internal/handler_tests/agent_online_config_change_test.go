@@ -49,10 +49,12 @@ func TestPutAgent_OnlineConfigChange_ExpectReconnect(t *testing.T) {
 	// STEP 3: Verify updated state was persisted (NOT original)
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_updated_token",
-			ConnectURL:    "https://connect.ngrok-agent.com",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_updated_token",
+			ConnectURL:      "https://connect.ngrok-agent.com",
+			ExpectedState:   "online",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
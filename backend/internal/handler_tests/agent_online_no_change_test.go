@@ -44,10 +44,12 @@ func TestPutAgent_OnlineIdenticalConfig_ExpectNoConnect(t *testing.T) {
 	// Verify state is unchanged
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_test_token",
-			ConnectURL:    "https://connect.ngrok-agent.com",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_test_token",
+			ConnectURL:      "https://connect.ngrok-agent.com",
+			ExpectedState:   "online",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
@@ -48,9 +48,11 @@ func TestPutAgent_OnlineToOffline_ExpectDisconnect(t *testing.T) {
 	// Verify state was persisted with offline expected state
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_test_token",
-			ExpectedState: "offline",
+			AuthToken:       "ngrok_test_token",
+			ExpectedState:   "offline",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
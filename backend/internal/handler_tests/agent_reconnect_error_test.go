@@ -56,9 +56,11 @@ func TestPutAgent_OnlineWithNewToken_ConnectError(t *testing.T) {
 	// Verify state was persisted with new token and online expected state (despite connection failure)
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_new_token",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_new_token",
+			ExpectedState:   "online",
+			ResourceVersion: 2,
 		},
-		Version: 1,
+		Version:             1,
+		LastResourceVersion: 2,
 	})
 }
@@ -38,10 +38,12 @@ func TestPutAgent_CreateInitialConfiguration(t *testing.T) {
 	// Verify state was persisted
 	env.expectState(store.State{
 		AgentConfig: store.AgentConfig{
-			AuthToken:     "ngrok_test_token",
-			ExpectedState: "online",
+			AuthToken:       "ngrok_test_token",
+			ExpectedState:   "online",
+			ResourceVersion: 1,
 		},
-		EndpointConfigs: make(map[string]store.EndpointConfig),
-		Version:         1,
+		EndpointConfigs:     make(map[string]store.EndpointConfig),
+		Version:             1,
+		LastResourceVersion: 1,
 	})
 }
@@ -0,0 +1,121 @@
+package handler_tests
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/apiauth"
+	"github.com/ngrok/ngrok-docker-extension/internal/audit"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestAuditLog_RecordsAuthenticatedChanges(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	// Wire auth and auditing the way initRouter does
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.log"))
+	env.Echo.Use(apiauth.Middleware(apiauth.ParseTokens("ui=ui-token,cli=cli-token"), nil))
+	env.Echo.Use(audit.Middleware(log, slog.New(slog.NewTextHandler(os.Stdout, nil))))
+	env.Handler.Audit = log
+
+	bearer := func(token string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + token}}
+	}
+
+	// Requests without a valid token are rejected and not audited
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/audit",
+		ExpectedCode: http.StatusUnauthorized,
+	})
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/agent",
+		RequestBody:  store.AgentConfig{AuthToken: "ngrok_stolen", ExpectedState: "offline"},
+		Headers:      bearer("wrong-token"),
+		ExpectedCode: http.StatusUnauthorized,
+	})
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/agent",
+		RequestBody:  store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "offline"},
+		Headers:      bearer("ui-token"),
+		ExpectedCode: http.StatusOK,
+	})
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPost,
+		Path:   "/endpoints",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:   "web",
+			TargetPort:    "80",
+			Description:   "web server",
+			ExpectedState: "offline",
+		},
+		Headers:      bearer("cli-token"),
+		ExpectedCode: http.StatusCreated,
+	})
+
+	var all handler.GetAuditResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/audit",
+		Headers:      bearer("cli-token"),
+		ResponseBody: &all,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, all.Entries, 2)
+
+	agentEntry := all.Entries[0]
+	assert.Equal(t, "ui", agentEntry.Identity)
+	assert.Equal(t, http.MethodPut, agentEntry.Method)
+	assert.Equal(t, http.StatusOK, agentEntry.Status)
+	assert.Contains(t, agentEntry.Changes, audit.Change{Resource: "agent", Field: "authToken", After: "[REDACTED]"})
+	assert.NotContains(t, env.apiRequest(&APIRequest{Method: http.MethodGet, Path: "/audit", Headers: bearer("ui-token")}).Body.String(), "ngrok_test_token")
+
+	endpointEntry := all.Entries[1]
+	assert.Equal(t, "cli", endpointEntry.Identity)
+	assert.Equal(t, []string{"web:80"}, endpointEntry.Endpoints)
+	assert.Contains(t, endpointEntry.Changes, audit.Change{Resource: "endpoint", ID: "web:80", Field: "description", After: "web server"})
+
+	// Filters select entries by caller and by endpoint
+	var filtered handler.GetAuditResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/audit?identity=ui",
+		Headers:      bearer("ui-token"),
+		ResponseBody: &filtered,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, filtered.Entries, 1)
+	assert.Equal(t, "ui", filtered.Entries[0].Identity)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/audit?endpoint=web:80&limit=5",
+		Headers:      bearer("ui-token"),
+		ResponseBody: &filtered,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, filtered.Entries, 1)
+	assert.Equal(t, "cli", filtered.Entries[0].Identity)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/audit?since=yesterday",
+		Headers:      bearer("ui-token"),
+		ExpectedCode: http.StatusBadRequest,
+	})
+}
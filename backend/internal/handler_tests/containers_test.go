@@ -0,0 +1,233 @@
+package handler_tests
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/containers"
+	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestListContainers(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web123:8080": {ID: "web123:8080", ContainerID: "web123", TargetPort: "8080", ExpectedState: "offline"},
+		},
+		Version: 1,
+	})
+
+	env.MockDocker.EXPECT().
+		ContainerList(gomock.Any(), container.ListOptions{All: true}).
+		Return([]container.Summary{
+			{
+				ID:     "web123",
+				Names:  []string{"/shop-web-1"},
+				Image:  "nginx:latest",
+				State:  container.StateRunning,
+				Status: "Up 5 minutes (healthy)",
+				Labels: map[string]string{
+					"com.docker.compose.project": "shop",
+					"com.docker.compose.service": "web",
+				},
+				Ports: []container.Port{
+					{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+					{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+					{PrivatePort: 9000, Type: "tcp"},
+				},
+				NetworkSettings: &container.NetworkSettingsSummary{
+					Networks: map[string]*network.EndpointSettings{"shop_default": {}, "bridge": {}},
+				},
+			},
+			{
+				ID:     "db456",
+				Names:  []string{"/db"},
+				Image:  "postgres:16",
+				State:  container.StateExited,
+				Status: "Exited (0) 2 hours ago",
+				Ports:  []container.Port{{PrivatePort: 5432, PublicPort: 5432, Type: "tcp"}},
+			},
+		}, nil).
+		Times(2)
+
+	// Detection results are cached between calls, and stopped containers are
+	// not probed
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "8080").
+		Return(&detectproto.Result{TCP: true, HTTP: true}, nil).
+		Times(1)
+
+	var resp handler.ListContainersResponse
+	for range 2 {
+		env.apiRequest(&APIRequest{
+			Method:       http.MethodGet,
+			Path:         "/containers?all=true",
+			ResponseBody: &resp,
+			ExpectedCode: http.StatusOK,
+		})
+	}
+
+	require.Len(t, resp.Containers, 2)
+	db, web := resp.Containers[0], resp.Containers[1]
+
+	assert.Equal(t, "shop-web-1", web.Name)
+	assert.Equal(t, "running", web.State)
+	assert.Equal(t, containers.HealthHealthy, web.Health)
+	assert.Equal(t, "shop", web.ComposeProject)
+	assert.Equal(t, "web", web.ComposeService)
+	assert.Equal(t, []string{"bridge", "shop_default"}, web.Networks)
+	assert.Equal(t, []string{"web123:8080"}, web.Endpoints)
+	require.Len(t, web.Ports, 2)
+	assert.Equal(t, uint16(80), web.Ports[0].PrivatePort)
+	assert.Equal(t, uint16(8080), web.Ports[0].PublicPort)
+	assert.Equal(t, "web123:8080", web.Ports[0].EndpointID)
+	require.NotNil(t, web.Ports[0].Detected)
+	assert.True(t, web.Ports[0].Detected.HTTP)
+	assert.False(t, web.Ports[0].Detected.TLS)
+	assert.Equal(t, containers.Port{PrivatePort: 9000, Protocol: "tcp"}, web.Ports[1])
+
+	assert.Equal(t, "db", db.Name)
+	assert.Equal(t, containers.HealthNone, db.Health)
+	assert.Empty(t, db.Endpoints)
+	assert.Empty(t, db.Networks)
+	require.Len(t, db.Ports, 1)
+	assert.Nil(t, db.Ports[0].Detected)
+}
+
+func TestListContainers_InvalidQuery(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/containers?all=maybe",
+		ExpectedCode: http.StatusBadRequest,
+	})
+}
+
+func TestDiscoverContainerPorts(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.MockDocker.EXPECT().
+		ContainerInspect(gomock.Any(), "web").
+		Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "web123",
+				State: &types.ContainerState{Running: true},
+			},
+			Config: &container.Config{
+				ExposedPorts: nat.PortSet{"80/tcp": {}, "9090/tcp": {}, "53/udp": {}},
+			},
+			NetworkSettings: &types.NetworkSettings{
+				NetworkSettingsBase: types.NetworkSettingsBase{
+					Ports: nat.PortMap{
+						"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8080"}},
+						"9090/tcp": nil,
+					},
+				},
+				Networks: map[string]*network.EndpointSettings{"bridge": {IPAddress: "172.17.0.5"}},
+			},
+		}, nil)
+
+	// 6379 listens on all addresses, 5432 only on loopback
+	env.MockDocker.EXPECT().
+		ContainerExec(gomock.Any(), "web", []string{"cat", "/proc/net/tcp", "/proc/net/tcp6"}).
+		Return([]byte(
+			"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+				"   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n"+
+				"   1: 0100007F:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2 1\n"+
+				"   2: 050011AC:0050 0A0011AC:C350 01 00000000:00000000 00:00000000 00000000     0        0 3 1\n"+
+				"  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+				"   0: 00000000000000000000000000000000:18EB 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 4 1\n"),
+			nil)
+
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "8080").
+		Return(&detectproto.Result{TCP: true, HTTP: true}, nil)
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.5", "9090").
+		Return(&detectproto.Result{TCP: true, TLS: true}, nil)
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.5", "6379").
+		Return(&detectproto.Result{TCP: true}, nil)
+
+	var discovery containers.Discovery
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPost,
+		Path:         "/containers/web/discover",
+		RequestBody:  handler.DiscoverRequest{ScanListening: true},
+		ResponseBody: &discovery,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, "web123", discovery.ContainerID)
+	assert.Empty(t, discovery.Warnings)
+	require.Len(t, discovery.Ports, 3)
+
+	web, tls, redis := discovery.Ports[0], discovery.Ports[1], discovery.Ports[2]
+	assert.Equal(t, uint16(80), web.Port)
+	assert.Equal(t, uint16(8080), web.PublicPort)
+	assert.ElementsMatch(t, []string{containers.SourceExposed, containers.SourcePublished, containers.SourceListening}, web.Sources)
+	assert.Equal(t, &containers.Suggestion{
+		EndpointType: "http", URLScheme: "https", UpstreamScheme: "http", TargetPort: "8080", Reason: "serves HTTP",
+	}, web.Suggestion)
+
+	assert.Equal(t, uint16(9090), tls.Port)
+	assert.Equal(t, "172.17.0.5:9090", tls.Address)
+	require.NotNil(t, tls.Suggestion)
+	assert.Equal(t, "tls", tls.Suggestion.EndpointType)
+	assert.Empty(t, tls.Suggestion.TargetPort)
+
+	assert.Equal(t, uint16(6379), redis.Port)
+	assert.Equal(t, []string{containers.SourceListening}, redis.Sources)
+	require.NotNil(t, redis.Suggestion)
+	assert.Equal(t, "tcp", redis.Suggestion.URLScheme)
+}
+
+func TestDiscoverContainerPorts_Errors(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.MockDocker.EXPECT().
+		ContainerInspect(gomock.Any(), "missing").
+		Return(types.ContainerJSON{}, errdefs.NotFound(errors.New("no such container")))
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPost,
+		Path:         "/containers/missing/discover",
+		ExpectedCode: http.StatusNotFound,
+	})
+
+	env.expectDockerContainer("stopped", false)
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPost,
+		Path:         "/containers/stopped/discover",
+		ExpectedCode: http.StatusConflict,
+	})
+}
@@ -0,0 +1,221 @@
+package handler_tests
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestGetDiagnostics_ReportsChecks(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "2abcdefghijklmnopqrstuvwxyz_0123456789abcdef",
+			ExpectedState: "offline",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {
+				ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "offline",
+				URL:           "tls://web.example.com",
+				TrafficPolicy: "on_http_request:\n  - actions: []\n",
+			},
+			"db:5432": {ID: "db:5432", ContainerID: "db", TargetPort: "5432", ExpectedState: "offline"},
+		},
+		Version: 1,
+	})
+
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{APIVersion: "1.47"}, nil)
+	env.expectDockerContainer("web", true)
+	env.expectDockerContainer("db", false)
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "80").
+		Return(&detectproto.Result{TCP: true, HTTP: true}, nil)
+
+	// The gateway refuses the probe connection, which proves it is reachable
+	env.Handler.Diagnostics.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		assert.Equal(t, "172.17.0.1:1", address)
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+
+	var report diagnostics.Report
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/diagnostics",
+		ResponseBody: &report,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, diagnostics.StatusFail, report.Status)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"docker":         diagnostics.StatusPass,
+		"bridge_gateway": diagnostics.StatusPass,
+		"state_file":     diagnostics.StatusPass,
+		"authtoken":      diagnostics.StatusPass,
+		"agent":          diagnostics.StatusWarn,
+	}, checkStatuses(report.Checks))
+
+	require.Len(t, report.Endpoints, 2)
+	db, web := report.Endpoints[0], report.Endpoints[1]
+
+	assert.Equal(t, "db:5432", db.ID)
+	assert.Equal(t, diagnostics.StatusFail, db.Status)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"container": diagnostics.StatusFail,
+	}, checkStatuses(db.Checks))
+
+	assert.Equal(t, "web:80", web.ID)
+	assert.Equal(t, diagnostics.StatusFail, web.Status)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"container":      diagnostics.StatusPass,
+		"port":           diagnostics.StatusPass,
+		"protocol":       diagnostics.StatusWarn,
+		"traffic_policy": diagnostics.StatusFail,
+	}, checkStatuses(web.Checks))
+}
+
+func TestGetDiagnostics_DockerUnreachable(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{AuthToken: "not-a-token", ExpectedState: "online"},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "online"},
+		},
+		Version: 1,
+	})
+
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, errors.New("socket not found"))
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "80").
+		Return(&detectproto.Result{}, nil)
+	env.Handler.Diagnostics.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, context.DeadlineExceeded
+	}
+
+	var report diagnostics.Report
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/diagnostics",
+		ResponseBody: &report,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, diagnostics.StatusFail, report.Status)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"docker":         diagnostics.StatusFail,
+		"bridge_gateway": diagnostics.StatusFail,
+		"state_file":     diagnostics.StatusPass,
+		"authtoken":      diagnostics.StatusWarn,
+		"agent":          diagnostics.StatusFail,
+	}, checkStatuses(report.Checks))
+
+	// Container checks are skipped without Docker
+	require.Len(t, report.Endpoints, 1)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"port": diagnostics.StatusFail,
+	}, checkStatuses(report.Endpoints[0].Checks))
+}
+
+func TestGetDiagnostics_StateFileIsNotWritten(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	dir := t.TempDir()
+	fileStore := store.NewFileStore(filepath.Join(dir, "state.json"))
+	require.NoError(t, fileStore.Save(&store.State{AgentConfig: store.AgentConfig{ExpectedState: "offline"}, Version: 1}))
+	before, err := os.Stat(fileStore.Path())
+	require.NoError(t, err)
+	env.Handler.Diagnostics.Store = fileStore
+
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{APIVersion: "1.47"}, nil)
+	env.Handler.Diagnostics.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+
+	var report diagnostics.Report
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/diagnostics",
+		ResponseBody: &report,
+		ExpectedCode: http.StatusOK,
+	})
+	check := findCheck(t, report.Checks, "state_file")
+	assert.Equal(t, diagnostics.StatusPass, check.Status)
+	assert.Equal(t, "state can be read and its directory is writable", check.Message)
+
+	// Neither the state file nor the probe file is left changed
+	after, err := os.Stat(fileStore.Path())
+	require.NoError(t, err)
+	assert.Equal(t, before.ModTime(), after.ModTime())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "state.json", entries[0].Name())
+}
+
+func TestGetDiagnostics_GitOpsStateIsReadOnly(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupGitOpsEnvironment(t, ctrl, "agent:\n  expectedState: offline\n")
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{APIVersion: "1.47"}, nil)
+	env.Handler.Diagnostics.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+
+	var report diagnostics.Report
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/diagnostics",
+		ResponseBody: &report,
+		ExpectedCode: http.StatusOK,
+	})
+	check := findCheck(t, report.Checks, "state_file")
+	assert.Equal(t, diagnostics.StatusPass, check.Status)
+	assert.Contains(t, check.Message, "read-only")
+}
+
+func findCheck(t *testing.T, checks []diagnostics.Check, name string) diagnostics.Check {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %s check", name)
+	return diagnostics.Check{}
+}
+
+func checkStatuses(checks []diagnostics.Check) map[string]diagnostics.Status {
+	statuses := make(map[string]diagnostics.Status)
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
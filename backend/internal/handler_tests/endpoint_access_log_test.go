@@ -0,0 +1,127 @@
+package handler_tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestGetEndpointLogs(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	// Connections to the upstream are dialed through the access log
+	var capturedUpstream string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			capturedUpstream = fmt.Sprintf("%+v", upstream)
+		}).
+		Return(env.createMockForwarder(ctrl, "https://web.ngrok.app", "ep_web"), nil).
+		Times(1)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+	env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "online"})
+	assert.Contains(t, capturedUpstream, "dialer:0x")
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, status := range []int{200, 404, 502} {
+		require.NoError(t, env.AccessLog.Record(accesslog.Entry{
+			Time:       start.Add(time.Duration(i) * time.Second),
+			EndpointID: "web:80",
+			Protocol:   accesslog.ProtocolHTTP,
+			RemoteAddr: "203.0.113.7",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/page/%d", i),
+			Status:     status,
+		}))
+	}
+
+	var response handler.GetEndpointLogsResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/endpoints/web:80/logs?status=4xx",
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, response.Entries, 1)
+	assert.Equal(t, "/page/1", response.Entries[0].Path)
+
+	// Exported as newline delimited JSON
+	rec := env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/endpoints/web:80/logs?format=ndjson&since=2025-01-01T00:00:01Z",
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="web_80.ndjson"`, rec.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var entry accesslog.Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, 502, entry.Status)
+
+	var errorResponse map[string]string
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/endpoints/web:80/logs?status=ok",
+		ResponseBody: &errorResponse,
+		ExpectedCode: http.StatusBadRequest,
+	})
+	assert.Contains(t, errorResponse["error"], "status must be a status code")
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/endpoints/api:8080/logs",
+		ExpectedCode: http.StatusNotFound,
+	})
+}
+
+func TestGetEndpointLogs_Follow(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.postEndpoint(handler.EndpointRequest{ContainerID: "db", TargetPort: "5432", URL: "tcp://", ExpectedState: "offline"})
+	require.NoError(t, env.AccessLog.Record(accesslog.Entry{Time: time.Now(), EndpointID: "db:5432", Protocol: accesslog.ProtocolTCP, BytesIn: 10}))
+
+	server := httptest.NewServer(env.Echo)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/endpoints/db:5432/logs?follow=true", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The recorded entries come first, then new ones as they are recorded
+	lines := bufio.NewScanner(resp.Body)
+	require.True(t, lines.Scan())
+	assert.Contains(t, lines.Text(), `"bytesIn":10`)
+
+	require.NoError(t, env.AccessLog.Record(accesslog.Entry{Time: time.Now(), EndpointID: "db:5432", Protocol: accesslog.ProtocolTCP, BytesIn: 20}))
+	require.True(t, lines.Scan())
+	assert.Contains(t, lines.Text(), `"bytesIn":20`)
+}
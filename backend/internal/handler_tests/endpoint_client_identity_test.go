@@ -0,0 +1,48 @@
+package handler_tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+)
+
+func TestPostEndpoints_ClientIdentityOptions(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	env := setupTestEnvironment(t, ctrl)
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "web",
+		TargetPort:    "8080",
+		URL:           "https://web.example.com",
+		ForwardedFor:  "replace",
+		RealIP:        true,
+		HostHeader:    "rewrite",
+		ExpectedState: "offline",
+	})
+	assert.Equal(t, "replace", endpoint.ForwardedFor)
+	assert.True(t, endpoint.RealIP)
+	assert.Equal(t, "rewrite", endpoint.HostHeader)
+
+	// Headers cannot be set on TCP connections: PROXY protocol carries the
+	// client's address instead
+	var errorResponse map[string]string
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPost,
+		Path:   "/endpoints",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:   "db",
+			TargetPort:    "5432",
+			URL:           "tcp://1.tcp.ngrok.io:12345",
+			RealIP:        true,
+			ExpectedState: "offline",
+		},
+		ResponseBody: &errorResponse,
+		ExpectedCode: http.StatusBadRequest,
+	})
+	assert.Contains(t, errorResponse["error"], "require an http or https endpoint, not tcp")
+}
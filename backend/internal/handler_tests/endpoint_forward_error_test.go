@@ -61,4 +61,9 @@ func TestPostEndpoints_AgentForwardError(t *testing.T) {
 	assert.Empty(t, actualResponse.Status.URL)
 	assert.Contains(t, actualResponse.Status.LastError, "failed to create endpoint")
 	assert.Contains(t, actualResponse.Status.LastError, "insufficient quota")
+	if assert.NotNil(t, actualResponse.Status.Error) {
+		assert.Equal(t, manager.ErrorCodeQuotaExceeded, actualResponse.Status.Error.Code)
+		assert.Equal(t, actualResponse.Status.LastError, actualResponse.Status.Error.Message)
+		assert.NotEmpty(t, actualResponse.Status.Error.Remediation)
+	}
 }
@@ -0,0 +1,155 @@
+package handler_tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestEndpoint_IfMatchPreventsLostUpdates(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "offline",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "offline", ResourceVersion: 7},
+		},
+		Version:             1,
+		LastResourceVersion: 7,
+	})
+
+	// GET returns the current version as an ETag
+	rec := env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/endpoints/web:80",
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, `"7"`, rec.Header().Get("ETag"))
+
+	// Another writer updates the endpoint first
+	first := env.putEndpoint("web:80", handler.EndpointRequest{
+		ContainerID:   "web",
+		TargetPort:    "80",
+		Description:   "first writer",
+		ExpectedState: "offline",
+	})
+	assert.Equal(t, int64(8), first.ResourceVersion)
+
+	// A write based on the stale ETag is rejected
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPut,
+		Path:   "/endpoints/web:80",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:   "web",
+			TargetPort:    "80",
+			Description:   "second writer",
+			ExpectedState: "offline",
+		},
+		Headers:      http.Header{"If-Match": []string{`"7"`}},
+		ExpectedCode: http.StatusPreconditionFailed,
+	})
+
+	// So is one carrying a stale resourceVersion in the body
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPut,
+		Path:   "/endpoints/web:80",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:     "web",
+			TargetPort:      "80",
+			Description:     "second writer",
+			ExpectedState:   "offline",
+			ResourceVersion: 7,
+		},
+		ExpectedCode: http.StatusConflict,
+	})
+
+	// A stale DELETE is rejected too
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodDelete,
+		Path:         "/endpoints/web:80",
+		Headers:      http.Header{"If-Match": []string{`"7"`}},
+		ExpectedCode: http.StatusPreconditionFailed,
+	})
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "first writer", state.EndpointConfigs["web:80"].Description)
+
+	// Writes based on the current ETag succeed
+	rec = env.apiRequest(&APIRequest{
+		Method: http.MethodPut,
+		Path:   "/endpoints/web:80",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:   "web",
+			TargetPort:    "80",
+			Description:   "second writer",
+			ExpectedState: "offline",
+		},
+		Headers:      http.Header{"If-Match": []string{`"8"`}},
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, `"9"`, rec.Header().Get("ETag"))
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodDelete,
+		Path:         "/endpoints/web:80",
+		Headers:      http.Header{"If-Match": []string{`"9"`}},
+		ExpectedCode: http.StatusNoContent,
+	})
+}
+
+func TestAgent_IfMatchSeesAgentStartedByEndpoint(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+	env.expectDockerContainer("web", true)
+	env.expectAgentForward().Return(env.createMockForwarder(ctrl, "https://web.ngrok.app", "ep_1"), nil)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:       "ngrok_test_token",
+			ExpectedState:   "offline",
+			ResourceVersion: 1,
+		},
+		Version:             1,
+		LastResourceVersion: 1,
+	})
+	rec := env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/agent",
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	// Starting an endpoint also starts the agent, which changes its version
+	env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "online"})
+	agent := env.getAgent()
+	assert.Equal(t, "online", agent.ExpectedState)
+	assert.NotEqual(t, int64(1), agent.ResourceVersion)
+
+	// So a write based on the ETag read before is rejected
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/agent",
+		RequestBody:  store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "offline"},
+		Headers:      http.Header{"If-Match": []string{`"1"`}},
+		ExpectedCode: http.StatusPreconditionFailed,
+	})
+	assert.Equal(t, "online", env.getAgent().ExpectedState)
+}
@@ -0,0 +1,136 @@
+package handler_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// slowForward makes the next Agent.Forward() call block until the returned
+// channel is closed, then return forwarder
+func (env *TestEnv) slowForward(forwarder ngrok.EndpointForwarder) (release chan struct{}) {
+	release = make(chan struct{})
+	env.expectAgentForward().DoAndReturn(func(context.Context, *ngrok.Upstream, ...ngrok.EndpointOption) (ngrok.EndpointForwarder, error) {
+		<-release
+		return forwarder, nil
+	})
+	return release
+}
+
+func TestEndpointOperation_SlowForwardFinishesInBackground(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+	env.expectDockerContainer("web", true)
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	release := env.slowForward(env.createMockForwarder(ctrl, "https://web.ngrok.app", "ep_1"))
+
+	// Convergence stops waiting for the operation after a moment
+	endpoint := env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "online"})
+	assert.Equal(t, manager.EndpointStateStarting, endpoint.Status.State)
+
+	operations := env.Manager.ConvergeStatus().Operations
+	require.Len(t, operations, 1)
+	assert.Equal(t, "web:80", operations[0].EndpointID)
+	assert.Equal(t, manager.OperationStart, operations[0].Kind)
+
+	// Another convergence leaves the running operation alone
+	require.NoError(t, env.Manager.Converge(context.Background()))
+
+	// The worker applies its result once Forward returns
+	close(release)
+	require.Eventually(t, func() bool {
+		return env.getEndpointByID("web:80").Status.State == manager.EndpointStateOnline
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "https://web.ngrok.app", env.getEndpointByID("web:80").Status.URL)
+	assert.Empty(t, env.Manager.ConvergeStatus().Operations)
+}
+
+func TestEndpointOperation_SupersededStartIsClosed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+	env.expectDockerContainer("web", true)
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	stale := env.createMockForwarder(ctrl, "https://stale.ngrok.app", "ep_1")
+	closed := make(chan struct{})
+	stale.EXPECT().Close().DoAndReturn(func() error {
+		close(closed)
+		return nil
+	})
+	release := env.slowForward(stale)
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "online"})
+	assert.Equal(t, manager.EndpointStateStarting, endpoint.Status.State)
+
+	// Changing the endpoint abandons the running operation and starts over
+	env.expectAgentForward().Return(env.createMockForwarder(ctrl, "https://web.ngrok.app", "ep_2"), nil)
+	endpoint = env.putEndpoint("web:80", handler.EndpointRequest{
+		ContainerID: "web", TargetPort: "80", ExpectedState: "online", Description: "changed",
+	})
+	assert.Equal(t, manager.EndpointStateOnline, endpoint.Status.State)
+	assert.Equal(t, "https://web.ngrok.app", endpoint.Status.URL)
+
+	// The forwarder the abandoned operation started is closed, not tracked
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("abandoned forwarder was not closed")
+	}
+	assert.Equal(t, "https://web.ngrok.app", env.getEndpointByID("web:80").Status.URL)
+}
+
+func TestEndpointOperation_TimesOut(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.Manager.(manager.TestOperationTimeouts).SetOperationTimeoutsForTests(100*time.Millisecond, 50*time.Millisecond)
+	env.setupStandardMockExpectations()
+	env.expectDockerContainer("web", true)
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	hung := env.createMockForwarder(ctrl, "https://web.ngrok.app", "ep_1")
+	closed := make(chan struct{})
+	hung.EXPECT().Close().DoAndReturn(func() error {
+		close(closed)
+		return nil
+	})
+	release := env.slowForward(hung)
+
+	// The operation gives up at its deadline and reports the endpoint failed
+	env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "online"})
+	require.Eventually(t, func() bool {
+		return env.getEndpointByID("web:80").Status.State == manager.EndpointStateFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	endpoint := env.getEndpointByID("web:80")
+	require.NotNil(t, endpoint.Status.Error)
+	assert.Equal(t, manager.ErrorCodeOperationTimeout, endpoint.Status.Error.Code)
+	assert.Empty(t, env.Manager.ConvergeStatus().Operations)
+
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("forwarder of the timed out operation was not closed")
+	}
+}
@@ -0,0 +1,128 @@
+package handler_tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPostEndpoints_Router(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	// The router forwards nowhere itself: its policy answers every request
+	var capturedUpstreamURL string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			if parts := strings.Split(fmt.Sprintf("%+v", upstream), "addr:"); len(parts) > 1 {
+				capturedUpstreamURL = strings.Fields(parts[1])[0]
+			}
+		}).
+		Return(env.createMockForwarder(ctrl, "https://example.com", "ep_router"), nil).
+		Times(1)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	// .internal URLs imply the internal binding
+	api := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "api",
+		TargetPort:    "8080",
+		URL:           "https://api.internal",
+		ExpectedState: "offline",
+	})
+	assert.Equal(t, manager.BindingInternal, api.Binding)
+
+	router := env.postEndpoint(handler.EndpointRequest{
+		URL:           "https://example.com",
+		Router:        []store.RouterRule{{Path: "/api/*", Endpoint: "api:8080"}},
+		ExpectedState: "online",
+	})
+	assert.Equal(t, "router:example.com", router.ID)
+	assert.Equal(t, manager.TargetTypeRouter, router.TargetType)
+	assert.Equal(t, manager.EndpointStateOnline, router.Status.State)
+	assert.Equal(t, "http://localhost:80", capturedUpstreamURL)
+
+	// Endpoints a router forwards to cannot be deleted
+	errorResponse := env.deleteEndpointExpectingError("api:8080", http.StatusConflict)
+	assert.Contains(t, errorResponse["error"], "router:example.com: router[0] forwards to endpoint api:8080, which does not exist")
+
+	var topology manager.Topology
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/topology",
+		ResponseBody: &topology,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Contains(t, topology.Edges, manager.TopologyEdge{From: "router:example.com", To: "api:8080", Path: "/api/*"})
+	assert.Contains(t, topology.Edges, manager.TopologyEdge{From: "api:8080", To: "container:api", Port: "8080"})
+	require.Len(t, topology.Nodes, 3)
+	assert.Equal(t, manager.NodeTypeRouter, topology.Nodes[2].Type)
+}
+
+func TestPostEndpoints_RouterValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  handler.EndpointRequest
+		err  string
+	}{
+		{
+			name: "missing endpoint",
+			req:  handler.EndpointRequest{URL: "https://example.com", Router: []store.RouterRule{{Endpoint: "api:8080"}}, ExpectedState: "offline"},
+			err:  "forwards to endpoint api:8080, which does not exist",
+		},
+		{
+			name: "public endpoint",
+			req:  handler.EndpointRequest{URL: "https://example.com", Router: []store.RouterRule{{Endpoint: "web:80"}}, ExpectedState: "offline"},
+			err:  "forwards to endpoint web:80, which is not internal",
+		},
+		{
+			name: "no url",
+			req:  handler.EndpointRequest{Router: []store.RouterRule{{Endpoint: "web:80"}}, ExpectedState: "offline"},
+			err:  "routers require a url",
+		},
+		{
+			name: "router with target",
+			req:  handler.EndpointRequest{ContainerID: "web", URL: "https://example.com", Router: []store.RouterRule{{Endpoint: "web:80"}}, ExpectedState: "offline"},
+			err:  "router cannot be combined with containerId, targetPort or upstream",
+		},
+		{
+			name: "internal binding",
+			req:  handler.EndpointRequest{ContainerID: "web", TargetPort: "80", Binding: "internal", ExpectedState: "offline"},
+			err:  "internal endpoints require a url ending in .internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			env := setupTestEnvironment(t, ctrl)
+			env.postEndpoint(handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "offline"})
+
+			var errorResponse map[string]string
+			env.apiRequest(&APIRequest{
+				Method:       http.MethodPost,
+				Path:         "/endpoints",
+				RequestBody:  tt.req,
+				ResponseBody: &errorResponse,
+				ExpectedCode: http.StatusBadRequest,
+			})
+			assert.Contains(t, errorResponse["error"], tt.err)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler_tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPostEndpoints_Routes(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	routeForwarder := mocks.NewMockEndpointForwarder(ctrl)
+	routeURL, _ := url.Parse("https://web-3000-8080.internal")
+	routeForwarder.EXPECT().URL().Return(routeURL).AnyTimes()
+
+	mainForwarder := mocks.NewMockEndpointForwarder(ctrl)
+	mainURL, _ := url.Parse("https://web.ngrok.app")
+	mainForwarder.EXPECT().URL().Return(mainURL).AnyTimes()
+
+	// The route's internal endpoint is started before the endpoint itself,
+	// each forwarding to its own port
+	var mu sync.Mutex
+	var upstreams []string
+	capture := func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if parts := strings.Split(fmt.Sprintf("%+v", upstream), "addr:"); len(parts) > 1 {
+			upstreams = append(upstreams, strings.Fields(parts[1])[0])
+		}
+	}
+	gomock.InOrder(
+		env.MockAgent.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(capture).Return(routeForwarder, nil),
+		env.MockAgent.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(capture).Return(mainForwarder, nil),
+	)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "web",
+		TargetPort:    "3000",
+		ExpectedState: "online",
+		Routes:        []store.Route{{Path: "/api/*", TargetPort: "8080"}},
+	})
+
+	assert.Equal(t, []store.Route{{Path: "/api/*", TargetPort: "8080"}}, endpoint.Routes)
+	assert.Equal(t, manager.EndpointStateOnline, endpoint.Status.State)
+	assert.Equal(t, []manager.RouteStatus{{
+		Path:       "/api/*",
+		TargetPort: "8080",
+		URL:        "https://web-3000-8080.internal",
+		State:      manager.EndpointStateOnline,
+	}}, endpoint.Status.Routes)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"http://172.17.0.1:8080", "http://172.17.0.1:3000"}, upstreams)
+}
+
+func TestPostEndpoints_RouteFailureIsReportedPerRoute(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	mainForwarder := mocks.NewMockEndpointForwarder(ctrl)
+	mainURL, _ := url.Parse("https://web.ngrok.app")
+	mainForwarder.EXPECT().URL().Return(mainURL).AnyTimes()
+
+	gomock.InOrder(
+		env.MockAgent.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to start route")),
+		env.MockAgent.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mainForwarder, nil),
+	)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "web",
+		TargetPort:    "3000",
+		ExpectedState: "online",
+		Routes:        []store.Route{{Path: "/api/", TargetPort: "8080"}},
+	})
+
+	// The endpoint serves its other requests while the route is down
+	assert.Equal(t, manager.EndpointStateOnline, endpoint.Status.State)
+	require.Len(t, endpoint.Status.Routes, 1)
+	assert.Equal(t, manager.EndpointStateFailed, endpoint.Status.Routes[0].State)
+	assert.Equal(t, "failed to start route", endpoint.Status.Routes[0].LastError)
+}
+
+func TestPostEndpoints_RoutesRequireHTTPEndpoint(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	env := setupTestEnvironment(t, ctrl)
+
+	var errorResponse map[string]string
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPost,
+		Path:   "/endpoints",
+		RequestBody: handler.EndpointRequest{
+			ContainerID:   "db",
+			TargetPort:    "5432",
+			URL:           "tcp://1.tcp.ngrok.io:12345",
+			ExpectedState: "online",
+			Routes:        []store.Route{{Path: "/api/", TargetPort: "8080"}},
+		},
+		ResponseBody: &errorResponse,
+		ExpectedCode: http.StatusBadRequest,
+	})
+	assert.Contains(t, errorResponse["error"], "routes require an http or https endpoint")
+}
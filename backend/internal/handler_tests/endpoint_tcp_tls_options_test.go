@@ -0,0 +1,217 @@
+package handler_tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPostEndpoints_TLSPassthroughWithProxyProtocol(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.expectNewAgent().Times(1)
+	env.expectAgentConnect().Times(1)
+
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "8883").
+		Return(&detectproto.Result{TCP: true, TLS: true}, nil).
+		Times(1)
+
+	// Passed through connections are forwarded still encrypted, after the
+	// PROXY protocol header
+	var capturedUpstream string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			capturedUpstream = fmt.Sprintf("%+v", upstream)
+		}).
+		Return(env.createMockForwarder(ctrl, "tls://mqtt.example.com:443", "ep_mqtt"), nil).
+		Times(1)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "mosquitto",
+		TargetPort:    "8883",
+		URL:           "tls://mqtt.example.com",
+		ProxyProtocol: "v2",
+		ExpectedState: "online",
+	})
+
+	assert.Equal(t, "v2", endpoint.ProxyProtocol)
+	assert.Equal(t, "passthrough", endpoint.TLSTermination)
+	assert.Contains(t, capturedUpstream, "addr:tcp://172.17.0.1:8883 ")
+	assert.Contains(t, capturedUpstream, "proxyProto:v2 ")
+}
+
+func TestPostEndpoints_TCPOptionsValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  handler.EndpointRequest
+		err  string
+	}{
+		{
+			name: "unreserved tcp address",
+			req:  handler.EndpointRequest{URL: "tcp://db.example.com:5432"},
+			err:  "is not a reserved address",
+		},
+		{
+			name: "tcp address without port",
+			req:  handler.EndpointRequest{URL: "tcp://1.tcp.ngrok.io"},
+			err:  "requires a port",
+		},
+		{
+			name: "proxy protocol on http endpoint",
+			req:  handler.EndpointRequest{URL: "https://web.example.com", ProxyProtocol: "v1"},
+			err:  "proxyProtocol requires a tcp or tls endpoint",
+		},
+		{
+			name: "unknown proxy protocol",
+			req:  handler.EndpointRequest{URL: "tcp://", ProxyProtocol: "2"},
+			err:  `proxyProtocol must be v1 or v2, got "2"`,
+		},
+		{
+			name: "tls termination on tcp endpoint",
+			req:  handler.EndpointRequest{URL: "tcp://1.tcp.ngrok.io:12345", TLSTermination: "edge"},
+			err:  "tlsTermination requires a tls endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			env := setupTestEnvironment(t, ctrl)
+
+			req := tt.req
+			req.ContainerID = "db"
+			req.TargetPort = "5432"
+			req.ExpectedState = "offline"
+
+			var errorResponse map[string]string
+			env.apiRequest(&APIRequest{
+				Method:       http.MethodPost,
+				Path:         "/endpoints",
+				RequestBody:  req,
+				ResponseBody: &errorResponse,
+				ExpectedCode: http.StatusBadRequest,
+			})
+			assert.Contains(t, errorResponse["error"], tt.err)
+		})
+	}
+
+	// Reserved, assigned and internal addresses are accepted
+	ctrl := gomock.NewController(t)
+	env := setupTestEnvironment(t, ctrl)
+	for i, url := range []string{"tcp://1.tcp.ngrok.io:12345", "tcp://5.tcp.eu.ngrok.io:20000", "tcp://", "tcp://db.internal:5432"} {
+		env.postEndpoint(handler.EndpointRequest{
+			ContainerID:   "db",
+			TargetPort:    fmt.Sprint(5432 + i),
+			URL:           url,
+			ExpectedState: "offline",
+		})
+	}
+}
+
+func TestPostEndpoints_TLSPassthroughByDefault(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.expectNewAgent().Times(1)
+	env.expectAgentConnect().Times(1)
+
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "8443").
+		Return(&detectproto.Result{TCP: true, TLS: true}, nil).
+		Times(1)
+
+	// Without tlsTermination the container receives the client's encrypted
+	// stream, with no PROXY protocol header
+	var capturedUpstream string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			capturedUpstream = fmt.Sprintf("%+v", upstream)
+		}).
+		Return(env.createMockForwarder(ctrl, "tls://app.example.com:443", "ep_app"), nil).
+		Times(1)
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "app",
+		TargetPort:    "8443",
+		URL:           "tls://app.example.com",
+		ExpectedState: "online",
+	})
+
+	assert.Equal(t, "passthrough", endpoint.TLSTermination)
+	assert.Empty(t, endpoint.ProxyProtocol)
+	assert.Contains(t, capturedUpstream, "addr:tcp://172.17.0.1:8443 ")
+	assert.Contains(t, capturedUpstream, "proxyProto: ")
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "passthrough", state.EndpointConfigs["app:8443"].TLSTermination)
+}
+
+func TestTLSEndpointsSavedWithoutTerminationKeepTheirUpstream(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.expectNewAgent().Times(1)
+	env.expectAgentConnect().Times(1)
+	env.expectDockerContainer("legacy", true)
+
+	env.MockProtocolDetector.EXPECT().
+		Detect(gomock.Any(), "172.17.0.1", "8443").
+		Return(&detectproto.Result{TCP: true, TLS: true}, nil).
+		Times(1)
+
+	var capturedUpstream string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			capturedUpstream = fmt.Sprintf("%+v", upstream)
+		}).
+		Return(env.createMockForwarder(ctrl, "tls://legacy.example.com:443", "ep_legacy"), nil).
+		Times(1)
+
+	// An endpoint saved before tlsTermination existed, migrated on startup
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "offline"},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"legacy:8443": {ID: "legacy:8443", ContainerID: "legacy", TargetPort: "8443", URL: "tls://legacy.example.com", ExpectedState: "online"},
+		},
+		Version: 1,
+	})
+	require.NoError(t, env.Store.Update(func(state *store.State) error {
+		assert.Equal(t, []string{"legacy:8443"}, manager.MigrateTLSTermination(state))
+		return nil
+	}))
+
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	// It still forwards to a tls:// upstream, as it did before
+	assert.Contains(t, capturedUpstream, "addr:tls://172.17.0.1:8443 ")
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "edge", state.EndpointConfigs["legacy:8443"].TLSTermination)
+}
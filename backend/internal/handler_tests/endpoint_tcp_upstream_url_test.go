@@ -263,12 +263,13 @@ func TestPostEndpoints_TLSDetection_TLSScheme(t *testing.T) {
 	// Mock Docker to return container as available
 	env.expectDockerContainer("test-container-123", true)
 
-	// Create endpoint request with TLS URL
+	// Create endpoint request with TLS URL, terminated at the edge
 	endpointRequest := handler.EndpointRequest{
-		ContainerID:   "test-container-123",
-		TargetPort:    "8080",
-		URL:           "tls://custom-tls-endpoint.ngrok.io", // TLS scheme
-		ExpectedState: "online",
+		ContainerID:    "test-container-123",
+		TargetPort:     "8080",
+		URL:            "tls://custom-tls-endpoint.ngrok.io", // TLS scheme
+		TLSTermination: "edge",
+		ExpectedState:  "online",
 	}
 
 	// Execute request
@@ -279,7 +280,8 @@ func TestPostEndpoints_TLSDetection_TLSScheme(t *testing.T) {
 	assert.Equal(t, "test-container-123", actualResponse.ContainerID)
 	assert.Equal(t, "8080", actualResponse.TargetPort)
 
-	// ASSERTION: When TLS is detected and URL is tls, upstream should use tls scheme
+	// ASSERTION: When TLS is detected and URL is tls terminated at the edge,
+	// upstream should use tls scheme
 	assert.Equal(t, "tls://172.17.0.1:8080", capturedUpstreamURL,
-		"Expected upstream URL to use tls scheme when TLS is detected with tls URL")
+		"Expected upstream URL to use tls scheme when TLS is detected with tls URL terminated at the edge")
 }
@@ -0,0 +1,164 @@
+package handler_tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPostEndpoints_UpstreamTarget(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	mockForwarder := mocks.NewMockEndpointForwarder(ctrl)
+	expectedURL, _ := url.Parse("https://dev-server.ngrok.io")
+	mockForwarder.EXPECT().URL().Return(expectedURL).AnyTimes()
+
+	env.expectNewAgent().Times(1)
+	env.expectAgentConnect().Times(1)
+
+	// Upstream URLs name their scheme: neither Docker nor the protocol
+	// detector is consulted
+	var capturedUpstreamURL string
+	env.MockAgent.EXPECT().
+		Forward(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(ctx interface{}, upstream interface{}, opts ...interface{}) {
+			upstreamStr := fmt.Sprintf("%+v", upstream)
+			if parts := strings.Split(upstreamStr, "addr:"); len(parts) > 1 {
+				capturedUpstreamURL = strings.Fields(parts[1])[0]
+			}
+		}).
+		Return(mockForwarder, nil).
+		Times(1)
+
+	env.putAgent(store.AgentConfig{
+		AuthToken:     "ngrok_test_token",
+		ExpectedState: "online",
+	})
+
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		Upstream:      "http://host.docker.internal",
+		ExpectedState: "online",
+	})
+
+	assert.Equal(t, "upstream:host.docker.internal:80", endpoint.ID)
+	assert.Equal(t, manager.TargetTypeUpstream, endpoint.TargetType)
+	assert.Equal(t, "http://host.docker.internal:80", endpoint.Upstream)
+	assert.Empty(t, endpoint.ContainerID)
+	assert.Equal(t, "http://host.docker.internal:80", capturedUpstreamURL)
+
+	// Upstream endpoints are listed alongside container endpoints
+	endpoints := env.getEndpoints()
+	require.Len(t, endpoints.Endpoints, 1)
+	assert.Equal(t, endpoint.ID, endpoints.Endpoints[0].ID)
+}
+
+func TestPostEndpoints_UpstreamTargetValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  handler.EndpointRequest
+		err  string
+	}{
+		{
+			name: "no target",
+			req:  handler.EndpointRequest{ExpectedState: "online"},
+			err:  "containerId or upstream is required",
+		},
+		{
+			name: "both targets",
+			req:  handler.EndpointRequest{ContainerID: "web", TargetPort: "8080", Upstream: "http://web:8080", ExpectedState: "online"},
+			err:  "upstream cannot be combined with containerId and targetPort",
+		},
+		{
+			name: "unsupported scheme",
+			req:  handler.EndpointRequest{Upstream: "udp://dns:53", ExpectedState: "online"},
+			err:  "scheme must be http, https, tcp or tls",
+		},
+		{
+			name: "tcp without port",
+			req:  handler.EndpointRequest{Upstream: "tcp://db", ExpectedState: "online"},
+			err:  "tcp upstreams require a port",
+		},
+		{
+			name: "path",
+			req:  handler.EndpointRequest{Upstream: "http://api:3000/v1", ExpectedState: "online"},
+			err:  "paths and queries are not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			env := setupTestEnvironment(t, ctrl)
+
+			var errorResponse map[string]string
+			env.apiRequest(&APIRequest{
+				Method:       http.MethodPost,
+				Path:         "/endpoints",
+				RequestBody:  tt.req,
+				ResponseBody: &errorResponse,
+				ExpectedCode: http.StatusBadRequest,
+			})
+			assert.Contains(t, errorResponse["error"], tt.err)
+		})
+	}
+}
+
+func TestPutEndpointByID_UpstreamTargetIDMismatch(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	env := setupTestEnvironment(t, ctrl)
+
+	var errorResponse map[string]string
+	env.apiRequest(&APIRequest{
+		Method: http.MethodPut,
+		Path:   "/endpoints/upstream:db:5432",
+		RequestBody: handler.EndpointRequest{
+			Upstream:      "tcp://db:5433",
+			ExpectedState: "offline",
+		},
+		ResponseBody: &errorResponse,
+		ExpectedCode: http.StatusBadRequest,
+	})
+	assert.Contains(t, errorResponse["error"], "endpoint ID must match")
+
+	endpoint := env.putEndpoint("upstream:db:5432", handler.EndpointRequest{
+		Upstream:      "tcp://db:5432",
+		ExpectedState: "offline",
+	})
+	assert.Equal(t, "tcp://db:5432", endpoint.Upstream)
+}
+
+func TestEndpointByID_EscapedUpstreamID(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	env := setupTestEnvironment(t, ctrl)
+
+	// The helpers escape the ID, as the CLI does: its brackets are not
+	// valid in a path
+	endpoint := env.putEndpoint("upstream:[::1]:3000", handler.EndpointRequest{
+		Upstream:      "tcp://[::1]:3000",
+		ExpectedState: "offline",
+	})
+	assert.Equal(t, "upstream:[::1]:3000", endpoint.ID)
+
+	assert.Equal(t, "tcp://[::1]:3000", env.getEndpointByID(endpoint.ID).Upstream)
+	env.deleteEndpoint(endpoint.ID)
+	env.getEndpointByIDExpectingError(endpoint.ID, http.StatusNotFound)
+}
@@ -0,0 +1,145 @@
+package handler_tests
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/gitops"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+)
+
+// setupGitOpsEnvironment creates a test environment whose desired state is
+// owned by a configuration file
+func setupGitOpsEnvironment(t *testing.T, ctrl *gomock.Controller, config string) *TestEnv {
+	path := filepath.Join(t.TempDir(), "ngrok-extension.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+
+	slogger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	source, err := gitops.NewStore(path, func(string) string { return "test_token" }, slogger)
+	require.NoError(t, err)
+
+	e := echo.New()
+	mockNgrok := mocks.NewMockNgrokSDK(ctrl)
+	mockDocker := mocks.NewMockDockerClient(ctrl)
+	mockProtocolDetector := mocks.NewMockProtocolDetector(ctrl)
+	mgr := manager.NewManager(source, mockNgrok, mockDocker, mockProtocolDetector, nil, nil, nil, slogger, "test-extension-version", 0)
+	h := handler.New(e, mgr, source, mockDocker, slogger,
+		handler.WithProtocolDetector(mockProtocolDetector),
+		handler.WithGitOps(source),
+	)
+
+	return &TestEnv{
+		T:                    t,
+		Echo:                 e,
+		Handler:              h,
+		Store:                source,
+		Manager:              mgr,
+		MockNgrok:            mockNgrok,
+		MockDocker:           mockDocker,
+		MockProtocolDetector: mockProtocolDetector,
+		MockAgent:            mocks.NewMockAgent(ctrl),
+	}
+}
+
+func TestGitOps_RejectsChanges(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupGitOpsEnvironment(t, ctrl, "agent:\n  authTokenEnv: NGROK_AUTHTOKEN\n  expectedState: offline\n")
+
+	requests := []struct{ method, path string }{
+		{http.MethodPut, "/agent"},
+		{http.MethodPatch, "/agent"},
+		{http.MethodPost, "/endpoints"},
+		{http.MethodPut, "/endpoints/web:80"},
+		{http.MethodPatch, "/endpoints/web:80"},
+		{http.MethodDelete, "/endpoints/web:80"},
+		{http.MethodPost, "/endpoints:batch"},
+	}
+	for _, req := range requests {
+		var resp handler.ErrorResponse
+		env.apiRequest(&APIRequest{
+			Method:       req.method,
+			Path:         req.path,
+			RequestBody:  map[string]string{},
+			ResponseBody: &resp,
+			ExpectedCode: http.StatusConflict,
+		})
+		assert.Contains(t, resp.Error, "GitOps mode", "%s %s", req.method, req.path)
+	}
+
+	// Reads are still served from the file
+	var agent handler.AgentResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/agent",
+		ResponseBody: &agent,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, "test_token", agent.AuthToken)
+	assert.Equal(t, "offline", agent.ExpectedState)
+}
+
+func TestGitOps_ReportsDrift(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupGitOpsEnvironment(t, ctrl, `
+agent:
+  authTokenEnv: NGROK_AUTHTOKEN
+  expectedState: offline
+endpoints:
+  - containerId: web
+    targetPort: "80"
+  - containerId: db
+    targetPort: "5432"
+    expectedState: offline
+`)
+
+	var resp handler.GetGitOpsResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/gitops",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.True(t, resp.Enabled)
+	require.NotNil(t, resp.Source)
+	assert.Equal(t, "ngrok-extension.yaml", filepath.Base(resp.Source.Path))
+	assert.Empty(t, resp.Source.Error)
+	assert.Equal(t, []gitops.Drift{
+		{Resource: "endpoint", ID: "web:80", Desired: "online", Actual: "offline"},
+	}, resp.Drift)
+}
+
+func TestGetGitOps_Disabled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	var resp handler.GetGitOpsResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/gitops",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.False(t, resp.Enabled)
+	assert.Nil(t, resp.Source)
+	assert.Empty(t, resp.Drift)
+}
@@ -0,0 +1,136 @@
+package handler_tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func healthStatuses(resp handler.HealthResponse) map[string]diagnostics.Status {
+	statuses := map[string]diagnostics.Status{}
+	for _, check := range resp.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestGetReadyz_WaitsForInitialConvergence(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.Store.Save(&store.State{AgentConfig: store.AgentConfig{ExpectedState: "offline"}, Version: 1})
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{APIVersion: "1.47"}, nil).Times(2)
+
+	var resp handler.HealthResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/readyz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusServiceUnavailable,
+	})
+	assert.Equal(t, diagnostics.StatusFail, resp.Status)
+	assert.Equal(t, map[string]diagnostics.Status{
+		"converge_loop":       diagnostics.StatusPass,
+		"initial_convergence": diagnostics.StatusFail,
+		"store":               diagnostics.StatusPass,
+		"docker":              diagnostics.StatusPass,
+	}, healthStatuses(resp))
+
+	require.NoError(t, env.Manager.Converge(context.Background()))
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/readyz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, diagnostics.StatusPass, resp.Status)
+}
+
+func TestGetReadyz_DockerUnreachable(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	require.NoError(t, env.Manager.Converge(context.Background()))
+	env.MockDocker.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, errors.New("socket not found"))
+
+	var resp handler.HealthResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/readyz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusServiceUnavailable,
+	})
+	assert.Equal(t, diagnostics.StatusFail, healthStatuses(resp)["docker"])
+
+	// Liveness does not depend on Docker
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/healthz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, diagnostics.StatusPass, resp.Status)
+}
+
+func TestGetHealthz_StuckConvergence(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.Handler.StuckConvergeThreshold = 50 * time.Millisecond
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{AuthToken: "test-token", ExpectedState: "online"},
+		Version:     1,
+	})
+
+	// The SDK hangs while creating the agent
+	release := make(chan struct{})
+	env.MockNgrok.EXPECT().NewAgent(gomock.Any()).DoAndReturn(func(...ngrok.AgentOption) (ngrok.Agent, error) {
+		<-release
+		return nil, errors.New("connection reset")
+	})
+	converged := make(chan error, 1)
+	go func() { converged <- env.Manager.Converge(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		return env.apiRequest(&APIRequest{Method: http.MethodGet, Path: "/healthz"}).Code == http.StatusServiceUnavailable
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var resp handler.HealthResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/healthz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusServiceUnavailable,
+	})
+	assert.Equal(t, diagnostics.StatusFail, healthStatuses(resp)["converge_loop"])
+
+	close(release)
+	require.Error(t, <-converged)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/healthz",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, diagnostics.StatusPass, resp.Status)
+}
@@ -0,0 +1,138 @@
+package handler_tests
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
+)
+
+func TestPutLogging(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	managerLogger := env.Logs.Logger(logging.SubsystemManager)
+	storeLogger := env.Logs.Logger(logging.SubsystemStore)
+
+	var config logging.Config
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/logging",
+		ResponseBody: &config,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, logging.Config{Level: "info"}, config)
+	assert.False(t, managerLogger.Enabled(context.Background(), slog.LevelDebug))
+
+	// Levels change at runtime, for existing loggers too
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/logging",
+		RequestBody:  logging.Config{Level: "warn", Subsystems: map[string]string{"manager": "debug"}},
+		ResponseBody: &config,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Equal(t, "warn", config.Level)
+	assert.Equal(t, map[string]string{"manager": "debug"}, config.Subsystems)
+	assert.True(t, managerLogger.Enabled(context.Background(), slog.LevelDebug))
+	assert.False(t, storeLogger.Enabled(context.Background(), slog.LevelInfo))
+
+	var errorResponse map[string]string
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/logging",
+		RequestBody:  logging.Config{Level: "info", Subsystems: map[string]string{"ui": "debug"}},
+		ResponseBody: &errorResponse,
+		ExpectedCode: http.StatusBadRequest,
+	})
+	assert.Contains(t, errorResponse["error"], `unknown subsystem "ui"`)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/logging",
+		RequestBody:  logging.Config{Level: "verbose"},
+		ExpectedCode: http.StatusBadRequest,
+	})
+}
+
+func TestGetLogs(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	since := time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+	env.Logs.Logger(logging.SubsystemManager).Info("Endpoint online", "endpoint", "web:80")
+	env.Logs.Logger(logging.SubsystemStore).Warn("State file is malformed")
+
+	var response handler.GetLogsResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/logs?since=" + since,
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, response.Entries, 2)
+	assert.Equal(t, "manager", response.Entries[0].Subsystem)
+	assert.Equal(t, "Endpoint online", response.Entries[0].Message)
+	assert.Equal(t, map[string]any{"endpoint": "web:80"}, response.Entries[0].Attrs)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/logs?level=warn",
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+	require.Len(t, response.Entries, 1)
+	assert.Equal(t, "WARN", response.Entries[0].Level)
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/logs?level=loud",
+		ExpectedCode: http.StatusBadRequest,
+	})
+}
+
+func TestGetLogs_Follow(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	logger := env.Logs.Logger(logging.SubsystemManager)
+	logger.Info("first")
+
+	server := httptest.NewServer(env.Echo)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/logs?follow=true&subsystem=manager", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The written entries come first, then new ones as they are written
+	lines := bufio.NewScanner(resp.Body)
+	require.True(t, lines.Scan())
+	assert.Contains(t, lines.Text(), `"msg":"first"`)
+
+	env.Logs.Logger(logging.SubsystemStore).Info("not followed")
+	logger.Info("second")
+	require.True(t, lines.Scan())
+	assert.Contains(t, lines.Text(), `"msg":"second"`)
+}
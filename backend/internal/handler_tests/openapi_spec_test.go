@@ -0,0 +1,180 @@
+package handler_tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
+	"github.com/ngrok/ngrok-docker-extension/internal/audit"
+	"github.com/ngrok/ngrok-docker-extension/internal/containers"
+	"github.com/ngrok/ngrok-docker-extension/internal/diagnostics"
+	"github.com/ngrok/ngrok-docker-extension/internal/gitops"
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// openAPIDocument is the subset of an OpenAPI 3 document the tests inspect
+type openAPIDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadOpenAPIDocument(t *testing.T) openAPIDocument {
+	var doc openAPIDocument
+	require.NoError(t, json.Unmarshal(handler.OpenAPISpec(), &doc))
+	return doc
+}
+
+func TestOpenAPI_ServedDocumentMatchesEmbeddedSpec(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	rec := env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/openapi.json",
+		ExpectedCode: http.StatusOK,
+	})
+	assert.JSONEq(t, string(handler.OpenAPISpec()), rec.Body.String())
+}
+
+func TestOpenAPI_DocumentsEveryRegisteredRoute(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	doc := loadOpenAPIDocument(t)
+
+	// Echo uses :param and escapes literal colons; OpenAPI uses {param}
+	echoParam := regexp.MustCompile(`(^|/):(\w+)`)
+	var registered []string
+	for _, route := range env.Echo.Routes() {
+		path := echoParam.ReplaceAllString(route.Path, "$1{$2}")
+		path = strings.ReplaceAll(path, `\:`, ":")
+		registered = append(registered, route.Method+" "+path)
+	}
+
+	var documented []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	slices.Sort(registered)
+	slices.Sort(documented)
+	assert.Equal(t, registered, documented, "openapi.json paths must match the routes registered by handler.New")
+}
+
+func TestOpenAPI_SchemasMatchGoTypes(t *testing.T) {
+	t.Parallel()
+	doc := loadOpenAPIDocument(t)
+
+	types := map[string]any{
+		"ErrorResponse":            handler.ErrorResponse{},
+		"AgentConfig":              store.AgentConfig{},
+		"AgentStatus":              manager.AgentStatus{},
+		"AgentResponse":            handler.AgentResponse{},
+		"EndpointRequest":          handler.EndpointRequest{},
+		"EndpointStatus":           manager.EndpointStatus{},
+		"EndpointRoute":            store.Route{},
+		"RouteStatus":              manager.RouteStatus{},
+		"RouterRule":               store.RouterRule{},
+		"Topology":                 manager.Topology{},
+		"TopologyNode":             manager.TopologyNode{},
+		"TopologyEdge":             manager.TopologyEdge{},
+		"StatusError":              manager.StatusError{},
+		"EndpointResponse":         handler.EndpointResponse{},
+		"GetEndpointsResponse":     handler.GetEndpointsResponse{},
+		"EndpointSelector":         handler.EndpointSelector{},
+		"BatchOperation":           handler.BatchOperation{},
+		"BatchRequest":             handler.BatchRequest{},
+		"BatchResult":              handler.BatchResult{},
+		"BatchResponse":            handler.BatchResponse{},
+		"DetectProtocolRequest":    handler.DetectProtocolRequest{},
+		"DetectProtocolResponse":   handler.DetectProtocolResponse{},
+		"AuditChange":              audit.Change{},
+		"AuditEntry":               audit.Entry{},
+		"GetAuditResponse":         handler.GetAuditResponse{},
+		"AccessLogEntry":           accesslog.Entry{},
+		"GetEndpointLogsResponse":  handler.GetEndpointLogsResponse{},
+		"LoggingConfig":            logging.Config{},
+		"LogEntry":                 logging.Entry{},
+		"GetLogsResponse":          handler.GetLogsResponse{},
+		"DiagnosticCheck":          diagnostics.Check{},
+		"DiagnosticEndpointReport": diagnostics.EndpointReport{},
+		"DiagnosticsReport":        diagnostics.Report{},
+		"HealthResponse":           handler.HealthResponse{},
+		"GitOpsStatus":             gitops.Status{},
+		"GitOpsDrift":              gitops.Drift{},
+		"GetGitOpsResponse":        handler.GetGitOpsResponse{},
+		"SecretInfo":               secrets.Info{},
+		"ListSecretsResponse":      handler.ListSecretsResponse{},
+		"PutSecretRequest":         handler.PutSecretRequest{},
+		"PortDetection":            containers.Detection{},
+		"ContainerPort":            containers.Port{},
+		"Container":                containers.Container{},
+		"ListContainersResponse":   handler.ListContainersResponse{},
+		"DiscoverRequest":          handler.DiscoverRequest{},
+		"PortSuggestion":           containers.Suggestion{},
+		"DiscoveredPort":           containers.DiscoveredPort{},
+		"ContainerDiscovery":       containers.Discovery{},
+	}
+
+	for name, value := range types {
+		schema, exists := doc.Components.Schemas[name]
+		if !assert.True(t, exists, "schema %s is missing", name) {
+			continue
+		}
+
+		var documented []string
+		for property := range schema.Properties {
+			documented = append(documented, property)
+		}
+		slices.Sort(documented)
+		assert.Equal(t, jsonFieldNames(reflect.TypeOf(value)), documented, "schema %s properties must match the Go type's JSON fields", name)
+	}
+}
+
+// jsonFieldNames returns the sorted JSON names of a struct's exported fields
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,150 @@
+package handler_tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPatchEndpointByID_OnlyModifiesProvidedFields(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "offline",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {
+				ID:            "web:80",
+				ContainerID:   "web",
+				TargetPort:    "80",
+				URL:           "https://web.ngrok.app",
+				TrafficPolicy: "on_http_request: []",
+				Description:   "old description",
+				ExpectedState: "offline",
+				LastStarted:   "2025-01-01T00:00:00Z",
+			},
+		},
+		Version: 1,
+	})
+
+	var response handler.EndpointResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPatch,
+		Path:         "/endpoints/web:80",
+		RequestBody:  map[string]any{"metadata": "team=web", "description": nil},
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, "team=web", response.Metadata)
+	assert.Empty(t, response.Description)
+	assert.Equal(t, "on_http_request: []", response.TrafficPolicy)
+	assert.Equal(t, "https://web.ngrok.app", response.URL)
+	assert.Equal(t, "offline", response.ExpectedState)
+	assert.Equal(t, "2025-01-01T00:00:00Z", response.LastStarted)
+
+	// The endpoint's identity cannot be patched
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPatch,
+		Path:         "/endpoints/web:80",
+		RequestBody:  map[string]any{"targetPort": "8080"},
+		ExpectedCode: http.StatusBadRequest,
+	})
+
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPatch,
+		Path:         "/endpoints/missing:80",
+		RequestBody:  map[string]any{"expectedState": "online"},
+		ExpectedCode: http.StatusNotFound,
+	})
+}
+
+func TestPatchEndpointByID_AlreadyOnlineKeepsLastStarted(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	mockForwarder := env.createMockForwarder(ctrl, "https://web.ngrok.app", "endpoint-id-web")
+	env.expectAgentForward().
+		Return(mockForwarder, nil).
+		Times(1)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "online",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {
+				ID:            "web:80",
+				ContainerID:   "web",
+				TargetPort:    "80",
+				ExpectedState: "online",
+				LastStarted:   "2025-01-01T00:00:00Z",
+			},
+		},
+		Version: 1,
+	})
+
+	var response handler.EndpointResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPatch,
+		Path:         "/endpoints/web:80",
+		RequestBody:  map[string]any{"description": "renamed", "expectedState": "online"},
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, "renamed", response.Description)
+	assert.Equal(t, "2025-01-01T00:00:00Z", response.LastStarted)
+	assert.Equal(t, manager.EndpointStateOnline, response.Status.State)
+}
+
+func TestPatchAgent_PreservesAuthToken(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "offline",
+		},
+		Version: 1,
+	})
+
+	var response handler.AgentResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPatch,
+		Path:         "/agent",
+		RequestBody:  map[string]any{"connectURL": "https://connect.example.com"},
+		ResponseBody: &response,
+		ExpectedCode: http.StatusOK,
+	})
+
+	assert.Equal(t, "ngrok_test_token", response.AuthToken)
+	assert.Equal(t, "https://connect.example.com", response.ConnectURL)
+	assert.Equal(t, "offline", response.ExpectedState)
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "ngrok_test_token", state.AgentConfig.AuthToken)
+}
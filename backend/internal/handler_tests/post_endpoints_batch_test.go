@@ -0,0 +1,241 @@
+package handler_tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestPostEndpointsBatch_StartComposeProjectAndReportPerItemResults(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.setupStandardMockExpectations()
+
+	// Only the two endpoints of the "shop" project should be forwarded
+	mockForwarder := env.createMockForwarder(ctrl, "https://shop.ngrok.io", "endpoint-id-shop")
+	env.expectAgentForward().
+		Return(mockForwarder, nil).
+		Times(2)
+
+	env.expectDockerContainerWithLabels("shop-web", map[string]string{"com.docker.compose.project": "shop"})
+	env.expectDockerContainerWithLabels("shop-api", map[string]string{"com.docker.compose.project": "shop"})
+	env.expectDockerContainerWithLabels("blog-web", map[string]string{"com.docker.compose.project": "blog"})
+	env.expectDockerContainerWithLabels("blog-db", map[string]string{"com.docker.compose.project": "blog"})
+
+	env.Store.Save(&store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "offline",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"shop-web:80":   {ID: "shop-web:80", ContainerID: "shop-web", TargetPort: "80", ExpectedState: "offline"},
+			"shop-api:8080": {ID: "shop-api:8080", ContainerID: "shop-api", TargetPort: "8080", ExpectedState: "offline"},
+			"blog-web:80":   {ID: "blog-web:80", ContainerID: "blog-web", TargetPort: "80", ExpectedState: "offline"},
+		},
+		Version: 1,
+	})
+
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Operations: []handler.BatchOperation{
+			{
+				Op:       handler.BatchOpStart,
+				Selector: &handler.EndpointSelector{ComposeProject: "shop"},
+			},
+			{
+				Op: handler.BatchOpCreate,
+				Endpoint: &handler.EndpointRequest{
+					ContainerID:   "blog-db",
+					TargetPort:    "5432",
+					ExpectedState: "offline",
+				},
+			},
+			{
+				Op: handler.BatchOpDelete,
+				ID: "missing:80",
+			},
+		},
+	}, http.StatusOK)
+
+	assert.True(t, response.Applied)
+	require.Len(t, response.Results, 3)
+
+	// Selector-based start hits both shop endpoints in one converge
+	assert.Equal(t, http.StatusOK, response.Results[0].Status)
+	assert.Equal(t, []string{"shop-api:8080", "shop-web:80"}, response.Results[0].IDs)
+	require.Len(t, response.Results[0].Endpoints, 2)
+	for _, endpoint := range response.Results[0].Endpoints {
+		assert.Equal(t, manager.EndpointStateOnline, endpoint.Status.State)
+		assert.NotEmpty(t, endpoint.LastStarted)
+	}
+
+	assert.Equal(t, http.StatusCreated, response.Results[1].Status)
+	assert.Equal(t, []string{"blog-db:5432"}, response.Results[1].IDs)
+
+	assert.Equal(t, http.StatusNotFound, response.Results[2].Status)
+	assert.NotEmpty(t, response.Results[2].Error)
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "online", state.AgentConfig.ExpectedState)
+	assert.Equal(t, "online", state.EndpointConfigs["shop-web:80"].ExpectedState)
+	assert.Equal(t, "online", state.EndpointConfigs["shop-api:8080"].ExpectedState)
+	assert.Equal(t, "offline", state.EndpointConfigs["blog-web:80"].ExpectedState)
+	assert.Contains(t, state.EndpointConfigs, "blog-db:5432")
+}
+
+func TestPostEndpointsBatch_AtomicFailureAppliesNothing(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No agent or forwarder calls are expected because nothing converges
+	env := setupTestEnvironment(t, ctrl)
+
+	initialState := store.State{
+		AgentConfig: store.AgentConfig{
+			AuthToken:     "ngrok_test_token",
+			ExpectedState: "offline",
+		},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "offline"},
+		},
+		Version: 1,
+	}
+	env.Store.Save(&initialState)
+
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Atomic: true,
+		Operations: []handler.BatchOperation{
+			{Op: handler.BatchOpStart, ID: "web:80"},
+			{Op: handler.BatchOpStop, ID: "missing:80"},
+		},
+	}, http.StatusUnprocessableEntity)
+
+	assert.False(t, response.Applied)
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, response.Results[1].Status)
+
+	env.expectState(initialState)
+}
+
+func TestPostEndpointsBatch_EmptySelectorIsRejected(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	initialState := store.State{
+		AgentConfig: store.AgentConfig{ExpectedState: "offline"},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "offline"},
+		},
+		Version: 1,
+	}
+	env.Store.Save(&initialState)
+
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Operations: []handler.BatchOperation{
+			{Op: handler.BatchOpDelete, Selector: &handler.EndpointSelector{}},
+		},
+	}, http.StatusOK)
+
+	assert.False(t, response.Applied)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, http.StatusBadRequest, response.Results[0].Status)
+	assert.Equal(t, "selector must set at least one field", response.Results[0].Error)
+
+	env.expectState(initialState)
+}
+
+func TestPostEndpointsBatch_SelectorMatchesEarlierOperations(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.Store.Save(&store.State{AgentConfig: store.AgentConfig{ExpectedState: "offline"}, Version: 1})
+
+	// Selectors are matched against the state as the batch changes it
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Operations: []handler.BatchOperation{
+			{Op: handler.BatchOpCreate, Endpoint: &handler.EndpointRequest{ContainerID: "web", TargetPort: "80", ExpectedState: "offline"}},
+			{Op: handler.BatchOpDelete, Selector: &handler.EndpointSelector{ContainerID: "web"}},
+		},
+	}, http.StatusOK)
+
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+	assert.Equal(t, http.StatusOK, response.Results[1].Status)
+	assert.Equal(t, []string{"web:80"}, response.Results[1].IDs)
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, state.EndpointConfigs)
+}
+
+func TestPostEndpointsBatch_SelectorMatchesEndpointsCreatedInTheBatch(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.expectDockerContainerWithLabels("shop-web", map[string]string{"com.docker.compose.project": "shop"})
+	env.Store.Save(&store.State{AgentConfig: store.AgentConfig{ExpectedState: "offline"}, Version: 1})
+
+	// The labels of the created endpoint's container are looked up before
+	// the transaction, and the Docker mock fails any other inspection
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Operations: []handler.BatchOperation{
+			{Op: handler.BatchOpCreate, Endpoint: &handler.EndpointRequest{ContainerID: "shop-web", TargetPort: "80", ExpectedState: "offline"}},
+			{Op: handler.BatchOpDelete, Selector: &handler.EndpointSelector{ComposeProject: "shop"}},
+		},
+	}, http.StatusOK)
+
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, http.StatusOK, response.Results[1].Status)
+	assert.Equal(t, []string{"shop-web:80"}, response.Results[1].IDs)
+}
+
+func TestPostEndpointsBatch_SelectorMatchingNothingIsNotApplied(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No agent or forwarder calls are expected because nothing converges
+	env := setupTestEnvironment(t, ctrl)
+
+	initialState := store.State{
+		AgentConfig: store.AgentConfig{ExpectedState: "offline"},
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "offline"},
+		},
+		Version: 1,
+	}
+	env.Store.Save(&initialState)
+
+	response := env.postEndpointsBatch(handler.BatchRequest{
+		Operations: []handler.BatchOperation{
+			{Op: handler.BatchOpStart, Selector: &handler.EndpointSelector{ContainerID: "api"}},
+		},
+	}, http.StatusOK)
+
+	assert.False(t, response.Applied)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, http.StatusNotFound, response.Results[0].Status)
+	assert.Equal(t, "no endpoints matched the selector", response.Results[0].Error)
+	assert.Empty(t, response.Results[0].IDs)
+
+	env.expectState(initialState)
+}
@@ -0,0 +1,147 @@
+package handler_tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// putSecret stores a secret and returns the response
+func (env *TestEnv) putSecret(name, value string, expectedCode int) *secrets.Info {
+	var info secrets.Info
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPut,
+		Path:         "/secrets/" + name,
+		RequestBody:  handler.PutSecretRequest{Value: value},
+		ResponseBody: &info,
+		ExpectedCode: expectedCode,
+	})
+	return &info
+}
+
+func TestSecrets_ReferencesAreResolvedAndReconverged(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+	env.expectNewAgent().Times(1)
+	env.expectAgentConnect().Times(1)
+	env.expectHTTPProtocolDetection()
+	env.putAgent(store.AgentConfig{AuthToken: "ngrok_test_token", ExpectedState: "online"})
+
+	policy := "on_http_request:\n  - actions:\n      - type: basic-auth\n        config:\n          credentials: [\"${env:NGROK_EXT_SECRET_BASIC_AUTH_USER}:${secret:basic-auth-password}\"]\n"
+
+	// The secret does not exist yet, so the endpoint cannot start
+	endpoint := env.postEndpoint(handler.EndpointRequest{
+		ContainerID:   "web",
+		TargetPort:    "80",
+		TrafficPolicy: policy,
+		ExpectedState: "online",
+	})
+	assert.Equal(t, manager.EndpointStateFailed, endpoint.Status.State)
+	require.NotNil(t, endpoint.Status.Error)
+	assert.Equal(t, manager.ErrorCodeSecretUnresolved, endpoint.Status.Error.Code)
+	assert.Contains(t, endpoint.Status.LastError, "${secret:basic-auth-password}")
+
+	// Creating the secret starts the endpoint
+	first := env.createMockForwarder(ctrl, "https://web.ngrok.io", "ep_1")
+	first.EXPECT().Close().Return(nil).Times(1)
+	env.expectAgentForward().Return(first, nil).Times(1)
+
+	info := env.putSecret("basic-auth-password", "hunter2", http.StatusCreated)
+	assert.Equal(t, "basic-auth-password", info.Name)
+	assert.Equal(t, manager.EndpointStateOnline, env.getEndpointByID("web:80").Status.State)
+
+	// Changing the secret recreates the endpoint
+	second := env.createMockForwarder(ctrl, "https://web.ngrok.io", "ep_2")
+	env.expectAgentForward().Return(second, nil).Times(1)
+
+	env.putSecret("basic-auth-password", "correct-horse", http.StatusOK)
+	assert.Equal(t, manager.EndpointStateOnline, env.getEndpointByID("web:80").Status.State)
+
+	// Resolved values never leave the extension
+	assert.Equal(t, policy, env.getEndpointByID("web:80").TrafficPolicy)
+	rec := env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/secrets",
+		ExpectedCode: http.StatusOK,
+	})
+	assert.NotContains(t, rec.Body.String(), "correct-horse")
+	assert.Contains(t, rec.Body.String(), `"name":"basic-auth-password"`)
+}
+
+func TestSecrets_Delete(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.putSecret("token", "value", http.StatusCreated)
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodDelete,
+		Path:         "/secrets/token",
+		ExpectedCode: http.StatusNoContent,
+	})
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodDelete,
+		Path:         "/secrets/token",
+		ExpectedCode: http.StatusNotFound,
+	})
+
+	var resp handler.ListSecretsResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodGet,
+		Path:         "/secrets",
+		ResponseBody: &resp,
+		ExpectedCode: http.StatusOK,
+	})
+	assert.Empty(t, resp.Secrets)
+}
+
+func TestSecrets_PutValidation(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	env.putSecret("bad$name", "value", http.StatusBadRequest)
+	env.putSecret("token", "", http.StatusBadRequest)
+}
+
+func TestSecrets_EnvReferencesNeedPrefix(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := setupTestEnvironment(t, ctrl)
+
+	// The extension's own credentials cannot be read into an endpoint
+	for _, req := range []handler.EndpointRequest{
+		{ContainerID: "web", TargetPort: "80", ExpectedState: "offline", Metadata: "${env:NGROK_AUTHTOKEN}"},
+		{ContainerID: "web", TargetPort: "80", ExpectedState: "offline", TrafficPolicy: "credentials: ['${env:NGROK_EXT_API_TOKENS}']"},
+		{ContainerID: "web", TargetPort: "80", ExpectedState: "offline", URL: "https://${env:HOSTNAME}.ngrok.app"},
+	} {
+		rec := env.apiRequest(&APIRequest{
+			Method:       http.MethodPost,
+			Path:         "/endpoints",
+			RequestBody:  req,
+			ExpectedCode: http.StatusBadRequest,
+		})
+		assert.Contains(t, rec.Body.String(), "forbidden reference")
+	}
+
+	state, err := env.Store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, state.EndpointConfigs)
+}
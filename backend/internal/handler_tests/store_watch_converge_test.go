@@ -0,0 +1,44 @@
+package handler_tests
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager"
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestManager_ConvergesOnStoreChange(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	fileStore := store.NewFileStore(path)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// The interval is long enough that only the store change can trigger a
+	// converge during the test
+	mgr := manager.NewManager(fileStore, mocks.NewMockNgrokSDK(ctrl), mocks.NewMockDockerClient(ctrl),
+		mocks.NewMockProtocolDetector(ctrl), nil, nil, nil, logger, "test-extension-version", time.Hour)
+	defer mgr.Shutdown(context.Background())
+
+	// An out-of-band edit, as made by a CLI or a mounted config file
+	edit := `{"agentConfig":{"authToken":"ngrok_test_token","expectedState":"offline"},` +
+		`"endpointConfigs":{"web:80":{"id":"web:80","containerId":"web","targetPort":"80","expectedState":"offline"}},"version":1}`
+	require.NoError(t, os.WriteFile(path, []byte(edit), 0600))
+
+	assert.Eventually(t, func() bool {
+		status, exists := mgr.EndpointStatus()["web:80"]
+		return exists && status.State == manager.EndpointStateOffline
+	}, 5*time.Second, 10*time.Millisecond, "manager should converge without waiting for the ticker")
+}
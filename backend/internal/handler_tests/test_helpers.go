@@ -10,18 +10,24 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
 	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
 	"github.com/ngrok/ngrok-docker-extension/internal/handler"
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
 	"github.com/ngrok/ngrok-docker-extension/internal/manager"
 	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
 )
 
@@ -29,7 +35,11 @@ import (
 type TestEnv struct {
 	T                    *testing.T
 	Echo                 *echo.Echo
+	Handler              *handler.Handler
 	Store                store.Store
+	Secrets              *secrets.Store
+	AccessLog            *accesslog.Log
+	Logs                 *logging.Logs
 	Manager              manager.Manager
 	MockNgrok            *mocks.MockNgrokSDK
 	MockDocker           *mocks.MockDockerClient
@@ -52,17 +62,36 @@ func setupTestEnvironment(t *testing.T, ctrl *gomock.Controller) *TestEnv {
 	mockAgent := mocks.NewMockAgent(ctrl)
 	slogger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
+	// Secrets resolve environment references from testEnvironment
+	secretStore := secrets.NewMemoryStore()
+	resolver := &secrets.Resolver{Secrets: secretStore, LookupEnv: lookupTestEnvironment}
+
+	// Probes go to the mock through the same cache the extension uses
+	detector := detectproto.NewCache(mockProtocolDetector, time.Minute, nil)
+
+	accessLog := accesslog.NewLog(t.TempDir(), slogger)
+	logs := logging.New(filepath.Join(t.TempDir(), "backend.log"), nil)
+
 	// Construct manager using constructor (now uses slog.Logger)
 	// Use 0 interval to disable converge loop in tests
-	mgr := manager.NewManager(memoryStore, mockNgrok, mockDocker, mockProtocolDetector, slogger, "test-extension-version", 0)
+	mgr := manager.NewManager(memoryStore, mockNgrok, mockDocker, detector, resolver, accessLog, nil, slogger, "test-extension-version", 0)
 
 	// Create handler using New (will register routes automatically)
-	_ = handler.New(e, mgr, memoryStore, slogger)
+	h := handler.New(e, mgr, memoryStore, mockDocker, slogger,
+		handler.WithProtocolDetector(detector),
+		handler.WithSecrets(secretStore),
+		handler.WithAccessLog(accessLog),
+		handler.WithLogs(logs),
+	)
 
 	return &TestEnv{
 		T:                    t,
 		Echo:                 e,
+		Handler:              h,
 		Store:                memoryStore,
+		Secrets:              secretStore,
+		AccessLog:            accessLog,
+		Logs:                 logs,
 		Manager:              mgr,
 		MockNgrok:            mockNgrok,
 		MockDocker:           mockDocker,
@@ -71,6 +100,15 @@ func setupTestEnvironment(t *testing.T, ctrl *gomock.Controller) *TestEnv {
 	}
 }
 
+// testEnvironment holds the environment variables visible to ${env:NAME}
+// references in tests
+var testEnvironment = map[string]string{"NGROK_EXT_SECRET_BASIC_AUTH_USER": "admin"}
+
+func lookupTestEnvironment(name string) (string, bool) {
+	value, ok := testEnvironment[name]
+	return value, ok
+}
+
 // makeJSONRequest creates a JSON request body from any object
 func makeJSONRequest(t *testing.T, data interface{}) []byte {
 	body, err := json.Marshal(data)
@@ -92,6 +130,7 @@ type APIRequest struct {
 	RequestBody  interface{} // Request body to marshal to JSON (nil for no body)
 	ResponseBody interface{} // Response body to unmarshal JSON into (must be pointer)
 	ExpectedCode int         // Expected HTTP status code
+	Headers      http.Header // Additional request headers
 }
 
 // apiRequest makes an HTTP request with optional JSON body, executes it, and unmarshals the response
@@ -112,6 +151,9 @@ func (env *TestEnv) apiRequest(req *APIRequest) *httptest.ResponseRecorder {
 	if req.RequestBody != nil {
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
+	for name, values := range req.Headers {
+		httpReq.Header[name] = values
+	}
 	rec := httptest.NewRecorder()
 
 	// Execute request
@@ -207,7 +249,7 @@ func (env *TestEnv) putEndpoint(endpointID string, req handler.EndpointRequest)
 	var response handler.EndpointResponse
 	env.apiRequest(&APIRequest{
 		Method:       http.MethodPut,
-		Path:         "/endpoints/" + endpointID,
+		Path:         "/endpoints/" + url.PathEscape(endpointID),
 		RequestBody:  req,
 		ResponseBody: &response,
 		ExpectedCode: http.StatusOK,
@@ -232,7 +274,7 @@ func (env *TestEnv) getEndpointByID(endpointID string) *handler.EndpointResponse
 	var response handler.EndpointResponse
 	env.apiRequest(&APIRequest{
 		Method:       http.MethodGet,
-		Path:         "/endpoints/" + endpointID,
+		Path:         "/endpoints/" + url.PathEscape(endpointID),
 		ResponseBody: &response,
 		ExpectedCode: http.StatusOK,
 	})
@@ -244,7 +286,7 @@ func (env *TestEnv) getEndpointByIDExpectingError(endpointID string, expectedCod
 	var errorResponse map[string]string
 	env.apiRequest(&APIRequest{
 		Method:       http.MethodGet,
-		Path:         "/endpoints/" + endpointID,
+		Path:         "/endpoints/" + url.PathEscape(endpointID),
 		ResponseBody: &errorResponse,
 		ExpectedCode: expectedCode,
 	})
@@ -255,7 +297,7 @@ func (env *TestEnv) getEndpointByIDExpectingError(endpointID string, expectedCod
 func (env *TestEnv) deleteEndpoint(endpointID string) {
 	env.apiRequest(&APIRequest{
 		Method:       http.MethodDelete,
-		Path:         "/endpoints/" + endpointID,
+		Path:         "/endpoints/" + url.PathEscape(endpointID),
 		ExpectedCode: http.StatusNoContent,
 	})
 }
@@ -265,7 +307,7 @@ func (env *TestEnv) deleteEndpointExpectingError(endpointID string, expectedCode
 	var errorResponse map[string]string
 	env.apiRequest(&APIRequest{
 		Method:       http.MethodDelete,
-		Path:         "/endpoints/" + endpointID,
+		Path:         "/endpoints/" + url.PathEscape(endpointID),
 		ResponseBody: &errorResponse,
 		ExpectedCode: expectedCode,
 	})
@@ -390,3 +432,33 @@ func (env *TestEnv) createMockForwarder(ctrl *gomock.Controller, urlStr, id stri
 	mockForwarder.EXPECT().ID().Return(id).AnyTimes()
 	return mockForwarder
 }
+
+// postEndpointsBatch applies a batch of endpoint operations using POST /endpoints:batch
+func (env *TestEnv) postEndpointsBatch(req handler.BatchRequest, expectedCode int) *handler.BatchResponse {
+	var response handler.BatchResponse
+	env.apiRequest(&APIRequest{
+		Method:       http.MethodPost,
+		Path:         "/endpoints:batch",
+		RequestBody:  req,
+		ResponseBody: &response,
+		ExpectedCode: expectedCode,
+	})
+	return &response
+}
+
+// expectDockerContainerWithLabels sets up mock expectations for inspecting a running container with labels
+func (env *TestEnv) expectDockerContainerWithLabels(name string, labels map[string]string) {
+	env.MockDocker.EXPECT().
+		ContainerInspect(gomock.Any(), name).
+		Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{
+					Running: true,
+				},
+			},
+			Config: &container.Config{
+				Labels: labels,
+			},
+		}, nil).
+		AnyTimes()
+}
@@ -0,0 +1,326 @@
+// Package logging writes the backend's logs to stdout and a rotating file,
+// with a level per subsystem that can be changed at runtime, and reads them
+// back for the API.
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/ndjson"
+)
+
+// Subsystems with their own level. Records of other loggers use the default
+// level.
+const (
+	SubsystemManager     = "manager"
+	SubsystemStore       = "store"
+	SubsystemDetectProto = "detectproto"
+	SubsystemHTTP        = "http"
+)
+
+// Subsystems lists every subsystem
+var Subsystems = []string{SubsystemManager, SubsystemStore, SubsystemDetectProto, SubsystemHTTP}
+
+// Rotation defaults of New
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024 // bytes per file
+	DefaultMaxBackups = 3                // rotated files kept
+)
+
+// Config sets the levels of the logs
+type Config struct {
+	Level      string            `json:"level"`                // debug | info | warn | error
+	Subsystems map[string]string `json:"subsystems,omitempty"` // levels overriding Level, by subsystem
+}
+
+// Entry is one record of the logs
+type Entry struct {
+	Time      time.Time      `json:"time"`
+	Level     string         `json:"level"` // DEBUG | INFO | WARN | ERROR
+	Subsystem string         `json:"subsystem,omitempty"`
+	Message   string         `json:"msg"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+}
+
+// Filter selects entries from the logs. Zero values match everything.
+type Filter struct {
+	Since     time.Time
+	Level     slog.Leveler // the minimum level, nil for every level
+	Subsystem string
+	Limit     int // keep only the most recent entries
+}
+
+// Matches reports whether entry is selected by f
+func (f Filter) Matches(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.Level != nil {
+		if level, err := ParseLevel(entry.Level); err == nil && level < f.Level.Level() {
+			return false
+		}
+	}
+	if f.Subsystem != "" && entry.Subsystem != f.Subsystem {
+		return false
+	}
+	return true
+}
+
+// ParseLevel parses a level name such as "debug" or "WARN"
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("level must be debug, info, warn or error, got %q", name)
+	}
+	return level, nil
+}
+
+// ParseSubsystemLevels parses comma-separated subsystem=level pairs such as
+// "manager=debug,http=warn"
+func ParseSubsystemLevels(s string) (map[string]string, error) {
+	levels := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		subsystem, level, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q must be subsystem=level", pair)
+		}
+		levels[strings.TrimSpace(subsystem)] = strings.TrimSpace(level)
+	}
+	return levels, nil
+}
+
+// Logs writes the records of every logger it creates to stdout and a file,
+// rotated once it reaches a size
+type Logs struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	stdout     io.Writer
+	levels     map[string]*slog.LevelVar // by subsystem, "" for the default
+
+	configMu sync.Mutex
+	config   Config
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	followers *ndjson.Followers[Entry] // under the key ""
+}
+
+// New creates logs written to stdout and the file at path with the default
+// rotation, at info level
+func New(path string, stdout io.Writer) *Logs {
+	return NewWithRotation(path, DefaultMaxSize, DefaultMaxBackups, stdout)
+}
+
+// NewWithRotation creates logs written to stdout and the file at path,
+// rotated once it reaches maxSize bytes, keeping maxBackups rotated files
+func NewWithRotation(path string, maxSize int64, maxBackups int, stdout io.Writer) *Logs {
+	l := &Logs{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		stdout:     stdout,
+		levels:     map[string]*slog.LevelVar{"": new(slog.LevelVar)},
+		config:     Config{Level: "info"},
+	}
+	l.followers = ndjson.NewFollowers[Entry](&l.mu)
+	for _, subsystem := range Subsystems {
+		l.levels[subsystem] = new(slog.LevelVar)
+	}
+	return l
+}
+
+// Logger returns the logger of a subsystem, or the default logger for ""
+func (l *Logs) Logger(subsystem string) *slog.Logger {
+	level, ok := l.levels[subsystem]
+	if !ok {
+		level = l.levels[""]
+	}
+	logger := slog.New(slog.NewJSONHandler(l, &slog.HandlerOptions{Level: level}))
+	if subsystem != "" {
+		logger = logger.With("subsystem", subsystem)
+	}
+	return logger
+}
+
+// Config returns the current levels
+func (l *Logs) Config() Config {
+	l.configMu.Lock()
+	defer l.configMu.Unlock()
+
+	return Config{Level: l.config.Level, Subsystems: maps.Clone(l.config.Subsystems)}
+}
+
+// SetConfig changes the levels of every logger. Subsystems without a level of
+// their own follow the default level.
+func (l *Logs) SetConfig(config Config) error {
+	if config.Level == "" {
+		config.Level = "info"
+	}
+	level, err := ParseLevel(config.Level)
+	if err != nil {
+		return err
+	}
+	levels := map[string]slog.Level{"": level}
+	for _, subsystem := range Subsystems {
+		levels[subsystem] = level
+	}
+	for subsystem, name := range config.Subsystems {
+		if !slices.Contains(Subsystems, subsystem) {
+			return fmt.Errorf("unknown subsystem %q, must be one of %s", subsystem, strings.Join(Subsystems, ", "))
+		}
+		if levels[subsystem], err = ParseLevel(name); err != nil {
+			return fmt.Errorf("%s: %w", subsystem, err)
+		}
+	}
+
+	l.configMu.Lock()
+	defer l.configMu.Unlock()
+	for subsystem, level := range levels {
+		l.levels[subsystem].Set(level)
+	}
+	l.config = Config{Level: config.Level}
+	if len(config.Subsystems) > 0 {
+		l.config.Subsystems = maps.Clone(config.Subsystems)
+	}
+	return nil
+}
+
+// Write writes one record to stdout and the file, and sends it to
+// followers. Handlers write each record in a single call.
+func (l *Logs) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stdout != nil {
+		_, _ = l.stdout.Write(p)
+	}
+
+	if l.followers.Following("") {
+		if entry, err := parseEntry(p); err == nil {
+			l.followers.Send("", entry)
+		}
+	}
+
+	// The logs are still on stdout if the file cannot be written, and there
+	// is nowhere to report that it failed
+	if err := l.writeFile(p); err != nil {
+		l.closeFile()
+	}
+	return len(p), nil
+}
+
+func (l *Logs) writeFile(p []byte) error {
+	if l.file != nil && l.size+int64(len(p)) > l.maxSize {
+		l.closeFile()
+		if err := l.files().Rotate(); err != nil {
+			return err
+		}
+	}
+	if l.file == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		l.file, l.size = f, info.Size()
+		if l.size+int64(len(p)) > l.maxSize && l.size > 0 {
+			return l.writeFile(p)
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return err
+}
+
+func (l *Logs) closeFile() {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+// Close closes the file. Records written afterwards reopen it.
+func (l *Logs) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closeFile()
+	return nil
+}
+
+// files returns the file of the logs and its rotated backups
+func (l *Logs) files() ndjson.Files {
+	return ndjson.Files{Path: l.path, MaxBackups: l.maxBackups}
+}
+
+// Query returns the entries selected by filter, oldest first
+func (l *Logs) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.query(filter)
+}
+
+func (l *Logs) query(filter Filter) ([]Entry, error) {
+	return ndjson.Read(l.files(), parseEntry, filter.Matches, filter.Limit)
+}
+
+// parseEntry parses a record written by a JSON handler
+func parseEntry(line []byte) (Entry, error) {
+	var record map[string]any
+	if err := json.Unmarshal(line, &record); err != nil {
+		return Entry{}, err
+	}
+
+	var entry Entry
+	if s, ok := record[slog.TimeKey].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	entry.Level, _ = record[slog.LevelKey].(string)
+	entry.Message, _ = record[slog.MessageKey].(string)
+	entry.Subsystem, _ = record["subsystem"].(string)
+	for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, "subsystem"} {
+		delete(record, key)
+	}
+	if len(record) > 0 {
+		entry.Attrs = record
+	}
+	return entry, nil
+}
+
+// Follow returns the entries selected by filter, like Query, and a channel of
+// those written from then on, until ctx is done. Entries are dropped from the
+// channel if the receiver falls behind.
+func (l *Logs) Follow(ctx context.Context, filter Filter) ([]Entry, <-chan Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.query(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, l.followers.Add(ctx, "", filter.Matches), nil
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevels(t *testing.T) {
+	var stdout bytes.Buffer
+	logs := New(filepath.Join(t.TempDir(), "backend.log"), &stdout)
+	logger := logs.Logger("")
+	manager := logs.Logger(SubsystemManager)
+
+	manager.Debug("hidden")
+	require.NoError(t, logs.SetConfig(Config{Level: "error", Subsystems: map[string]string{"manager": "debug"}}))
+	manager.Debug("shown")
+	logger.Warn("hidden")
+	logger.Error("shown")
+
+	assert.Equal(t, 2, strings.Count(stdout.String(), "\n"))
+	assert.NotContains(t, stdout.String(), "hidden")
+	assert.Contains(t, stdout.String(), `"subsystem":"manager"`)
+
+	assert.Error(t, logs.SetConfig(Config{Level: "info", Subsystems: map[string]string{"docker": "debug"}}))
+	assert.Equal(t, "error", logs.Config().Level, "an invalid config changes nothing")
+}
+
+func TestRotationAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	logs := NewWithRotation(filepath.Join(dir, "backend.log"), 400, 1, nil)
+	logger := logs.Logger(SubsystemStore)
+
+	for i := range 10 {
+		logger.Info("saved", "revision", i)
+	}
+	logger.Warn("malformed")
+	require.NoError(t, logs.Close())
+
+	// Only the current file and one backup are kept
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	entries, err := logs.Query(Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Less(t, len(entries), 11)
+	assert.Equal(t, "malformed", entries[len(entries)-1].Message)
+
+	entries, err = logs.Query(Filter{Level: slog.LevelWarn})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, SubsystemStore, entries[0].Subsystem)
+
+	entries, err = logs.Query(Filter{Subsystem: SubsystemHTTP})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = logs.Query(Filter{Since: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFollow(t *testing.T) {
+	logs := New(filepath.Join(t.TempDir(), "backend.log"), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	history, follow, err := logs.Follow(ctx, Filter{Level: slog.LevelInfo})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	logger := logs.Logger(SubsystemHTTP)
+	require.NoError(t, logs.SetConfig(Config{Level: "debug"}))
+	logger.Debug("polled")
+	logger.Info("request", "status", 201)
+
+	entry := <-follow
+	assert.Equal(t, "request", entry.Message)
+	assert.Equal(t, float64(201), entry.Attrs["status"])
+
+	cancel()
+	for range follow {
+	}
+}
+
+func TestParseSubsystemLevels(t *testing.T) {
+	levels, err := ParseSubsystemLevels("manager=debug, http=warn,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"manager": "debug", "http": "warn"}, levels)
+
+	_, err = ParseSubsystemLevels("manager")
+	assert.Error(t, err)
+}
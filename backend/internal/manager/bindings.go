@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Endpoint bindings
+const (
+	BindingPublic     = "public"
+	BindingInternal   = "internal"
+	BindingKubernetes = "kubernetes"
+)
+
+// internalDomain is the top-level domain of internal endpoint URLs
+const internalDomain = ".internal"
+
+// EndpointBinding validates the binding of an endpoint against its URL and
+// returns it. URLs ending in .internal imply the internal binding, which in
+// turn requires such a URL.
+func EndpointBinding(rawURL, binding string) (string, error) {
+	switch binding {
+	case "", BindingPublic, BindingInternal, BindingKubernetes:
+	default:
+		return "", fmt.Errorf("binding must be public, internal or kubernetes, got %q", binding)
+	}
+
+	// References are only resolved when the endpoint starts
+	if strings.Contains(rawURL, "${") {
+		return binding, nil
+	}
+
+	internalURL := isInternalURL(rawURL)
+	switch {
+	case internalURL && binding == "":
+		return BindingInternal, nil
+	case internalURL && binding != BindingInternal:
+		return "", fmt.Errorf("url %s is internal, so binding must be internal", rawURL)
+	case !internalURL && binding == BindingInternal:
+		return "", fmt.Errorf("internal endpoints require a url ending in %s, e.g. https://api%s", internalDomain, internalDomain)
+	}
+	return binding, nil
+}
+
+// IsInternal reports whether an endpoint is only reachable from other
+// endpoints, through forward-internal
+func IsInternal(config store.EndpointConfig) bool {
+	return config.Binding == BindingInternal || isInternalURL(config.URL)
+}
+
+// isInternalURL reports whether an endpoint URL, with or without a scheme,
+// has a .internal hostname
+func isInternalURL(rawURL string) bool {
+	if rawURL == "" {
+		return false
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Hostname()), internalDomain)
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// How the X-Forwarded-For header reaches the upstream of HTTP endpoints
+const (
+	// ForwardedForAppend adds the client's address to the header sent by
+	// the client, as ngrok does by default
+	ForwardedForAppend = "append"
+	// ForwardedForReplace discards the header sent by the client, which the
+	// client controls, and sets it to the client's address
+	ForwardedForReplace = "replace"
+	// ForwardedForRemove sends no X-Forwarded-For header
+	ForwardedForRemove = "remove"
+)
+
+// HostHeaderRewrite sets the Host header of requests to the address of the
+// upstream
+const HostHeaderRewrite = "rewrite"
+
+// hostHeaderPattern matches host[:port] values of the Host header
+var hostHeaderPattern = regexp.MustCompile(`^[A-Za-z0-9.-]+(:[0-9]+)?$`)
+
+// clientIPExpression is the traffic policy interpolation of the client's
+// address
+const clientIPExpression = "${conn.client_ip}"
+
+// ValidateClientIdentity checks the options controlling how the client's
+// address and the Host header reach the upstream of an HTTP endpoint
+func ValidateClientIdentity(config store.EndpointConfig) error {
+	if config.ForwardedFor == "" && !config.RealIP && config.HostHeader == "" {
+		return nil
+	}
+	if scheme := EndpointScheme(config); scheme != "http" && scheme != "https" {
+		return fmt.Errorf("forwardedFor, realIP and hostHeader require an http or https endpoint, not %s", scheme)
+	}
+
+	switch config.ForwardedFor {
+	case "", ForwardedForAppend, ForwardedForReplace, ForwardedForRemove:
+	default:
+		return fmt.Errorf("forwardedFor must be append, replace or remove, got %q", config.ForwardedFor)
+	}
+
+	switch {
+	case config.HostHeader == "":
+	case config.HostHeader == HostHeaderRewrite:
+		if len(config.Router) > 0 {
+			return errors.New("hostHeader rewrite requires an endpoint with an upstream; routers may set a host instead")
+		}
+	case !hostHeaderPattern.MatchString(config.HostHeader):
+		return fmt.Errorf("hostHeader must be rewrite or a host such as example.com:8080, got %q", config.HostHeader)
+	}
+	return nil
+}
+
+// clientIdentityRules returns the rules setting the client identity headers
+// of an endpoint. upstreamAddress is the host:port requests are forwarded to.
+func clientIdentityRules(config store.EndpointConfig, upstreamAddress string) []any {
+	var actions []any
+	headers := make(map[string]any)
+
+	switch config.ForwardedFor {
+	case ForwardedForReplace:
+		actions = append(actions, removeHeaders("x-forwarded-for"))
+		headers["x-forwarded-for"] = clientIPExpression
+	case ForwardedForRemove:
+		actions = append(actions, removeHeaders("x-forwarded-for"))
+	}
+	if config.RealIP {
+		actions = append(actions, removeHeaders("x-real-ip"))
+		headers["x-real-ip"] = clientIPExpression
+	}
+	switch config.HostHeader {
+	case "":
+	case HostHeaderRewrite:
+		headers["host"] = upstreamAddress
+	default:
+		headers["host"] = config.HostHeader
+	}
+	if len(headers) > 0 {
+		actions = append(actions, map[string]any{
+			"type":   "add-headers",
+			"config": map[string]any{"headers": headers},
+		})
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+	return []any{map[string]any{"name": "client identity headers", "actions": actions}}
+}
+
+// removeHeaders returns a remove-headers action
+func removeHeaders(headers ...string) map[string]any {
+	return map[string]any{
+		"type":   "remove-headers",
+		"config": map[string]any{"headers": headers},
+	}
+}
+
+// upstreamAddress returns the host:port an endpoint forwards requests to
+func upstreamAddress(config store.EndpointConfig) string {
+	if config.Upstream != "" {
+		if u, err := ParseUpstream(config.Upstream); err == nil {
+			return u.Host
+		}
+	}
+	return net.JoinHostPort(DockerBridgeHost, config.TargetPort)
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestClientIdentityRules(t *testing.T) {
+	config := store.EndpointConfig{
+		ContainerID:  "web",
+		TargetPort:   "8080",
+		ForwardedFor: ForwardedForReplace,
+		RealIP:       true,
+		HostHeader:   HostHeaderRewrite,
+	}
+
+	rules := clientIdentityRules(config, upstreamAddress(config))
+	require.Len(t, rules, 1)
+	assert.Equal(t, []any{
+		removeHeaders("x-forwarded-for"),
+		removeHeaders("x-real-ip"),
+		map[string]any{
+			"type": "add-headers",
+			"config": map[string]any{"headers": map[string]any{
+				"x-forwarded-for": "${conn.client_ip}",
+				"x-real-ip":       "${conn.client_ip}",
+				"host":            "172.17.0.1:8080",
+			}},
+		},
+	}, rules[0].(map[string]any)["actions"])
+
+	// ngrok's defaults need no rules
+	assert.Nil(t, clientIdentityRules(store.EndpointConfig{ForwardedFor: ForwardedForAppend}, ""))
+}
+
+func TestValidateClientIdentity(t *testing.T) {
+	require.NoError(t, ValidateClientIdentity(store.EndpointConfig{URL: "https://web.example.com", HostHeader: "example.com:8080", ForwardedFor: ForwardedForRemove}))
+
+	tests := []struct {
+		name   string
+		config store.EndpointConfig
+		err    string
+	}{
+		{"tcp endpoint", store.EndpointConfig{URL: "tcp://1.tcp.ngrok.io:12345", RealIP: true}, "require an http or https endpoint, not tcp"},
+		{"bad forwardedFor", store.EndpointConfig{ForwardedFor: "keep"}, `forwardedFor must be append, replace or remove, got "keep"`},
+		{"bad host", store.EndpointConfig{HostHeader: "example.com/path"}, "hostHeader must be rewrite or a host"},
+		{"router rewrite", store.EndpointConfig{URL: "https://example.com", HostHeader: HostHeaderRewrite, Router: []store.RouterRule{{Endpoint: "api:80"}}}, "hostHeader rewrite requires an endpoint with an upstream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClientIdentity(tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
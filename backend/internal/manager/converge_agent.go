@@ -2,14 +2,20 @@ package manager
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	ngrok "golang.ngrok.com/ngrok/v2"
 
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
 )
 
-func (m *manager) convergeAgent(ctx context.Context, config store.AgentConfig) error {
+func (m *manager) convergeAgent(ctx context.Context, config store.AgentConfig) (err error) {
+	ctx, end := startPhase(ctx, "agent")
+	defer end(&err)
 	if config.ExpectedState == AgentStateOffline {
 		m.handleAgentOfflineState()
 	}
@@ -92,10 +98,12 @@ func (m *manager) connectAgent(ctx context.Context) error {
 	// We connect the agent async so that we're not blocking convergence /
 	// holding a lock while we do it.
 	ch := make(chan error)
-	agent := m.agent
+	agent, agentCtx := m.agent, m.agentCtx
+	connectCtx, span := tracer.Start(ctx, "agent.connect")
 	go func() {
 		defer close(ch)
-		err := agent.Connect(m.agentCtx)
+		err := agent.Connect(agentCtx)
+		agentConnects.Add(connectCtx, 1, metric.WithAttributes(attribute.String("outcome", endSpan(span, err))))
 		if err != nil {
 			m.setAgentOffline(err)
 		} else {
@@ -132,7 +140,10 @@ func (m *manager) disconnectAgent() {
 	//
 	// we clear out their state so that the convergence loop will
 	// start them again.
-	for id := range m.endpointStatus {
+	m.endpointMu.RLock()
+	ids := slices.Collect(maps.Keys(m.endpointStatus))
+	m.endpointMu.RUnlock()
+	for _, id := range ids {
 		m.handleEndpointOfflineState(id)
 	}
 }
@@ -188,6 +199,7 @@ func (m *manager) setAgentConnecting(err error) {
 	m.agentStatus = AgentStatus{
 		State:       AgentStateConnecting,
 		LastError:   lastError,
+		Error:       ClassifyError(err),
 		Latency:     0,
 		ConnectedAt: time.Time{},
 	}
@@ -219,6 +231,7 @@ func (m *manager) setAgentOffline(err error) {
 		State:       AgentStateOffline,
 		ConnectedAt: time.Time{},
 		LastError:   lastError,
+		Error:       ClassifyError(err),
 		Latency:     0,
 	}
 }
@@ -6,20 +6,39 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/url"
+	"slices"
 	"time"
 
 	ngrok "golang.ngrok.com/ngrok/v2"
 
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
 	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
+	"github.com/ngrok/ngrok-docker-extension/internal/secrets"
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
 )
 
-func (m *manager) convergeEndpoints(ctx context.Context, endpointConfigs map[string]store.EndpointConfig) error {
+func (m *manager) convergeEndpoints(ctx context.Context, endpointConfigs map[string]store.EndpointConfig) (err error) {
+	ctx, end := startPhase(ctx, "endpoints")
+	defer end(&err)
 	// Handle endpoints in the desired configuration
 	for endpointID, config := range endpointConfigs {
 		switch config.ExpectedState {
 		case EndpointStateOnline:
+			// Routers forward to the URLs of other endpoints, so a change to
+			// those recreates the router
+			if len(config.Router) > 0 {
+				policy, err := routerPolicy(config, endpointConfigs)
+				if err != nil {
+					m.closeEndpointForwarders(endpointID)
+					delete(m.endpointConfigs, endpointID)
+					m.setEndpointFailed(endpointID, err)
+					continue
+				}
+				config.TrafficPolicy = policy
+			}
 			if err := m.handleEndpointOnlineState(ctx, endpointID, config); err != nil {
 				return err
 			}
@@ -27,8 +46,8 @@ func (m *manager) convergeEndpoints(ctx context.Context, endpointConfigs map[str
 			m.handleEndpointOfflineState(endpointID)
 		}
 	}
-	// Handle running endpoints not in desired config (remove them)
-	for endpointID := range m.endpointForwarders {
+	// Handle running or starting endpoints not in desired config (remove them)
+	for _, endpointID := range m.activeEndpoints() {
 		if _, exists := endpointConfigs[endpointID]; !exists {
 			m.handleEndpointOfflineState(endpointID)
 		}
@@ -38,79 +57,142 @@ func (m *manager) convergeEndpoints(ctx context.Context, endpointConfigs map[str
 
 // handleEndpointOnlineState manages creating/updating endpoints for online state
 func (m *manager) handleEndpointOnlineState(ctx context.Context, endpointID string, config store.EndpointConfig) error {
-	_, forwarderExists := m.endpointForwarders[endpointID]
-	configChanged := m.endpointConfigChanged(endpointID, config)
+	configHash := m.computeConfigHash(config)
+
+	// Leave a running operation alone unless the config changed or it ran
+	// past its deadline
+	if op := m.endpointOp(endpointID); op != nil {
+		switch {
+		case op.configHash != configHash:
+			m.abandonEndpointOp(endpointID)
+		case time.Now().After(op.Deadline):
+			m.timeOutEndpointOp(op)
+			return nil
+		default:
+			return nil
+		}
+	}
 
-	// Create/recreate endpoint if needed
-	if !forwarderExists || configChanged {
-		err := m.createOrUpdateEndpoint(ctx, endpointID, config, forwarderExists, configChanged)
-		return err
+	// Create/recreate endpoint if needed, unless its start timed out recently
+	_, forwarderExists := m.endpointForwarders[endpointID]
+	if !forwarderExists || m.endpointConfigs[endpointID] != configHash {
+		if m.backingOff(endpointID, configHash) {
+			return nil
+		}
+		return m.createOrUpdateEndpoint(ctx, endpointID, config, configHash)
 	}
+
+	// Retry the routes that failed to start
+	m.ensureRoutes(ctx, endpointID, config, configHash)
 	return nil
 }
 
 // handleEndpointOfflineState manages removing endpoints for offline state
 func (m *manager) handleEndpointOfflineState(endpointID string) {
+	m.closeEndpointForwarders(endpointID)
+	delete(m.endpointConfigs, endpointID)
+	delete(m.endpointRetries, endpointID)
+	m.setEndpointOffline(endpointID)
+}
+
+// closeEndpointForwarders abandons the running operation of an endpoint and
+// closes its forwarder and those of its routes
+func (m *manager) closeEndpointForwarders(endpointID string) {
+	m.abandonEndpointOp(endpointID)
 	if forwarder, exists := m.endpointForwarders[endpointID]; exists {
 		forwarder.Close()
 		delete(m.endpointForwarders, endpointID)
 	}
-	delete(m.endpointConfigs, endpointID)
-	m.setEndpointOffline(endpointID)
+	for _, forwarder := range m.routeForwarders[endpointID] {
+		if forwarder != nil {
+			forwarder.Close()
+		}
+	}
+	delete(m.routeForwarders, endpointID)
 }
 
-// endpointConfigChanged checks if endpoint configuration has changed
-func (m *manager) endpointConfigChanged(endpointID string, config store.EndpointConfig) bool {
-	currentConfigHash := m.computeConfigHash(config)
-	lastConfigHash, configExists := m.endpointConfigs[endpointID]
-	return !configExists || lastConfigHash != currentConfigHash
+// activeEndpoints returns the endpoints with forwarders or a running
+// operation
+func (m *manager) activeEndpoints() []string {
+	ids := slices.Collect(maps.Keys(m.endpointForwarders))
+	ids = slices.AppendSeq(ids, maps.Keys(m.routeForwarders))
+	m.opsMu.Lock()
+	ids = slices.AppendSeq(ids, maps.Keys(m.endpointOps))
+	m.opsMu.Unlock()
+	slices.Sort(ids)
+	return slices.Compact(ids)
 }
 
 // createOrUpdateEndpoint handles the creation or recreation of an endpoint
-func (m *manager) createOrUpdateEndpoint(ctx context.Context, endpointID string, config store.EndpointConfig, forwarderExists, configChanged bool) error {
-	// Close existing forwarder if config changed
-	if forwarderExists && configChanged {
-		if forwarder, exists := m.endpointForwarders[endpointID]; exists {
-			forwarder.Close()
-			delete(m.endpointForwarders, endpointID)
-		}
-	}
+func (m *manager) createOrUpdateEndpoint(ctx context.Context, endpointID string, config store.EndpointConfig, configHash string) error {
+	// Close existing forwarders, including the routes left running by an
+	// endpoint that failed to start
+	m.closeEndpointForwarders(endpointID)
 
 	if m.agent == nil {
-		m.setEndpointStarting(endpointID, "waiting for connection to ngrok cloud")
+		m.setEndpointStarting(endpointID, newStatusError(ErrorCodeAgentNotConnected, "waiting for connection to ngrok cloud"))
 		return nil
 	}
 
-	m.setEndpointStarting(endpointID, "")
+	m.setEndpointStarting(endpointID, nil)
+	m.setRoutesStarting(endpointID, config)
 
-	ch := make(chan struct{})
-	go func() {
-		defer close(ch)
-		// Create the forwarder
-		forwarder, err := m.createEndpointForwarder(ctx, config)
+	m.routeForwarders[endpointID] = make([]ngrok.EndpointForwarder, len(config.Routes))
+	m.startEndpointOp(ctx, OperationStart, config, configHash, allRoutes(config))
+	return nil
+}
+
+// allRoutes returns the indexes of every route of an endpoint
+func allRoutes(config store.EndpointConfig) []int {
+	routes := make([]int, len(config.Routes))
+	for i := range routes {
+		routes[i] = i
+	}
+	return routes
+}
+
+// createEndpointForwarder creates a new endpoint forwarder
+func (m *manager) createEndpointForwarder(ctx context.Context, op *endpointOp) (ngrok.EndpointForwarder, error) {
+	config, err := m.resolveConfig(op.config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Routes are served by internal endpoints the policy forwards to
+	if len(config.Routes) > 0 {
+		policy, err := routingPolicy(config.TrafficPolicy, config)
 		if err != nil {
-			m.setEndpointFailed(endpointID, fmt.Sprintf("failed to create endpoint: %v", err))
-			return
+			return nil, err
 		}
+		config.TrafficPolicy = policy
+	}
 
-		// Store forwarder and config hash
-		m.endpointForwarders[endpointID] = forwarder
-		m.endpointConfigs[endpointID] = m.computeConfigHash(config)
+	// Client identity headers are set before the policy's own rules, which
+	// may override them
+	if rules := clientIdentityRules(config, upstreamAddress(config)); rules != nil {
+		policy, err := addPolicyRules(config.TrafficPolicy, "on_http_request", rules, nil)
+		if err != nil {
+			return nil, err
+		}
+		config.TrafficPolicy = policy
+	}
 
-		// Set endpoint status
-		m.setEndpointOnline(endpointID, forwarder)
-	}()
-	select {
-	case <-ch:
-	case <-time.After(time.Second):
+	// TLS endpoints terminating at the edge decrypt before forwarding
+	if config.TLSTermination == TLSTerminationEdge {
+		policy, err := terminationPolicy(config.TrafficPolicy)
+		if err != nil {
+			return nil, err
+		}
+		config.TrafficPolicy = policy
 	}
-	return nil
-}
 
-// createEndpointForwarder creates a new endpoint forwarder
-func (m *manager) createEndpointForwarder(ctx context.Context, config store.EndpointConfig) (ngrok.EndpointForwarder, error) {
 	// Create upstream and options
-	upstream := m.buildUpstream(ctx, config)
+	var upstream *ngrok.Upstream
+	if len(config.Router) > 0 {
+		upstream = ngrok.WithUpstream(routerUpstream)
+	} else {
+		upstream = m.buildUpstream(ctx, config)
+	}
 	var opts []ngrok.EndpointOption
 	if config.URL != "" {
 		opts = append(opts, ngrok.WithURL(config.URL))
@@ -130,22 +212,119 @@ func (m *manager) createEndpointForwarder(ctx context.Context, config store.Endp
 	opts = append(opts, ngrok.WithPoolingEnabled(config.PoolingEnabled))
 
 	// Create the forwarder using agent context
-	return m.agent.Forward(m.agentCtx, upstream, opts...)
+	return m.forward(ctx, op, upstream, opts...)
 }
 
+// ensureRoutes starts the internal endpoints of the routes of an endpoint
+// that are not running
+func (m *manager) ensureRoutes(ctx context.Context, endpointID string, config store.EndpointConfig, configHash string) {
+	forwarders := m.routeForwarders[endpointID]
+	var missing []int
+	for i := range config.Routes {
+		if i < len(forwarders) && forwarders[i] == nil {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) > 0 {
+		m.startEndpointOp(ctx, OperationRoutes, config, configHash, missing)
+	}
+}
+
+// createRouteForwarder creates the internal endpoint serving a route of op's
+// endpoint, which forwards to the route's port of the endpoint's container
+func (m *manager) createRouteForwarder(ctx context.Context, op *endpointOp, route store.Route) (ngrok.EndpointForwarder, error) {
+	// Use the resolved config, as createEndpointForwarder does, so that the
+	// route's URL is the one the routing policy forwards to
+	config, err := m.resolveConfig(op.config)
+	if err != nil {
+		return nil, err
+	}
+	upstreamConfig := store.EndpointConfig{
+		ID:          op.config.ID,
+		ContainerID: op.config.ContainerID,
+		TargetPort:  route.TargetPort,
+	}
+	return m.forward(ctx, op, m.buildUpstream(ctx, upstreamConfig),
+		ngrok.WithURL(RouteURL(config, route)),
+		ngrok.WithDescription(fmt.Sprintf("route %s of %s", route.Path, op.config.ID)),
+	)
+}
+
+// resolveConfig expands secret and environment references in the fields that
+// support them. Resolved values must never be persisted or returned by the API.
+func (m *manager) resolveConfig(config store.EndpointConfig) (store.EndpointConfig, error) {
+	if m.Secrets == nil {
+		return config, nil
+	}
+	for _, field := range []*string{&config.URL, &config.TrafficPolicy, &config.Metadata} {
+		resolved, err := m.Secrets.Resolve(*field)
+		if err != nil {
+			return config, newStatusError(ErrorCodeSecretUnresolved, err.Error())
+		}
+		*field = resolved
+	}
+	return config, nil
+}
+
+// ValidateSecretReferences checks the references in the fields resolveConfig
+// expands: environment variables must carry secrets.EnvPrefix
+func ValidateSecretReferences(config store.EndpointConfig) error {
+	for _, field := range []string{config.URL, config.TrafficPolicy, config.Metadata} {
+		if err := secrets.CheckReferences(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateEndpoint runs every check an endpoint config must pass before it
+// is stored, whether it comes from the API or from a GitOps config file
+func ValidateEndpoint(config store.EndpointConfig) error {
+	for _, validate := range []func(store.EndpointConfig) error{
+		ValidateTransport,
+		ValidateClientIdentity,
+		ValidateRoutes,
+		ValidateRouter,
+		ValidateSecretReferences,
+	} {
+		if err := validate(config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DockerBridgeHost is docker's bridge gateway IP, where the published ports of
+// containers are reachable from the extension
+const DockerBridgeHost = "172.17.0.1"
+
 // buildUpstream constructs the upstream URL for connecting to the container
-// Uses protocol detection to determine if TLS schemes should be applied
+// Uses protocol detection to determine if TLS schemes should be applied.
+// Endpoints with an upstream URL use it as is.
 func (m *manager) buildUpstream(ctx context.Context, config store.EndpointConfig) *ngrok.Upstream {
-	host := "172.17.0.1"
+	// Upstream URLs name their scheme, so there is nothing to detect
+	if config.Upstream != "" {
+		var upstreamScheme string
+		if u, err := ParseUpstream(config.Upstream); err == nil {
+			upstreamScheme = u.Scheme
+		}
+		opts := append(upstreamProxyProto(config), m.upstreamDialer(config, upstreamScheme)...)
+		return ngrok.WithUpstream(config.Upstream, append(opts,
+			ngrok.WithUpstreamTLSClientConfig(&tls.Config{InsecureSkipVerify: true}),
+		)...)
+	}
+
+	host := DockerBridgeHost
 	port := config.TargetPort
 
 	// Detect protocols on the target port
-	detectCtx, cancel := context.WithTimeout(ctx, time.Second)
-	defer cancel()
-
-	result, err := m.ProtocolDetector.Detect(detectCtx, host, port)
-	if err != nil {
+	result, err := m.ProtocolDetector.DetectContainer(ctx, config.ContainerID, host, port)
+	switch {
+	case err != nil:
+		m.Logger.Warn("Protocol detection failed, assuming a plain upstream", "endpoint", config.ID, "error", err)
 		result = &detectproto.Result{}
+	case !result.TCP:
+		m.Logger.Warn("Upstream is unreachable, assuming a plain upstream", "endpoint", config.ID, "error", result.Error)
 	}
 
 	// Parse the endpoint URL to get its scheme
@@ -157,7 +336,7 @@ func (m *manager) buildUpstream(ctx context.Context, config store.EndpointConfig
 	}
 
 	var upstreamScheme string
-	var opts []ngrok.UpstreamOption
+	opts := upstreamProxyProto(config)
 
 	// Apply TLS-based upstream scheme logic
 	// If TLS is detected and endpoint scheme is http/https (or no scheme), use https:// upstream
@@ -168,6 +347,15 @@ func (m *manager) buildUpstream(ctx context.Context, config store.EndpointConfig
 		} else {
 			upstreamScheme = "http"
 		}
+	case "tls":
+		// Passed through connections are still encrypted by the client and
+		// must not be wrapped in TLS again. Connections decrypted at the edge
+		// are encrypted again for upstreams that speak TLS.
+		if config.TLSTermination == TLSTerminationEdge && result.TLS {
+			upstreamScheme = "tls"
+		} else {
+			upstreamScheme = "tcp"
+		}
 	default:
 		upstreamScheme = endpointScheme
 	}
@@ -176,6 +364,7 @@ func (m *manager) buildUpstream(ctx context.Context, config store.EndpointConfig
 	// connection is only transiting over docker's host-local interface. we can
 	// add user-configuration for this in the future
 	opts = append(opts, ngrok.WithUpstreamTLSClientConfig(&tls.Config{InsecureSkipVerify: true}))
+	opts = append(opts, m.upstreamDialer(config, upstreamScheme)...)
 
 	return ngrok.WithUpstream(
 		fmt.Sprintf("%s://%s:%s", upstreamScheme, host, port),
@@ -183,6 +372,28 @@ func (m *manager) buildUpstream(ctx context.Context, config store.EndpointConfig
 	)
 }
 
+// upstreamDialer returns the upstream option dialing an endpoint's upstream
+// through the access log, recording its connections, and through the
+// propagator when trace propagation is enabled, continuing the trace of each
+// request. Requests are only parsed from plain HTTP upstreams: the SDK
+// encrypts the others after dialing.
+func (m *manager) upstreamDialer(config store.EndpointConfig, upstreamScheme string) []ngrok.UpstreamOption {
+	var dialer telemetry.Dialer
+	if m.AccessLog != nil {
+		dialer = m.AccessLog.Dialer(config.ID, accesslog.Tap{
+			HTTP:          upstreamScheme == "http",
+			ProxyProtocol: config.ProxyProtocol != "" && upstreamScheme == "tcp",
+		})
+	}
+	if m.Propagator != nil && upstreamScheme == "http" && config.ProxyProtocol == "" {
+		dialer = m.Propagator.Dialer(config.ID, dialer)
+	}
+	if dialer == nil {
+		return nil
+	}
+	return []ngrok.UpstreamOption{ngrok.WithUpstreamDialer(dialer)}
+}
+
 // setEndpointOffline sets an endpoint status to offline with optional error
 func (m *manager) setEndpointOffline(endpointID string) {
 	m.endpointMu.Lock()
@@ -191,18 +402,23 @@ func (m *manager) setEndpointOffline(endpointID string) {
 	m.endpointStatus[endpointID] = EndpointStatus{
 		State: EndpointStateOffline,
 	}
+	delete(m.routeStatus, endpointID)
 }
 
 // setEndpointStarting sets an endpoint status that indicates it's trying to
-// start
-func (m *manager) setEndpointStarting(endpointID string, lastError string) {
+// start, with an optional reason it has not started yet
+func (m *manager) setEndpointStarting(endpointID string, reason *StatusError) {
 	m.endpointMu.Lock()
 	defer m.endpointMu.Unlock()
 
-	m.endpointStatus[endpointID] = EndpointStatus{
-		State:     EndpointStateStarting,
-		LastError: lastError,
+	status := EndpointStatus{
+		State: EndpointStateStarting,
+	}
+	if reason != nil {
+		status.LastError = reason.Message
+		status.Error = reason
 	}
+	m.endpointStatus[endpointID] = status
 }
 
 func (m *manager) setEndpointsAgentDisconnected() {
@@ -214,6 +430,7 @@ func (m *manager) setEndpointsAgentDisconnected() {
 			m.endpointStatus[id] = EndpointStatus{
 				State:     EndpointStateStarting,
 				LastError: "agent disconnected",
+				Error:     newStatusError(ErrorCodeAgentDisconnected, "agent disconnected"),
 				URL:       status.URL,
 			}
 		}
@@ -225,7 +442,7 @@ func (m *manager) setEndpointsAgentConnected() {
 	defer m.endpointMu.Unlock()
 
 	for id, status := range m.endpointStatus {
-		if status.State == EndpointStateStarting && status.LastError == "agent disconnected" {
+		if status.State == EndpointStateStarting && status.Error != nil && status.Error.Code == ErrorCodeAgentDisconnected {
 			m.endpointStatus[id] = EndpointStatus{
 				State: EndpointStateOnline,
 				URL:   status.URL,
@@ -236,13 +453,14 @@ func (m *manager) setEndpointsAgentConnected() {
 
 // setEndpointFailed sets an endpoint status that indicates the call to
 // Forward() failed
-func (m *manager) setEndpointFailed(endpointID string, lastError string) {
+func (m *manager) setEndpointFailed(endpointID string, err error) {
 	m.endpointMu.Lock()
 	defer m.endpointMu.Unlock()
 
 	m.endpointStatus[endpointID] = EndpointStatus{
 		State:     EndpointStateFailed,
-		LastError: lastError,
+		LastError: err.Error(),
+		Error:     ClassifyError(err),
 	}
 }
 
@@ -259,8 +477,14 @@ func (m *manager) setEndpointOnline(endpointID string, forwarder ngrok.EndpointF
 	m.endpointStatus[endpointID] = status
 }
 
-// computeConfigHash computes a hash of endpoint configuration for change detection
+// computeConfigHash computes a hash of endpoint configuration for change
+// detection. References are resolved first so that changing a secret
+// recreates the endpoints that use it.
 func (m *manager) computeConfigHash(config store.EndpointConfig) string {
+	if resolved, err := m.resolveConfig(config); err == nil {
+		config = resolved
+	}
+
 	// Only hash fields that affect the ngrok endpoint
 	configData := map[string]interface{}{
 		"url":            config.URL,
@@ -270,9 +494,55 @@ func (m *manager) computeConfigHash(config store.EndpointConfig) string {
 		"metadata":       config.Metadata,
 		"description":    config.Description,
 		"targetPort":     config.TargetPort,
+		"upstream":       config.Upstream,
+		"routes":         config.Routes,
+		"proxyProtocol":  config.ProxyProtocol,
+		"tlsTermination": config.TLSTermination,
+		"forwardedFor":   config.ForwardedFor,
+		"realIP":         config.RealIP,
+		"hostHeader":     config.HostHeader,
 	}
 
 	data, _ := json.Marshal(configData)
 	hash := sha256.Sum256(data)
 	return fmt.Sprintf("%x", hash)
 }
+
+// setRoutesStarting resets the status of the routes of an endpoint
+func (m *manager) setRoutesStarting(endpointID string, config store.EndpointConfig) {
+	m.endpointMu.Lock()
+	defer m.endpointMu.Unlock()
+
+	if len(config.Routes) == 0 {
+		delete(m.routeStatus, endpointID)
+		return
+	}
+	routes := make([]RouteStatus, len(config.Routes))
+	for i, route := range config.Routes {
+		routes[i] = RouteStatus{
+			Path:       route.Path,
+			TargetPort: route.TargetPort,
+			URL:        RouteURL(config, route),
+			State:      EndpointStateStarting,
+		}
+	}
+	m.routeStatus[endpointID] = routes
+}
+
+// setRouteStatus updates the state of one route of an endpoint, keeping its
+// path, port and URL
+func (m *manager) setRouteStatus(endpointID string, i int, status RouteStatus) {
+	m.endpointMu.Lock()
+	defer m.endpointMu.Unlock()
+
+	routes := m.routeStatus[endpointID]
+	if i >= len(routes) {
+		return
+	}
+	status.Path = routes[i].Path
+	status.TargetPort = routes[i].TargetPort
+	if status.URL == "" {
+		status.URL = routes[i].URL
+	}
+	routes[i] = status
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/ngrok/ngrok-docker-extension/internal/detectproto"
 	"golang.ngrok.com/ngrok/v2"
 )
@@ -18,9 +19,25 @@ type NgrokSDK interface {
 // DockerClient wraps Docker client functionality
 type DockerClient interface {
 	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
+	// ContainerExec runs cmd in a running container and returns its standard
+	// output. A command that exits non-zero returns its output along with an
+	// *ExecExitError.
+	ContainerExec(ctx context.Context, containerID string, cmd []string) ([]byte, error)
+	Ping(ctx context.Context) (types.Ping, error)
 }
 
 // ProtocolDetector wraps protocol detection functionality
 type ProtocolDetector interface {
+	// Detect probes a port
 	Detect(ctx context.Context, host, port string) (*detectproto.Result, error)
+	// DetectContainer probes a port of a container, reusing recent results
+	// for as long as the container keeps running
+	DetectContainer(ctx context.Context, containerID, host, port string) (*detectproto.Result, error)
+}
+
+// SecretResolver expands ${secret:name} and ${env:NAME} references in
+// endpoint configuration
+type SecretResolver interface {
+	Resolve(s string) (string, error)
 }
@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"syscall"
+
+	ngrok "golang.ngrok.com/ngrok/v2"
+)
+
+// ErrorCode is a machine-readable classification of a status error
+type ErrorCode string
+
+const (
+	ErrorCodeAuthFailed          ErrorCode = "auth_failed"
+	ErrorCodeURLInUse            ErrorCode = "url_in_use"
+	ErrorCodePolicyInvalid       ErrorCode = "policy_invalid"
+	ErrorCodeUpstreamUnreachable ErrorCode = "upstream_unreachable"
+	ErrorCodeQuotaExceeded       ErrorCode = "quota_exceeded"
+	ErrorCodeNetworkDown         ErrorCode = "network_down"
+	ErrorCodeSecretUnresolved    ErrorCode = "secret_unresolved"
+	ErrorCodeEndpointMissing     ErrorCode = "endpoint_missing"
+	ErrorCodeOperationTimeout    ErrorCode = "operation_timeout"
+
+	// ErrorCodeAgentDisconnected marks online endpoints whose agent lost its
+	// connection and is reconnecting
+	ErrorCodeAgentDisconnected ErrorCode = "agent_disconnected"
+	// ErrorCodeAgentNotConnected marks endpoints waiting for the agent to
+	// connect for the first time
+	ErrorCodeAgentNotConnected ErrorCode = "agent_not_connected"
+	ErrorCodeUnknown           ErrorCode = "unknown"
+)
+
+// remediations are the hints shown to users for each error code
+var remediations = map[ErrorCode]string{
+	ErrorCodeAuthFailed:          "Check that your authtoken is correct and has not been revoked at https://dashboard.ngrok.com/get-started/your-authtoken.",
+	ErrorCodeURLInUse:            "Another agent or endpoint is already serving this URL. Stop it, choose a different URL, or enable pooling.",
+	ErrorCodePolicyInvalid:       "Fix the traffic policy; see https://ngrok.com/docs/traffic-policy/ for the supported syntax.",
+	ErrorCodeUpstreamUnreachable: "Make sure the container or upstream service is running and listening on the target port.",
+	ErrorCodeQuotaExceeded:       "Your ngrok plan limit has been reached. Stop other agents or endpoints, or upgrade your plan.",
+	ErrorCodeNetworkDown:         "Check your network connection and any proxy or firewall between Docker Desktop and ngrok.",
+	ErrorCodeSecretUnresolved:    "Create the missing secret with PUT /secrets/{name}, or set the environment variable on the extension container.",
+	ErrorCodeEndpointMissing:     "Create the internal endpoint the router forwards to, or remove the rule referring to it.",
+	ErrorCodeOperationTimeout:    "Starting the endpoint took too long and was abandoned; it is retried automatically. Check your network connection to ngrok.",
+	ErrorCodeAgentDisconnected:   "The agent is reconnecting to ngrok; the endpoint will come back online automatically.",
+	ErrorCodeAgentNotConnected:   "Start the agent, or check its status for connection errors.",
+}
+
+// ngrokErrorCodes maps ngrok cloud error codes to the error taxonomy
+var ngrokErrorCodes = map[string]ErrorCode{
+	"ERR_NGROK_105":  ErrorCodeAuthFailed,
+	"ERR_NGROK_106":  ErrorCodeAuthFailed,
+	"ERR_NGROK_107":  ErrorCodeAuthFailed,
+	"ERR_NGROK_4018": ErrorCodeAuthFailed,
+	"ERR_NGROK_334":  ErrorCodeURLInUse,
+	"ERR_NGROK_108":  ErrorCodeQuotaExceeded,
+	"ERR_NGROK_324":  ErrorCodeQuotaExceeded,
+	"ERR_NGROK_8012": ErrorCodeUpstreamUnreachable,
+}
+
+// ngrokCodePattern finds an ngrok error code in a message whose error type
+// was lost, e.g. after being flattened with fmt.Errorf("%v")
+var ngrokCodePattern = regexp.MustCompile(`\bERR_NGROK_\d+\b`)
+
+// messagePhrases are the fallback phrases for errors without a known code,
+// checked in order. They are whole phrases rather than single words so that
+// e.g. "rate limit" or "policy" in an unrelated message does not match.
+var messagePhrases = []struct {
+	phrase string
+	code   ErrorCode
+}{
+	{"authtoken you specified is invalid", ErrorCodeAuthFailed},
+	{"invalid authtoken", ErrorCodeAuthFailed},
+	{"authentication failed", ErrorCodeAuthFailed},
+	{"already online", ErrorCodeURLInUse},
+	{"already in use", ErrorCodeURLInUse},
+	{"already bound", ErrorCodeURLInUse},
+	{"invalid traffic policy", ErrorCodePolicyInvalid},
+	{"traffic policy is invalid", ErrorCodePolicyInvalid},
+	{"failed to parse traffic policy", ErrorCodePolicyInvalid},
+	{"insufficient quota", ErrorCodeQuotaExceeded},
+	{"quota exceeded", ErrorCodeQuotaExceeded},
+	{"limit exceeded", ErrorCodeQuotaExceeded},
+	{"limit reached", ErrorCodeQuotaExceeded},
+}
+
+// StatusError is a classified error reported in agent and endpoint status
+type StatusError struct {
+	Code        ErrorCode `json:"code"`
+	Message     string    `json:"message"`               // original error message
+	Remediation string    `json:"remediation,omitempty"` // hint on how to fix it
+	NgrokCode   string    `json:"ngrokCode,omitempty"`   // e.g. "ERR_NGROK_105"
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// newStatusError creates a status error with the remediation for code
+func newStatusError(code ErrorCode, message string) *StatusError {
+	return &StatusError{
+		Code:        code,
+		Message:     message,
+		Remediation: remediations[code],
+	}
+}
+
+// ClassifyError maps err, typically returned by the ngrok SDK, to a
+// StatusError. It returns nil for a nil error.
+func ClassifyError(err error) *StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+
+	// Errors from the ngrok cloud carry a code
+	var ngrokErr ngrok.Error
+	if errors.As(err, &ngrokErr) {
+		code, known := ngrokErrorCodes[ngrokErr.Code()]
+		if !known {
+			code = classifyMessage(ngrokErr.Error())
+		}
+		e := newStatusError(code, err.Error())
+		e.NgrokCode = ngrokErr.Code()
+		return e
+	}
+
+	if ngrokCode := ngrokCodePattern.FindString(err.Error()); ngrokCode != "" {
+		if code, known := ngrokErrorCodes[ngrokCode]; known {
+			e := newStatusError(code, err.Error())
+			e.NgrokCode = ngrokCode
+			return e
+		}
+	}
+
+	return newStatusError(classifyLocal(err), err.Error())
+}
+
+// classifyLocal classifies errors that did not come from the ngrok cloud
+func classifyLocal(err error) ErrorCode {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr),
+		errors.Is(err, syscall.ENETUNREACH),
+		errors.Is(err, syscall.EHOSTUNREACH),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCodeNetworkDown
+	}
+	return classifyMessage(err.Error())
+}
+
+// classifyMessage is the fallback for errors without a known code
+func classifyMessage(message string) ErrorCode {
+	message = strings.ToLower(message)
+	for _, p := range messagePhrases {
+		if strings.Contains(message, p.phrase) {
+			return p.code
+		}
+	}
+	return ErrorCodeUnknown
+}
+
+// ExecExitError is returned by DockerClient.ContainerExec when the command
+// exits with a non-zero code
+type ExecExitError struct {
+	ExitCode int
+	Stderr   string
+}
+
+func (e *ExecExitError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("command exited with code %d", e.ExitCode)
+	}
+	return fmt.Sprintf("command exited with code %d: %s", e.ExitCode, strings.TrimSpace(e.Stderr))
+}
@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloudError implements ngrok.Error
+type cloudError struct {
+	code    string
+	message string
+}
+
+func (e *cloudError) Error() string { return e.message }
+func (e *cloudError) Code() string  { return e.code }
+
+func TestClassifyError_NgrokCodes(t *testing.T) {
+	for ngrokCode, want := range ngrokErrorCodes {
+		t.Run(ngrokCode, func(t *testing.T) {
+			// The code wins over a misleading message
+			err := fmt.Errorf("failed to start endpoint: %w", &cloudError{code: ngrokCode, message: "already in use"})
+			got := ClassifyError(err)
+			require.NotNil(t, got)
+			assert.Equal(t, want, got.Code)
+			assert.Equal(t, ngrokCode, got.NgrokCode)
+			assert.Equal(t, err.Error(), got.Message)
+			assert.Equal(t, remediations[want], got.Remediation)
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      ErrorCode
+		ngrokCode string
+	}{
+		{"unknown ngrok code falls back to the message", &cloudError{code: "ERR_NGROK_9999", message: "invalid traffic policy: bad expression"}, ErrorCodePolicyInvalid, "ERR_NGROK_9999"},
+		{"unknown ngrok code and message", &cloudError{code: "ERR_NGROK_9999", message: "something broke"}, ErrorCodeUnknown, "ERR_NGROK_9999"},
+		{"code in a flattened message", fmt.Errorf("connect: %v", &cloudError{code: "ERR_NGROK_105", message: "bad token\n\nERR_NGROK_105"}), ErrorCodeAuthFailed, "ERR_NGROK_105"},
+		{"partial code in a message", errors.New("see ERR_NGROK_1050"), ErrorCodeUnknown, ""},
+
+		{"invalid authtoken", errors.New("The authtoken you specified is invalid."), ErrorCodeAuthFailed, ""},
+		{"authentication failed", errors.New("authentication failed: bad credentials"), ErrorCodeAuthFailed, ""},
+		{"already online", errors.New("The endpoint is already online."), ErrorCodeURLInUse, ""},
+		{"already in use", errors.New("domain already in use"), ErrorCodeURLInUse, ""},
+		{"already bound", errors.New("tcp address already bound"), ErrorCodeURLInUse, ""},
+		{"invalid traffic policy", errors.New("Invalid traffic policy: unknown action"), ErrorCodePolicyInvalid, ""},
+		{"unparseable traffic policy", errors.New("failed to parse traffic policy: yaml error"), ErrorCodePolicyInvalid, ""},
+		{"insufficient quota", errors.New("failed to create tunnel: insufficient quota"), ErrorCodeQuotaExceeded, ""},
+		{"limit exceeded", errors.New("endpoint limit exceeded"), ErrorCodeQuotaExceeded, ""},
+		{"limit reached", errors.New("agent session limit reached"), ErrorCodeQuotaExceeded, ""},
+
+		{"bare policy", errors.New("failed to apply retry policy"), ErrorCodeUnknown, ""},
+		{"bare limit", errors.New("rate limit: slow down"), ErrorCodeUnknown, ""},
+		{"bare authtoken", errors.New("reading authtoken from disk: permission denied"), ErrorCodeUnknown, ""},
+
+		{"dns", &net.DNSError{Err: "no such host", Name: "connect.ngrok-agent.com"}, ErrorCodeNetworkDown, ""},
+		{"network unreachable", fmt.Errorf("dial: %w", syscall.ENETUNREACH), ErrorCodeNetworkDown, ""},
+		{"host unreachable", fmt.Errorf("dial: %w", syscall.EHOSTUNREACH), ErrorCodeNetworkDown, ""},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), ErrorCodeNetworkDown, ""},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrorCodeNetworkDown, ""},
+		{"deadline exceeded", fmt.Errorf("connect: %w", context.DeadlineExceeded), ErrorCodeNetworkDown, ""},
+		{"unknown", errors.New("something broke"), ErrorCodeUnknown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.code, got.Code)
+			assert.Equal(t, tt.ngrokCode, got.NgrokCode)
+			assert.Equal(t, tt.err.Error(), got.Message)
+		})
+	}
+}
+
+func TestClassifyError_PassesThroughStatusErrors(t *testing.T) {
+	assert.Nil(t, ClassifyError(nil))
+
+	statusErr := newStatusError(ErrorCodeSecretUnresolved, "secret not found")
+	assert.Same(t, statusErr, ClassifyError(fmt.Errorf("resolve: %w", statusErr)))
+}
@@ -0,0 +1,56 @@
+package manager
+
+import "time"
+
+// ConvergeStatus reports the progress of convergence for health checks
+type ConvergeStatus struct {
+	Converged      bool        `json:"converged"`                // a convergence has finished since the manager started
+	Running        bool        `json:"running"`                  // a convergence holds the manager's lock
+	StartedAt      time.Time   `json:"startedAt,omitempty"`      // when the running or last convergence took the lock
+	LastFinishedAt time.Time   `json:"lastFinishedAt,omitempty"` // when the last convergence finished
+	LastError      string      `json:"lastError,omitempty"`      // error of the last convergence
+	Operations     []Operation `json:"operations"`               // running endpoint operations
+}
+
+// RunningFor returns how long the running convergence has held the manager's
+// lock, or 0 if none is running
+func (s ConvergeStatus) RunningFor(now time.Time) time.Duration {
+	if !s.Running {
+		return 0
+	}
+	return now.Sub(s.StartedAt)
+}
+
+func (m *manager) ConvergeStatus() ConvergeStatus {
+	m.convergeMu.Lock()
+	status := m.convergeStatus
+	m.convergeMu.Unlock()
+
+	status.Operations = m.operations()
+	return status
+}
+
+// convergeStarted records that a convergence took m.mu. It is tracked under
+// its own mutex so that it can be read while the convergence hangs.
+func (m *manager) convergeStarted() {
+	m.convergeMu.Lock()
+	defer m.convergeMu.Unlock()
+
+	m.convergeStatus.Running = true
+	m.convergeStatus.StartedAt = time.Now()
+	m.convergeReported = false
+}
+
+// convergeFinished records the outcome of the running convergence
+func (m *manager) convergeFinished(err *error) {
+	m.convergeMu.Lock()
+	defer m.convergeMu.Unlock()
+
+	m.convergeStatus.Converged = true
+	m.convergeStatus.Running = false
+	m.convergeStatus.LastFinishedAt = time.Now()
+	m.convergeStatus.LastError = ""
+	if *err != nil {
+		m.convergeStatus.LastError = (*err).Error()
+	}
+}
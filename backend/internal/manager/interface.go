@@ -14,6 +14,9 @@ type Manager interface {
 	Converge(ctx context.Context) error
 	AgentStatus() AgentStatus
 	EndpointStatus() map[string]EndpointStatus
+	// ConvergeStatus reports convergence progress without waiting for a
+	// running convergence
+	ConvergeStatus() ConvergeStatus
 	Shutdown(ctx context.Context) error
 }
 
@@ -22,6 +25,7 @@ type AgentStatus struct {
 	State       string        `json:"state"` // "online" | "offline" | "reconnecting"
 	ConnectedAt time.Time     `json:"connectedAt"`
 	LastError   string        `json:"lastError,omitempty"`
+	Error       *StatusError  `json:"error,omitempty"`   // classified LastError
 	Latency     time.Duration `json:"latency,omitempty"` // connection latency from heartbeat
 }
 
@@ -41,7 +45,9 @@ const (
 
 // EndpointStatus represents runtime state of an endpoint
 type EndpointStatus struct {
-	URL       string `json:"url,omitempty"` // ngrok public URL
-	State     string `json:"state"`               // "online" | "offline" | "starting"
-	LastError string `json:"lastError,omitempty"` // last error from convergence
+	URL       string        `json:"url,omitempty"`       // ngrok public URL
+	State     string        `json:"state"`               // "online" | "offline" | "starting"
+	LastError string        `json:"lastError,omitempty"` // last error from convergence
+	Error     *StatusError  `json:"error,omitempty"`     // classified LastError
+	Routes    []RouteStatus `json:"routes,omitempty"`    // one per configured route, in order
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"log/slog"
 	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	ngrok "golang.ngrok.com/ngrok/v2"
 
+	"github.com/ngrok/ngrok-docker-extension/internal/accesslog"
 	"github.com/ngrok/ngrok-docker-extension/internal/store"
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
 )
 
 type manager struct {
@@ -17,6 +20,9 @@ type manager struct {
 	NgrokSDK         NgrokSDK
 	DockerClient     DockerClient
 	ProtocolDetector ProtocolDetector
+	Secrets          SecretResolver        // nil leaves references unresolved
+	AccessLog        *accesslog.Log        // nil records no access log
+	Propagator       *telemetry.Propagator // nil propagates no trace context
 	Logger           *slog.Logger
 	ExtensionVersion string // Extension version for client info
 
@@ -28,11 +34,13 @@ type manager struct {
 	agentCtx           context.Context    // Context for agent operations
 	agentCancel        context.CancelFunc // Cancel function for agent context
 	agentStatus        AgentStatus
-	agentConfig        store.AgentConfig                  // Track current agent config for comparison
-	endpointStatus     map[string]EndpointStatus          // Track endpoint runtime status
-	endpointForwarders map[string]ngrok.EndpointForwarder // Track active forwarders
-	endpointCancels    map[string]context.CancelFunc      // Track forwarder cancel functions
-	endpointConfigs    map[string]string                  // Track last known config hashes for change detection
+	agentConfig        store.AgentConfig                    // Track current agent config for comparison
+	endpointStatus     map[string]EndpointStatus            // Track endpoint runtime status
+	endpointForwarders map[string]ngrok.EndpointForwarder   // Track active forwarders
+	endpointCancels    map[string]context.CancelFunc        // Track forwarder cancel functions
+	endpointConfigs    map[string]string                    // Track last known config hashes for change detection
+	routeForwarders    map[string][]ngrok.EndpointForwarder // Route forwarders by endpoint, nil where a route failed
+	routeStatus        map[string][]RouteStatus             // Route runtime status by endpoint, guarded by endpointMu
 
 	// Converge loop state
 	convergeInterval time.Duration
@@ -40,15 +48,31 @@ type manager struct {
 	convergeCtx      context.Context
 	convergeCancel   context.CancelFunc
 	triggerChan      chan struct{}
+
+	// Endpoint operations, added and removed under mu but readable by the
+	// watchdog while a convergence holds it
+	opsMu            sync.Mutex
+	endpointOps      map[string]*endpointOp   // the running operation of each endpoint
+	operationTimeout time.Duration            // how long an operation may run before it is abandoned
+	slowOperation    time.Duration            // how long an operation may run before the watchdog reports it
+	endpointRetries  map[string]endpointRetry // backoff of endpoints whose start timed out, guarded by mu
+
+	// Convergence progress, readable while Converge holds mu
+	convergeMu       sync.Mutex
+	convergeStatus   ConvergeStatus
+	convergeReported bool // the watchdog reported the running convergence
 }
 
 // NewManager creates a new manager instance
-func NewManager(store store.Store, ngrokSDK NgrokSDK, docker DockerClient, protocolDetector ProtocolDetector, logger *slog.Logger, extensionVersion string, convergeInterval time.Duration) Manager {
+func NewManager(store store.Store, ngrokSDK NgrokSDK, docker DockerClient, protocolDetector ProtocolDetector, secrets SecretResolver, accessLog *accesslog.Log, propagator *telemetry.Propagator, logger *slog.Logger, extensionVersion string, convergeInterval time.Duration) Manager {
 	m := &manager{
 		Store:            store,
 		NgrokSDK:         ngrokSDK,
 		DockerClient:     docker,
 		ProtocolDetector: protocolDetector,
+		Secrets:          secrets,
+		AccessLog:        accessLog,
+		Propagator:       propagator,
 		Logger:           logger,
 		ExtensionVersion: extensionVersion,
 		convergeInterval: convergeInterval,
@@ -62,6 +86,12 @@ func NewManager(store store.Store, ngrokSDK NgrokSDK, docker DockerClient, proto
 		endpointForwarders: make(map[string]ngrok.EndpointForwarder),
 		endpointCancels:    make(map[string]context.CancelFunc),
 		endpointConfigs:    make(map[string]string),
+		routeForwarders:    make(map[string][]ngrok.EndpointForwarder),
+		routeStatus:        make(map[string][]RouteStatus),
+		endpointOps:        make(map[string]*endpointOp),
+		operationTimeout:   DefaultOperationTimeout,
+		slowOperation:      DefaultSlowOperation,
+		endpointRetries:    make(map[string]endpointRetry),
 		triggerChan:        make(chan struct{}, 1), // buffered to prevent blocking
 	}
 
@@ -73,9 +103,13 @@ func NewManager(store store.Store, ngrokSDK NgrokSDK, docker DockerClient, proto
 	return m
 }
 
-func (m *manager) Converge(ctx context.Context) error {
+func (m *manager) Converge(ctx context.Context) (err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.convergeStarted()
+	defer m.convergeFinished(&err)
+	ctx, end := startPhase(ctx, "total")
+	defer end(&err)
 	// load state
 	state, err := m.Store.Load()
 	if err != nil {
@@ -104,7 +138,14 @@ func (m *manager) EndpointStatus() map[string]EndpointStatus {
 	defer m.endpointMu.RUnlock()
 
 	// Return a copy to avoid concurrent modification
-	return maps.Clone(m.endpointStatus)
+	statuses := maps.Clone(m.endpointStatus)
+	for id, routes := range m.routeStatus {
+		if status, ok := statuses[id]; ok {
+			status.Routes = slices.Clone(routes)
+			statuses[id] = status
+		}
+	}
+	return statuses
 }
 
 func (m *manager) Shutdown(ctx context.Context) error {
@@ -127,12 +168,16 @@ func (m *manager) Shutdown(ctx context.Context) error {
 func (m *manager) startConvergeLoop() {
 	m.convergeCtx, m.convergeCancel = context.WithCancel(context.Background())
 	m.convergeTicker = time.NewTicker(m.convergeInterval)
+	changes := m.Store.Watch(m.convergeCtx)
 
-	go m.convergeLoop()
+	go m.convergeLoop(changes)
+	go m.watchdog(m.convergeCtx)
 }
 
-// convergeLoop runs the periodic converge loop
-func (m *manager) convergeLoop() {
+// convergeLoop runs the converge loop periodically and whenever the stored
+// state is changed outside this process, so that such edits apply
+// immediately. The API converges after its own writes.
+func (m *manager) convergeLoop(changes <-chan *store.State) {
 	defer m.convergeTicker.Stop()
 
 	for {
@@ -141,6 +186,12 @@ func (m *manager) convergeLoop() {
 			return
 		case <-m.convergeTicker.C:
 		case <-m.triggerChan:
+		case _, ok := <-changes:
+			if !ok {
+				// fall back to the ticker
+				changes = nil
+				continue
+			}
 		}
 
 		// run converge
@@ -158,6 +209,15 @@ func (m *manager) triggerConverge() {
 	}
 }
 
+// SetOperationTimeoutsForTests overrides how long endpoint operations may run
+// before they are abandoned and before the watchdog reports them
+func (m *manager) SetOperationTimeoutsForTests(timeout, slow time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operationTimeout = timeout
+	m.slowOperation = slow
+}
+
 // CallNgrokEventHandlerForTests triggers the ngrok event handler for testing purposes
 func (m *manager) CallNgrokEventHandlerForTests(event ngrok.Event) {
 	m.handleAgentEvent(event)
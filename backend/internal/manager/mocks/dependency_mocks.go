@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	types "github.com/docker/docker/api/types"
+	container "github.com/docker/docker/api/types/container"
 	detectproto "github.com/ngrok/ngrok-docker-extension/internal/detectproto"
 	gomock "go.uber.org/mock/gomock"
 	ngrok "golang.ngrok.com/ngrok/v2"
@@ -86,6 +87,21 @@ func (m *MockDockerClient) EXPECT() *MockDockerClientMockRecorder {
 	return m.recorder
 }
 
+// ContainerExec mocks base method.
+func (m *MockDockerClient) ContainerExec(ctx context.Context, containerID string, cmd []string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerExec", ctx, containerID, cmd)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerExec indicates an expected call of ContainerExec.
+func (mr *MockDockerClientMockRecorder) ContainerExec(ctx, containerID, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerExec", reflect.TypeOf((*MockDockerClient)(nil).ContainerExec), ctx, containerID, cmd)
+}
+
 // ContainerInspect mocks base method.
 func (m *MockDockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +117,36 @@ func (mr *MockDockerClientMockRecorder) ContainerInspect(ctx, containerID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerInspect", reflect.TypeOf((*MockDockerClient)(nil).ContainerInspect), ctx, containerID)
 }
 
+// ContainerList mocks base method.
+func (m *MockDockerClient) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerList", ctx, options)
+	ret0, _ := ret[0].([]container.Summary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerList indicates an expected call of ContainerList.
+func (mr *MockDockerClientMockRecorder) ContainerList(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerList", reflect.TypeOf((*MockDockerClient)(nil).ContainerList), ctx, options)
+}
+
+// Ping mocks base method.
+func (m *MockDockerClient) Ping(ctx context.Context) (types.Ping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(types.Ping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockDockerClientMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockDockerClient)(nil).Ping), ctx)
+}
+
 // MockProtocolDetector is a mock of ProtocolDetector interface.
 type MockProtocolDetector struct {
 	ctrl     *gomock.Controller
@@ -139,3 +185,57 @@ func (mr *MockProtocolDetectorMockRecorder) Detect(ctx, host, port any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detect", reflect.TypeOf((*MockProtocolDetector)(nil).Detect), ctx, host, port)
 }
+
+// DetectContainer mocks base method.
+func (m *MockProtocolDetector) DetectContainer(ctx context.Context, containerID, host, port string) (*detectproto.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectContainer", ctx, containerID, host, port)
+	ret0, _ := ret[0].(*detectproto.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectContainer indicates an expected call of DetectContainer.
+func (mr *MockProtocolDetectorMockRecorder) DetectContainer(ctx, containerID, host, port any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectContainer", reflect.TypeOf((*MockProtocolDetector)(nil).DetectContainer), ctx, containerID, host, port)
+}
+
+// MockSecretResolver is a mock of SecretResolver interface.
+type MockSecretResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockSecretResolverMockRecorder is the mock recorder for MockSecretResolver.
+type MockSecretResolverMockRecorder struct {
+	mock *MockSecretResolver
+}
+
+// NewMockSecretResolver creates a new mock instance.
+func NewMockSecretResolver(ctrl *gomock.Controller) *MockSecretResolver {
+	mock := &MockSecretResolver{ctrl: ctrl}
+	mock.recorder = &MockSecretResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretResolver) EXPECT() *MockSecretResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockSecretResolver) Resolve(s string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", s)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockSecretResolverMockRecorder) Resolve(s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockSecretResolver)(nil).Resolve), s)
+}
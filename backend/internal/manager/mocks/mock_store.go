@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	store "github.com/ngrok/ngrok-docker-extension/internal/store"
@@ -82,3 +83,17 @@ func (mr *MockStoreMockRecorder) Update(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStore)(nil).Update), arg0)
 }
+
+// Watch mocks base method.
+func (m *MockStore) Watch(ctx context.Context) <-chan *store.State {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx)
+	ret0, _ := ret[0].(<-chan *store.State)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockStoreMockRecorder) Watch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockStore)(nil).Watch), ctx)
+}
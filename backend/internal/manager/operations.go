@@ -0,0 +1,356 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Endpoint operations start the forwarders of an endpoint in a worker
+// goroutine. Convergence waits a moment for each, so that API responses
+// usually show the endpoint online, and otherwise leaves it running. Workers
+// never touch the manager's maps: results are applied under m.mu, and only
+// while the operation is still the endpoint's current one. Forwarders started
+// by an abandoned operation are closed. An endpoint whose start timed out is
+// not started again until its backoff expires.
+
+const (
+	// DefaultOperationTimeout is how long an endpoint operation may run before
+	// convergence abandons it and reports the endpoint failed
+	DefaultOperationTimeout = 30 * time.Second
+	// DefaultSlowOperation is how long an endpoint operation or a convergence
+	// may run before the watchdog reports it
+	DefaultSlowOperation = 10 * time.Second
+
+	// maxRetryBackoff caps the backoff of an endpoint whose start timed out
+	maxRetryBackoff = 5 * time.Minute
+
+	// operationWait is how long convergence waits for an operation to finish
+	operationWait = time.Second
+	// watchdogInterval is how often the watchdog looks for slow operations
+	watchdogInterval = 5 * time.Second
+)
+
+// Operation kinds
+const (
+	OperationStart  = "start"  // start an endpoint and its routes
+	OperationRoutes = "routes" // start the routes of a running endpoint that failed
+)
+
+// Operation describes a running endpoint operation
+type Operation struct {
+	EndpointID string    `json:"endpointId"`
+	Kind       string    `json:"kind"` // "start" | "routes"
+	StartedAt  time.Time `json:"startedAt"`
+	Deadline   time.Time `json:"deadline"`
+	Slow       bool      `json:"slow"` // reported by the watchdog
+}
+
+// endpointOp is a running endpoint operation. Its fields are set before the
+// worker starts, except for Slow (guarded by opsMu), result (set before done
+// is closed) and applied (guarded by m.mu).
+type endpointOp struct {
+	Operation
+	config     store.EndpointConfig
+	configHash string
+	routes     []int           // indexes of the routes to start
+	agent      ngrok.Agent     // the agent to forward with, read once under m.mu
+	agentCtx   context.Context // the lifetime of the agent's forwarders
+	cancel     context.CancelFunc
+
+	done    chan struct{}
+	result  endpointResult
+	applied bool // the result was applied or discarded
+}
+
+// endpointResult holds the forwarders started by an operation
+type endpointResult struct {
+	forwarder ngrok.EndpointForwarder // start operations only
+	err       error
+	routes    map[int]routeResult // by route index
+}
+
+type routeResult struct {
+	forwarder ngrok.EndpointForwarder
+	err       error
+}
+
+// close closes every forwarder of a discarded result
+func (r endpointResult) close() {
+	if r.forwarder != nil {
+		r.forwarder.Close()
+	}
+	for _, route := range r.routes {
+		if route.forwarder != nil {
+			route.forwarder.Close()
+		}
+	}
+}
+
+// endpointRetry is the backoff of an endpoint whose start timed out
+type endpointRetry struct {
+	configHash string // the config that timed out; a change retries at once
+	timeouts   int
+	retryAt    time.Time
+}
+
+// backOff delays the next start of an endpoint after its start timed out.
+// m.mu must be held.
+func (m *manager) backOff(endpointID, configHash string) {
+	retry := m.endpointRetries[endpointID]
+	if retry.configHash != configHash {
+		retry = endpointRetry{configHash: configHash}
+	}
+	retry.timeouts++
+	backoff := min(m.operationTimeout<<min(retry.timeouts-1, 10), maxRetryBackoff)
+	retry.retryAt = time.Now().Add(backoff)
+	m.endpointRetries[endpointID] = retry
+	m.Logger.Info("Backing off endpoint start", "endpoint", endpointID, "timeouts", retry.timeouts, "retryAt", retry.retryAt)
+}
+
+// backingOff reports whether an endpoint must not be started yet. m.mu must be
+// held.
+func (m *manager) backingOff(endpointID, configHash string) bool {
+	retry, exists := m.endpointRetries[endpointID]
+	if !exists {
+		return false
+	}
+	if retry.configHash != configHash {
+		delete(m.endpointRetries, endpointID)
+		return false
+	}
+	return time.Now().Before(retry.retryAt)
+}
+
+// startEndpointOp starts an operation on an endpoint and waits briefly for it
+// to finish. m.mu must be held.
+func (m *manager) startEndpointOp(ctx context.Context, kind string, config store.EndpointConfig, configHash string, routes []int) {
+	// The worker outlives this convergence, but stays in its trace
+	opCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.operationTimeout)
+	now := time.Now()
+	op := &endpointOp{
+		Operation: Operation{
+			EndpointID: config.ID,
+			Kind:       kind,
+			StartedAt:  now,
+			Deadline:   now.Add(m.operationTimeout),
+		},
+		config:     config,
+		configHash: configHash,
+		routes:     routes,
+		agent:      m.agent,
+		agentCtx:   m.agentCtx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	m.opsMu.Lock()
+	m.endpointOps[config.ID] = op
+	m.opsMu.Unlock()
+
+	go func() {
+		op.result = m.runEndpointOp(opCtx, op)
+		close(op.done)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.finishEndpointOp(op)
+	}()
+
+	select {
+	case <-op.done:
+		m.finishEndpointOp(op)
+	case <-time.After(min(operationWait, m.operationTimeout)):
+	case <-ctx.Done():
+	}
+}
+
+// runEndpointOp starts the forwarders of an operation. It runs without m.mu
+// and must not touch the manager's state.
+func (m *manager) runEndpointOp(ctx context.Context, op *endpointOp) endpointResult {
+	result := endpointResult{routes: make(map[int]routeResult, len(op.routes))}
+
+	// Start the routes first, so that the endpoint can forward to them as
+	// soon as it is online
+	for _, i := range op.routes {
+		if err := ctx.Err(); err != nil {
+			result.routes[i] = routeResult{err: err}
+			continue
+		}
+		forwarder, err := m.createRouteForwarder(ctx, op, op.config.Routes[i])
+		result.routes[i] = routeResult{forwarder: forwarder, err: err}
+	}
+
+	if op.Kind == OperationStart {
+		if result.err = ctx.Err(); result.err == nil {
+			result.forwarder, result.err = m.createEndpointForwarder(ctx, op)
+		}
+	}
+	return result
+}
+
+// finishEndpointOp applies the result of a finished operation, or closes its
+// forwarders if the operation was abandoned. m.mu must be held.
+func (m *manager) finishEndpointOp(op *endpointOp) {
+	if op.applied {
+		return
+	}
+	op.applied = true
+	op.cancel()
+
+	m.opsMu.Lock()
+	current := m.endpointOps[op.EndpointID] == op
+	if current {
+		delete(m.endpointOps, op.EndpointID)
+	}
+	m.opsMu.Unlock()
+
+	if !current {
+		op.result.close()
+		return
+	}
+
+	endpointID := op.EndpointID
+	forwarders := m.routeForwarders[endpointID]
+	for _, i := range op.routes {
+		route := op.result.routes[i]
+		if route.err != nil {
+			m.Logger.Warn("Failed to start route", "endpoint", endpointID, "path", op.config.Routes[i].Path, "error", route.err)
+			m.setRouteStatus(endpointID, i, RouteStatus{
+				State:     EndpointStateFailed,
+				LastError: route.err.Error(),
+				Error:     ClassifyError(route.err),
+			})
+			continue
+		}
+		if i >= len(forwarders) {
+			route.forwarder.Close()
+			continue
+		}
+		forwarders[i] = route.forwarder
+		m.setRouteStatus(endpointID, i, RouteStatus{State: EndpointStateOnline, URL: route.forwarder.URL().String()})
+	}
+
+	if op.Kind != OperationStart {
+		return
+	}
+	if op.result.err != nil {
+		if ClassifyError(op.result.err).Code == ErrorCodeOperationTimeout {
+			m.backOff(endpointID, op.configHash)
+		}
+		m.setEndpointFailed(endpointID, fmt.Errorf("failed to create endpoint: %w", op.result.err))
+		return
+	}
+	delete(m.endpointRetries, endpointID)
+	m.endpointForwarders[endpointID] = op.result.forwarder
+	m.endpointConfigs[endpointID] = op.configHash
+	m.setEndpointOnline(endpointID, op.result.forwarder)
+}
+
+// endpointOp returns the running operation of an endpoint, if any
+func (m *manager) endpointOp(endpointID string) *endpointOp {
+	m.opsMu.Lock()
+	defer m.opsMu.Unlock()
+
+	return m.endpointOps[endpointID]
+}
+
+// abandonEndpointOp cancels the running operation of an endpoint. Its
+// forwarders are closed once it finishes. m.mu must be held.
+func (m *manager) abandonEndpointOp(endpointID string) {
+	m.opsMu.Lock()
+	op := m.endpointOps[endpointID]
+	delete(m.endpointOps, endpointID)
+	m.opsMu.Unlock()
+
+	if op == nil {
+		return
+	}
+	op.cancel()
+	select {
+	case <-op.done:
+		m.finishEndpointOp(op)
+	default: // the worker discards its result when it finishes
+	}
+}
+
+// timeOutEndpointOp abandons an operation that ran past its deadline and
+// reports what it was starting as failed. m.mu must be held.
+func (m *manager) timeOutEndpointOp(op *endpointOp) {
+	m.abandonEndpointOp(op.EndpointID)
+	m.Logger.Warn("Endpoint operation timed out", "endpoint", op.EndpointID, "operation", op.Kind, "timeout", m.operationTimeout)
+
+	err := newStatusError(ErrorCodeOperationTimeout, fmt.Sprintf("%s operation did not finish within %s", op.Kind, m.operationTimeout))
+	for _, i := range op.routes {
+		m.setRouteStatus(op.EndpointID, i, RouteStatus{State: EndpointStateFailed, LastError: err.Message, Error: err})
+	}
+	if op.Kind == OperationStart {
+		m.backOff(op.EndpointID, op.configHash)
+		m.setEndpointFailed(op.EndpointID, err)
+	}
+}
+
+// operations returns the running endpoint operations, sorted by endpoint
+func (m *manager) operations() []Operation {
+	m.opsMu.Lock()
+	defer m.opsMu.Unlock()
+
+	operations := make([]Operation, 0, len(m.endpointOps))
+	for _, id := range slices.Sorted(maps.Keys(m.endpointOps)) {
+		operations = append(operations, m.endpointOps[id].Operation)
+	}
+	return operations
+}
+
+// watchdog reports the endpoint operations and convergences running for
+// longer than m.slowOperation until ctx is done. It never takes m.mu, which a
+// stuck convergence holds.
+func (m *manager) watchdog(ctx context.Context) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.reportSlowOperations(now)
+		}
+	}
+}
+
+// reportSlowOperations logs each operation and convergence running for
+// longer than m.slowOperation once
+func (m *manager) reportSlowOperations(now time.Time) {
+	m.convergeMu.Lock()
+	running := m.convergeStatus.RunningFor(now)
+	report := running > m.slowOperation && !m.convergeReported
+	if report {
+		m.convergeReported = true
+	}
+	m.convergeMu.Unlock()
+	if report {
+		m.Logger.Warn("Convergence is running slowly", "runningFor", running.Round(time.Second))
+		slowOperations.Add(context.Background(), 1, metric.WithAttributes(attribute.String("operation", "converge")))
+	}
+
+	m.opsMu.Lock()
+	defer m.opsMu.Unlock()
+	for _, op := range m.endpointOps {
+		if op.Slow || now.Sub(op.StartedAt) <= m.slowOperation {
+			continue
+		}
+		op.Slow = true
+		m.Logger.Warn("Endpoint operation is running slowly", "endpoint", op.EndpointID, "operation", op.Kind,
+			"runningFor", now.Sub(op.StartedAt).Round(time.Second), "deadline", op.Deadline)
+		slowOperations.Add(context.Background(), 1, metric.WithAttributes(attribute.String("operation", op.Kind)))
+	}
+}
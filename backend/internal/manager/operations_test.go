@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/manager/mocks"
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestReportSlowOperations(t *testing.T) {
+	var logs bytes.Buffer
+	started := time.Now()
+	m := &manager{
+		Logger:        slog.New(slog.NewTextHandler(&logs, nil)),
+		slowOperation: 10 * time.Second,
+		endpointOps: map[string]*endpointOp{
+			"web:80": {Operation: Operation{EndpointID: "web:80", Kind: OperationStart, StartedAt: started}},
+		},
+		convergeStatus: ConvergeStatus{Running: true, StartedAt: started},
+	}
+
+	m.reportSlowOperations(started.Add(5 * time.Second))
+	assert.Empty(t, logs.String())
+	assert.False(t, m.operations()[0].Slow)
+
+	// Each is reported once
+	m.reportSlowOperations(started.Add(11 * time.Second))
+	m.reportSlowOperations(started.Add(12 * time.Second))
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("Convergence is running slowly")))
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("Endpoint operation is running slowly")))
+	assert.True(t, m.operations()[0].Slow)
+}
+
+func TestForward_AbandonsHungForward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentCtx, cancelAgent := context.WithCancel(context.Background())
+	defer cancelAgent()
+
+	// Forward hangs until its context is cancelled, then returns a forwarder
+	// anyway
+	late := mocks.NewMockEndpointForwarder(ctrl)
+	closed := make(chan struct{})
+	late.EXPECT().Close().DoAndReturn(func() error {
+		close(closed)
+		return nil
+	})
+	agent := mocks.NewMockAgent(ctrl)
+	agent.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *ngrok.Upstream, _ ...ngrok.EndpointOption) (ngrok.EndpointForwarder, error) {
+			<-ctx.Done()
+			return late, nil
+		}).Times(1)
+
+	m := &manager{
+		Logger:             slog.New(slog.DiscardHandler),
+		agent:              agent,
+		agentCtx:           agentCtx,
+		endpointStatus:     map[string]EndpointStatus{},
+		endpointForwarders: map[string]ngrok.EndpointForwarder{},
+		endpointConfigs:    map[string]string{},
+		routeForwarders:    map[string][]ngrok.EndpointForwarder{},
+		routeStatus:        map[string][]RouteStatus{},
+		endpointOps:        map[string]*endpointOp{},
+		endpointRetries:    map[string]endpointRetry{},
+		operationTimeout:   50 * time.Millisecond,
+	}
+	config := store.EndpointConfig{ID: "web:80", Upstream: "http://web:80", ExpectedState: EndpointStateOnline}
+	goroutines := runtime.NumGoroutine()
+
+	m.mu.Lock()
+	require.NoError(t, m.handleEndpointOnlineState(context.Background(), config.ID, config))
+	m.mu.Unlock()
+
+	// The worker gives up at the deadline and the late forwarder is closed
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("forwarder returned after the deadline was not closed")
+	}
+	require.Eventually(t, func() bool {
+		// Eventually runs the condition in a goroutine of its own
+		return runtime.NumGoroutine() <= goroutines+1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := m.EndpointStatus()[config.ID]
+	assert.Equal(t, EndpointStateFailed, status.State)
+	require.NotNil(t, status.Error)
+	assert.Equal(t, ErrorCodeOperationTimeout, status.Error.Code)
+	assert.Empty(t, m.operations())
+
+	// The endpoint is not started again until its backoff expires
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	retry := m.endpointRetries[config.ID]
+	assert.Equal(t, 1, retry.timeouts)
+	retry.retryAt = time.Now().Add(time.Minute)
+	m.endpointRetries[config.ID] = retry
+	require.NoError(t, m.handleEndpointOnlineState(context.Background(), config.ID, config))
+	assert.Empty(t, m.operations())
+}
@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// TargetTypeRouter is the target type of endpoints that forward to other
+// endpoints rather than to an upstream
+const TargetTypeRouter = "router"
+
+// routerIDPrefix prefixes the IDs of routers
+const routerIDPrefix = "router:"
+
+// routerHostPattern restricts router hostnames to characters that need no
+// escaping in traffic policy expressions
+var routerHostPattern = regexp.MustCompile(`^[A-Za-z0-9.-]+$`)
+
+// routerUpstream is the upstream of routers. It is never dialed: the
+// router's policy answers every request.
+const routerUpstream = "http://localhost:80"
+
+// RouterID validates the URL of a router and returns its ID, router:host.
+// Routers are identified by their URL since they have no target.
+func RouterID(rawURL string) (string, error) {
+	if rawURL == "" {
+		return "", errors.New("routers require a url")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid router url %q", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("router url %q must be http or https", rawURL)
+	}
+	return routerIDPrefix + strings.ToLower(u.Hostname()), nil
+}
+
+// ValidateRouter checks the rules of a router. Routers have no target of
+// their own, and each host and path pair may appear once.
+func ValidateRouter(config store.EndpointConfig) error {
+	if len(config.Router) == 0 {
+		return nil
+	}
+	if config.ContainerID != "" || config.TargetPort != "" || config.Upstream != "" {
+		return errors.New("router cannot be combined with containerId, targetPort or upstream")
+	}
+	if len(config.Routes) > 0 {
+		return errors.New("router cannot be combined with routes")
+	}
+
+	seen := make(map[[2]string]bool)
+	for i, rule := range config.Router {
+		if rule.Endpoint == "" {
+			return fmt.Errorf("router[%d]: endpoint is required", i)
+		}
+		if rule.Host != "" && !routerHostPattern.MatchString(rule.Host) {
+			return fmt.Errorf("router[%d]: invalid host %q", i, rule.Host)
+		}
+		if rule.Path != "" && !routePathPattern.MatchString(rule.Path) {
+			return fmt.Errorf("router[%d]: path %q must start with '/' and contain only letters, digits, '/', '_', '.', '~' and '-', optionally followed by '*'", i, rule.Path)
+		}
+		key := [2]string{strings.ToLower(rule.Host), strings.TrimSuffix(rule.Path, "*")}
+		if seen[key] {
+			return fmt.Errorf("router[%d]: duplicate rule for host %q and path %q", i, rule.Host, rule.Path)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ValidateReferences checks the references involving the endpoint id: the
+// endpoints it forwards to if it is a router, and the endpoint itself if a
+// router forwards to it. They must exist and be internal.
+func ValidateReferences(configs map[string]store.EndpointConfig, id string) error {
+	for _, routerID := range slices.Sorted(maps.Keys(configs)) {
+		for i, rule := range configs[routerID].Router {
+			if routerID != id && rule.Endpoint != id {
+				continue
+			}
+			target, exists := configs[rule.Endpoint]
+			switch {
+			case !exists:
+				return fmt.Errorf("%s: router[%d] forwards to endpoint %s, which does not exist", routerID, i, rule.Endpoint)
+			case !IsInternal(target):
+				return fmt.Errorf("%s: router[%d] forwards to endpoint %s, which is not internal", routerID, i, rule.Endpoint)
+			}
+		}
+	}
+	return nil
+}
+
+// routerPolicy appends to the traffic policy of a router the rules
+// forwarding to the internal endpoints it references, in order, and a last
+// rule answering the requests no rule matches
+func routerPolicy(config store.EndpointConfig, configs map[string]store.EndpointConfig) (string, error) {
+	var rules []any
+	for i, rule := range config.Router {
+		target, exists := configs[rule.Endpoint]
+		if !exists || target.URL == "" {
+			return "", newStatusError(ErrorCodeEndpointMissing, fmt.Sprintf("router[%d] forwards to endpoint %s, which does not exist", i, rule.Endpoint))
+		}
+
+		var expressions []string
+		if rule.Host != "" {
+			expressions = append(expressions, fmt.Sprintf("req.host == '%s'", strings.ToLower(rule.Host)))
+		}
+		if rule.Path != "" {
+			expressions = append(expressions, fmt.Sprintf("req.url.path.startsWith('%s')", strings.TrimSuffix(rule.Path, "*")))
+		}
+		r := map[string]any{
+			"name":    fmt.Sprintf("forward to %s", rule.Endpoint),
+			"actions": []any{forwardInternal(target.URL)},
+		}
+		if len(expressions) > 0 {
+			r["expressions"] = []string{strings.Join(expressions, " && ")}
+		}
+		rules = append(rules, r)
+	}
+	rules = append(rules, map[string]any{
+		"name": "no matching rule",
+		"actions": []any{map[string]any{
+			"type":   "custom-response",
+			"config": map[string]any{"status_code": 404, "content": "no route matches this request"},
+		}},
+	})
+	return appendHTTPRequestRules(config.TrafficPolicy, rules)
+}
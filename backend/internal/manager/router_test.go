@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestEndpointBinding(t *testing.T) {
+	tests := []struct {
+		url, binding string
+		want         string
+		err          string
+	}{
+		{url: "https://web.example.com", want: ""},
+		{url: "https://api.internal", want: "internal"},
+		{url: "api.internal", binding: "internal", want: "internal"},
+		{url: "${secret:url}", binding: "internal", want: "internal"},
+		{url: "https://api.internal", binding: "public", err: "binding must be internal"},
+		{binding: "internal", err: "internal endpoints require a url ending in .internal"},
+		{binding: "private", err: "binding must be public, internal or kubernetes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url+"/"+tt.binding, func(t *testing.T) {
+			got, err := EndpointBinding(tt.url, tt.binding)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouterPolicy(t *testing.T) {
+	configs := map[string]store.EndpointConfig{
+		"api:8080": {ID: "api:8080", URL: "https://api.internal"},
+		"web:80":   {ID: "web:80", URL: "https://web.internal"},
+	}
+	router := store.EndpointConfig{
+		URL: "https://example.com",
+		Router: []store.RouterRule{
+			{Host: "API.example.com", Path: "/v1/*", Endpoint: "api:8080"},
+			{Endpoint: "web:80"},
+		},
+	}
+
+	policy, err := routerPolicy(router, configs)
+	require.NoError(t, err)
+
+	var document map[string][]struct {
+		Expressions []string `json:"expressions"`
+		Actions     []struct {
+			Type   string         `json:"type"`
+			Config map[string]any `json:"config"`
+		} `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(policy), &document))
+
+	rules := document["on_http_request"]
+	require.Len(t, rules, 3)
+	assert.Equal(t, []string{"req.host == 'api.example.com' && req.url.path.startsWith('/v1/')"}, rules[0].Expressions)
+	assert.Equal(t, "https://api.internal", rules[0].Actions[0].Config["url"])
+	assert.Empty(t, rules[1].Expressions)
+	assert.Equal(t, "https://web.internal", rules[1].Actions[0].Config["url"])
+	// Requests matching no rule never reach the router's upstream
+	assert.Equal(t, "custom-response", rules[2].Actions[0].Type)
+
+	delete(configs, "web:80")
+	_, err = routerPolicy(router, configs)
+	require.Error(t, err)
+	assert.Equal(t, ErrorCodeEndpointMissing, ClassifyError(err).Code)
+}
+
+func TestValidateReferences(t *testing.T) {
+	configs := map[string]store.EndpointConfig{
+		"router:example.com": {Router: []store.RouterRule{{Endpoint: "api:8080"}}},
+		"api:8080":           {URL: "https://api.internal"},
+		"web:80":             {URL: "https://web.example.com"},
+	}
+	require.NoError(t, ValidateReferences(configs, "router:example.com"))
+	require.NoError(t, ValidateReferences(configs, "api:8080"))
+
+	// Only references involving the endpoint are checked
+	configs["router:other.com"] = store.EndpointConfig{Router: []store.RouterRule{{Endpoint: "web:80"}}}
+	require.NoError(t, ValidateReferences(configs, "api:8080"))
+	assert.EqualError(t, ValidateReferences(configs, "web:80"), "router:other.com: router[0] forwards to endpoint web:80, which is not internal")
+
+	delete(configs, "api:8080")
+	assert.EqualError(t, ValidateReferences(configs, "api:8080"), "router:example.com: router[0] forwards to endpoint api:8080, which does not exist")
+}
+
+func TestBuildTopology(t *testing.T) {
+	configs := map[string]store.EndpointConfig{
+		"router:example.com": {
+			ID:     "router:example.com",
+			URL:    "https://example.com",
+			Router: []store.RouterRule{{Path: "/api/", Endpoint: "api:8080"}, {Endpoint: "gone:80"}},
+		},
+		"api:8080": {
+			ID: "api:8080", ContainerID: "api", TargetPort: "8080", URL: "https://api.internal",
+			Routes: []store.Route{{Path: "/metrics", TargetPort: "9090"}},
+		},
+		"upstream:host.docker.internal:3000": {ID: "upstream:host.docker.internal:3000", Upstream: "http://host.docker.internal:3000"},
+	}
+	statuses := map[string]EndpointStatus{
+		"router:example.com": {State: EndpointStateOnline, URL: "https://example.com"},
+		"api:8080":           {State: EndpointStateOnline, URL: "https://api.internal", Routes: []RouteStatus{{State: EndpointStateFailed}}},
+	}
+
+	topology := BuildTopology(configs, statuses)
+
+	assert.Equal(t, []TopologyNode{
+		{ID: "api:8080", Type: NodeTypeEndpoint, Label: "api:8080", URL: "https://api.internal", Binding: BindingInternal, State: EndpointStateOnline},
+		{ID: "container:api", Type: NodeTypeContainer, Label: "api"},
+		{ID: "http://host.docker.internal:3000", Type: NodeTypeUpstream, Label: "http://host.docker.internal:3000"},
+		{ID: "https://api-8080-9090.internal", Type: NodeTypeRoute, Label: "/metrics", URL: "https://api-8080-9090.internal", State: EndpointStateFailed},
+		{ID: "router:example.com", Type: NodeTypeRouter, Label: "router:example.com", URL: "https://example.com", Binding: BindingPublic, State: EndpointStateOnline},
+		{ID: "upstream:host.docker.internal:3000", Type: NodeTypeEndpoint, Label: "upstream:host.docker.internal:3000", Binding: BindingPublic, State: EndpointStateOffline},
+	}, topology.Nodes)
+	assert.Equal(t, []TopologyEdge{
+		{From: "api:8080", To: "container:api", Port: "8080"},
+		{From: "api:8080", To: "https://api-8080-9090.internal", Path: "/metrics"},
+		{From: "https://api-8080-9090.internal", To: "container:api", Port: "9090"},
+		{From: "router:example.com", To: "api:8080", Path: "/api/"},
+		{From: "router:example.com", To: "gone:80", Broken: true},
+		{From: "upstream:host.docker.internal:3000", To: "http://host.docker.internal:3000"},
+	}, topology.Edges)
+}
@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// routePathPattern restricts route paths to characters that need no escaping
+// in traffic policy expressions
+var routePathPattern = regexp.MustCompile(`^/[A-Za-z0-9/_.~-]*\*?$`)
+
+// routeLabelInvalid matches the characters not allowed in an internal
+// endpoint hostname
+var routeLabelInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// RouteStatus is the runtime state of one route of an endpoint
+type RouteStatus struct {
+	Path       string       `json:"path"`
+	TargetPort string       `json:"targetPort"`
+	URL        string       `json:"url"`   // internal endpoint serving the route
+	State      string       `json:"state"` // "online" | "offline" | "starting" | "failed"
+	LastError  string       `json:"lastError,omitempty"`
+	Error      *StatusError `json:"error,omitempty"` // classified LastError
+}
+
+// ValidateRoutes checks the routes of an endpoint. Routes need an HTTP
+// endpoint attached to a container, and each path may appear once.
+func ValidateRoutes(config store.EndpointConfig) error {
+	if len(config.Routes) == 0 {
+		return nil
+	}
+	if config.ContainerID == "" {
+		return errors.New("routes require an endpoint attached to a container")
+	}
+	if config.URL != "" {
+		if u, err := url.Parse(config.URL); err == nil && u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("routes require an http or https endpoint, not %s", u.Scheme)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, route := range config.Routes {
+		if !routePathPattern.MatchString(route.Path) {
+			return fmt.Errorf("routes[%d]: path %q must start with '/' and contain only letters, digits, '/', '_', '.', '~' and '-', optionally followed by '*'", i, route.Path)
+		}
+		if n, err := strconv.Atoi(route.TargetPort); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("routes[%d]: invalid targetPort %q", i, route.TargetPort)
+		}
+		prefix := routePrefix(route)
+		if seen[prefix] {
+			return fmt.Errorf("routes[%d]: duplicate path %q", i, route.Path)
+		}
+		seen[prefix] = true
+	}
+	return nil
+}
+
+// routePrefix returns the path prefix a route matches
+func routePrefix(route store.Route) string {
+	return strings.TrimSuffix(route.Path, "*")
+}
+
+// RouteURL returns the URL of the internal endpoint serving a route. It is
+// derived from the container and both ports so that it is stable across
+// restarts.
+func RouteURL(config store.EndpointConfig, route store.Route) string {
+	label := routeLabelInvalid.ReplaceAllString(strings.ToLower(config.ContainerID), "-")
+	label = strings.Trim(label, "-")
+	// Leave room for the ports within the 63 characters of a DNS label
+	if len(label) > 40 {
+		label = label[:40]
+	}
+	return fmt.Sprintf("https://%s-%s-%s.internal", label, config.TargetPort, route.TargetPort)
+}
+
+// routingPolicy appends to a traffic policy the rules forwarding each route
+// to its internal endpoint. They run after the policy's own on_http_request
+// rules, so that e.g. authentication applies to every route.
+func routingPolicy(policy string, config store.EndpointConfig) (string, error) {
+	var rules []any
+	for _, route := range config.Routes {
+		prefix := routePrefix(route)
+		rules = append(rules, map[string]any{
+			"name":        fmt.Sprintf("route %s to port %s", route.Path, route.TargetPort),
+			"expressions": []string{fmt.Sprintf("req.url.path.startsWith('%s')", prefix)},
+			"actions":     []any{forwardInternal(RouteURL(config, route))},
+		})
+	}
+	return appendHTTPRequestRules(policy, rules)
+}
+
+// forwardInternal returns a forward-internal action sending requests to the
+// internal endpoint at url
+func forwardInternal(url string) map[string]any {
+	return map[string]any{
+		"type":   "forward-internal",
+		"config": map[string]any{"url": url},
+	}
+}
+
+// appendHTTPRequestRules appends rules to the on_http_request phase of a
+// traffic policy, after the policy's own rules, and returns it as JSON
+func appendHTTPRequestRules(policy string, rules []any) (string, error) {
+	return addPolicyRules(policy, "on_http_request", nil, rules)
+}
+
+// addPolicyRules adds rules before and after the policy's own rules in one
+// phase of a traffic policy, and returns it as JSON
+func addPolicyRules(policy, phase string, before, after []any) (string, error) {
+	document := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(policy), &document); err != nil {
+		return "", newStatusError(ErrorCodePolicyInvalid, fmt.Sprintf("traffic policy is not valid YAML or JSON: %v", err))
+	}
+	if document == nil {
+		document = make(map[string]any)
+	}
+
+	var existing []any
+	if rules, ok := document[phase]; ok {
+		if existing, ok = rules.([]any); !ok {
+			return "", newStatusError(ErrorCodePolicyInvalid, fmt.Sprintf("%s must be a list of rules", phase))
+		}
+	}
+	document[phase] = slices.Concat(before, existing, after)
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestRoutingPolicy(t *testing.T) {
+	config := store.EndpointConfig{
+		ContainerID: "My_App",
+		TargetPort:  "3000",
+		Routes:      []store.Route{{Path: "/api/*", TargetPort: "8080"}},
+	}
+	policy := `
+on_http_request:
+  - actions:
+      - type: basic-auth
+        config:
+          credentials: ["user:password"]
+`
+	routed, err := routingPolicy(policy, config)
+	require.NoError(t, err)
+
+	var document map[string][]struct {
+		Expressions []string `json:"expressions"`
+		Actions     []struct {
+			Type   string         `json:"type"`
+			Config map[string]any `json:"config"`
+		} `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(routed), &document))
+
+	// The policy's own rules run first
+	rules := document["on_http_request"]
+	require.Len(t, rules, 2)
+	assert.Equal(t, "basic-auth", rules[0].Actions[0].Type)
+	assert.Equal(t, []string{"req.url.path.startsWith('/api/')"}, rules[1].Expressions)
+	assert.Equal(t, "forward-internal", rules[1].Actions[0].Type)
+	assert.Equal(t, "https://my-app-3000-8080.internal", rules[1].Actions[0].Config["url"])
+}
+
+func TestValidateRoutes(t *testing.T) {
+	valid := store.EndpointConfig{ContainerID: "web", TargetPort: "3000", Routes: []store.Route{{Path: "/api/", TargetPort: "8080"}}}
+	require.NoError(t, ValidateRoutes(valid))
+
+	tests := []struct {
+		name   string
+		modify func(*store.EndpointConfig)
+		err    string
+	}{
+		{"upstream target", func(c *store.EndpointConfig) { c.ContainerID = "" }, "routes require an endpoint attached to a container"},
+		{"tcp endpoint", func(c *store.EndpointConfig) { c.URL = "tcp://1.tcp.ngrok.io:12345" }, "routes require an http or https endpoint"},
+		{"relative path", func(c *store.EndpointConfig) { c.Routes[0].Path = "api" }, "must start with '/'"},
+		{"quoted path", func(c *store.EndpointConfig) { c.Routes[0].Path = "/a')" }, "must start with '/'"},
+		{"bad port", func(c *store.EndpointConfig) { c.Routes[0].TargetPort = "http" }, "invalid targetPort"},
+		{"duplicate path", func(c *store.EndpointConfig) {
+			c.Routes = append(c.Routes, store.Route{Path: "/api/*", TargetPort: "9090"})
+		}, "duplicate path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			config.Routes = append([]store.Route(nil), valid.Routes...)
+			tt.modify(&config)
+			err := ValidateRoutes(config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
+)
+
+// The global providers are used, so nothing is exported until
+// telemetry.Setup installs them
+var (
+	tracer = otel.Tracer(telemetry.InstrumentationName)
+	meter  = otel.Meter(telemetry.InstrumentationName)
+
+	convergeDuration, _ = meter.Float64Histogram("ngrok_ext.converge.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of each phase of convergence"),
+	)
+	agentConnects, _ = meter.Int64Counter("ngrok_ext.agent.connects",
+		metric.WithDescription("Attempts to connect the agent, by outcome"),
+	)
+	endpointForwards, _ = meter.Int64Counter("ngrok_ext.endpoint.forwards",
+		metric.WithDescription("Endpoints started, by outcome"),
+	)
+	slowOperations, _ = meter.Int64Counter("ngrok_ext.watchdog.slow_operations",
+		metric.WithDescription("Convergences and endpoint operations reported running slowly, by operation"),
+	)
+)
+
+// startPhase starts the span of a phase of convergence. The returned function
+// ends it and records its duration.
+func startPhase(ctx context.Context, phase string) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, "converge."+phase)
+	started := time.Now()
+	return ctx, func(errp *error) {
+		outcome := endSpan(span, *errp)
+		convergeDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+			attribute.String("phase", phase),
+			attribute.String("outcome", outcome),
+		))
+	}
+}
+
+// endSpan ends span, recording err, and returns the outcome for metrics
+func endSpan(span trace.Span, err error) string {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "error"
+	}
+	return "ok"
+}
+
+// forward starts an endpoint of op forwarding to upstream. The SDK ties the
+// forwarder to the context passed to Forward, so each forwarder gets its own,
+// derived from the agent's and cancelled when the forwarder is closed. ctx
+// bounds the call: once it is done, Forward is abandoned and its context
+// cancelled, and a forwarder it still returns is closed.
+func (m *manager) forward(ctx context.Context, op *endpointOp, upstream *ngrok.Upstream, opts ...ngrok.EndpointOption) (ngrok.EndpointForwarder, error) {
+	ctx, span := tracer.Start(ctx, "endpoint.forward", trace.WithAttributes(
+		attribute.String("ngrok_ext.endpoint", op.EndpointID),
+	))
+
+	lifetime, stop := context.WithCancel(op.agentCtx)
+	forwarded := make(chan forwardResult)
+	go func() {
+		forwarder, err := op.agent.Forward(lifetime, upstream, opts...)
+		select {
+		case forwarded <- forwardResult{forwarder, err}:
+		case <-ctx.Done():
+			if forwarder != nil {
+				forwarder.Close()
+			}
+			stop()
+		}
+	}()
+
+	var result forwardResult
+	select {
+	case result = <-forwarded:
+	case <-ctx.Done():
+		stop()
+		result.err = ctx.Err()
+		if errors.Is(result.err, context.DeadlineExceeded) {
+			result.err = newStatusError(ErrorCodeOperationTimeout,
+				fmt.Sprintf("forwarding did not finish within %s", op.Deadline.Sub(op.StartedAt)))
+		}
+	}
+
+	outcome := endSpan(span, result.err)
+	endpointForwards.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+	if result.err != nil {
+		stop()
+		return nil, result.err
+	}
+	return closingForwarder{result.forwarder, stop}, nil
+}
+
+type forwardResult struct {
+	forwarder ngrok.EndpointForwarder
+	err       error
+}
+
+// closingForwarder cancels the context of a forwarder when it is closed
+type closingForwarder struct {
+	ngrok.EndpointForwarder
+	stop context.CancelFunc
+}
+
+func (f closingForwarder) Close() error {
+	defer f.stop()
+	return f.EndpointForwarder.Close()
+}
@@ -0,0 +1,32 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+	"github.com/ngrok/ngrok-docker-extension/internal/telemetry"
+)
+
+func TestUpstreamDialer(t *testing.T) {
+	propagator := telemetry.NewPropagator()
+	defer propagator.Close()
+
+	dialed := func(m *manager, config store.EndpointConfig, upstreamScheme string) bool {
+		upstream := ngrok.WithUpstream("172.17.0.1:80", m.upstreamDialer(config, upstreamScheme)...)
+		return fmt.Sprintf("%+v", upstream) != fmt.Sprintf("%+v", ngrok.WithUpstream("172.17.0.1:80"))
+	}
+
+	// Without an access log or telemetry, the SDK dials upstreams itself
+	assert.False(t, dialed(&manager{}, store.EndpointConfig{ID: "web:80"}, "http"))
+
+	// Trace context is only propagated in plain HTTP requests
+	m := &manager{Propagator: propagator}
+	assert.True(t, dialed(m, store.EndpointConfig{ID: "web:80"}, "http"))
+	assert.False(t, dialed(m, store.EndpointConfig{ID: "web:443"}, "https"))
+	assert.False(t, dialed(m, store.EndpointConfig{ID: "db:5432"}, "tcp"))
+	assert.False(t, dialed(m, store.EndpointConfig{ID: "web:80", ProxyProtocol: "v1"}, "http"))
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"time"
+
 	ngrok "golang.ngrok.com/ngrok/v2"
 )
 
@@ -8,3 +10,9 @@ import (
 type TestEventHandler interface {
 	CallNgrokEventHandlerForTests(event ngrok.Event)
 }
+
+// TestOperationTimeouts is an interface for shortening the timeouts of
+// endpoint operations in tests
+type TestOperationTimeouts interface {
+	SetOperationTimeoutsForTests(timeout, slow time.Duration)
+}
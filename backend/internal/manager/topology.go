@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Topology node types
+const (
+	NodeTypeRouter    = "router"    // an endpoint forwarding to internal endpoints
+	NodeTypeEndpoint  = "endpoint"  // an endpoint forwarding to a container or upstream
+	NodeTypeRoute     = "route"     // the internal endpoint serving a route
+	NodeTypeContainer = "container" // a container endpoints forward to
+	NodeTypeUpstream  = "upstream"  // an upstream URL endpoints forward to
+)
+
+// Topology is the graph of endpoints and what they forward to
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// TopologyNode is an endpoint, a route or a target in the topology
+type TopologyNode struct {
+	ID      string `json:"id"`   // endpoint ID, route URL, container:<id> or upstream URL
+	Type    string `json:"type"` // "router" | "endpoint" | "route" | "container" | "upstream"
+	Label   string `json:"label"`
+	URL     string `json:"url,omitempty"`
+	Binding string `json:"binding,omitempty"` // endpoints and routers
+	State   string `json:"state,omitempty"`   // endpoints, routers and routes
+}
+
+// TopologyEdge is traffic forwarded from one node to another
+type TopologyEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Host   string `json:"host,omitempty"`   // router rules
+	Path   string `json:"path,omitempty"`   // router rules and routes
+	Port   string `json:"port,omitempty"`   // edges to containers
+	Broken bool   `json:"broken,omitempty"` // the node forwarded to does not exist
+}
+
+// BuildTopology returns the graph of the configured endpoints, with their
+// runtime state. Nodes are sorted by ID; edges follow their source nodes.
+func BuildTopology(configs map[string]store.EndpointConfig, statuses map[string]EndpointStatus) Topology {
+	topology := Topology{Nodes: []TopologyNode{}, Edges: []TopologyEdge{}}
+	containers := make(map[string]bool)
+	addContainer := func(from, containerID, port string) {
+		id := NodeTypeContainer + ":" + containerID
+		if !containers[id] {
+			containers[id] = true
+			topology.Nodes = append(topology.Nodes, TopologyNode{ID: id, Type: NodeTypeContainer, Label: containerID})
+		}
+		topology.Edges = append(topology.Edges, TopologyEdge{From: from, To: id, Port: port})
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(configs)) {
+		config := configs[id]
+		status, exists := statuses[id]
+		if !exists {
+			status.State = EndpointStateOffline
+		}
+
+		node := TopologyNode{
+			ID:      id,
+			Type:    NodeTypeEndpoint,
+			Label:   id,
+			URL:     cmp.Or(status.URL, config.URL),
+			Binding: topologyBinding(config),
+			State:   status.State,
+		}
+		if config.Description != "" {
+			node.Label = config.Description
+		}
+
+		switch TargetType(config) {
+		case TargetTypeRouter:
+			node.Type = NodeTypeRouter
+			for _, rule := range config.Router {
+				_, exists := configs[rule.Endpoint]
+				topology.Edges = append(topology.Edges, TopologyEdge{
+					From:   id,
+					To:     rule.Endpoint,
+					Host:   rule.Host,
+					Path:   rule.Path,
+					Broken: !exists,
+				})
+			}
+		case TargetTypeUpstream:
+			topology.Nodes = append(topology.Nodes, TopologyNode{ID: config.Upstream, Type: NodeTypeUpstream, Label: config.Upstream})
+			topology.Edges = append(topology.Edges, TopologyEdge{From: id, To: config.Upstream})
+		default:
+			addContainer(id, config.ContainerID, config.TargetPort)
+			for i, route := range config.Routes {
+				routeNode := TopologyNode{
+					ID:    RouteURL(config, route),
+					Type:  NodeTypeRoute,
+					Label: route.Path,
+					URL:   RouteURL(config, route),
+					State: EndpointStateOffline,
+				}
+				if i < len(status.Routes) {
+					routeNode.State = status.Routes[i].State
+				}
+				topology.Nodes = append(topology.Nodes, routeNode)
+				topology.Edges = append(topology.Edges, TopologyEdge{From: id, To: routeNode.ID, Path: route.Path})
+				addContainer(routeNode.ID, config.ContainerID, route.TargetPort)
+			}
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+
+	// Several endpoints may forward to the same upstream
+	topology.Nodes = slices.CompactFunc(slices.SortedStableFunc(slices.Values(topology.Nodes), func(a, b TopologyNode) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), func(a, b TopologyNode) bool {
+		return a.ID == b.ID
+	})
+	return topology
+}
+
+// topologyBinding returns the binding of an endpoint, which is public unless
+// set otherwise
+func topologyBinding(config store.EndpointConfig) string {
+	if IsInternal(config) {
+		return BindingInternal
+	}
+	return cmp.Or(config.Binding, BindingPublic)
+}
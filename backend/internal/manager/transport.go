@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	ngrok "golang.ngrok.com/ngrok/v2"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// PROXY protocol versions sent to the upstream
+const (
+	ProxyProtocolV1 = "v1"
+	ProxyProtocolV2 = "v2"
+)
+
+// Where TLS endpoints terminate TLS
+const (
+	// TLSTerminationPassthrough forwards the encrypted stream to the
+	// upstream, which holds the certificate
+	TLSTerminationPassthrough = "passthrough"
+	// TLSTerminationEdge terminates TLS in the ngrok cloud, which forwards
+	// the decrypted stream
+	TLSTerminationEdge = "edge"
+)
+
+// reservedTCPHostPattern matches the hostnames of the TCP addresses ngrok
+// reserves, e.g. 1.tcp.ngrok.io or 5.tcp.eu.ngrok.io
+var reservedTCPHostPattern = regexp.MustCompile(`^[0-9]+\.tcp\.([a-z]+\.)?ngrok\.io$`)
+
+// EndpointScheme returns the scheme of an endpoint's URL. Endpoints without
+// one are https.
+func EndpointScheme(config store.EndpointConfig) string {
+	if config.URL != "" {
+		if u, err := url.Parse(config.URL); err == nil && u.Scheme != "" {
+			return u.Scheme
+		}
+	}
+	return "https"
+}
+
+// ValidateTransport checks the TCP and TLS options of an endpoint. TCP URLs
+// are either tcp:// for an address assigned on start, a reserved address such
+// as tcp://1.tcp.ngrok.io:12345, or an internal or kubernetes address.
+func ValidateTransport(config store.EndpointConfig) error {
+	scheme := EndpointScheme(config)
+
+	if scheme == "tcp" && !strings.Contains(config.URL, "${") {
+		u, err := url.Parse(config.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("tcp url %s cannot have a path", config.URL)
+		}
+		if u.Host != "" {
+			if n, err := strconv.Atoi(u.Port()); err != nil || n < 1 || n > 65535 {
+				return fmt.Errorf("tcp url %s requires a port", config.URL)
+			}
+			if !reservedTCPHostPattern.MatchString(u.Hostname()) && !isInternalURL(config.URL) && config.Binding != BindingKubernetes {
+				return fmt.Errorf("tcp url %s is not a reserved address; reserve one in the ngrok dashboard, e.g. tcp://1.tcp.ngrok.io:12345, or use tcp:// for an address assigned on start", config.URL)
+			}
+		}
+	}
+
+	switch config.ProxyProtocol {
+	case "":
+	case ProxyProtocolV1, ProxyProtocolV2:
+		if scheme != "tcp" && scheme != "tls" {
+			return errors.New("proxyProtocol requires a tcp or tls endpoint")
+		}
+	default:
+		return fmt.Errorf("proxyProtocol must be v1 or v2, got %q", config.ProxyProtocol)
+	}
+
+	switch config.TLSTermination {
+	case "":
+	case TLSTerminationPassthrough, TLSTerminationEdge:
+		if scheme != "tls" {
+			return errors.New("tlsTermination requires a tls endpoint")
+		}
+	default:
+		return fmt.Errorf("tlsTermination must be passthrough or edge, got %q", config.TLSTermination)
+	}
+	return nil
+}
+
+// EndpointTLSTermination returns where an endpoint terminates TLS: the
+// termination it sets, or passthrough for TLS endpoints setting none. It is
+// stored explicitly so that MigrateTLSTermination can tell endpoints saved
+// before the option existed apart.
+func EndpointTLSTermination(config store.EndpointConfig) string {
+	if config.TLSTermination == "" && EndpointScheme(config) == "tls" {
+		return TLSTerminationPassthrough
+	}
+	return config.TLSTermination
+}
+
+// MigrateTLSTermination terminates the TLS endpoints saved before
+// tlsTermination existed at the edge. They forwarded to a tls:// upstream
+// when the container spoke TLS, which edge termination keeps doing, whereas
+// the passthrough default would hand the container the client's encrypted
+// stream. It returns the IDs of the endpoints changed.
+func MigrateTLSTermination(state *store.State) []string {
+	var migrated []string
+	for _, id := range slices.Sorted(maps.Keys(state.EndpointConfigs)) {
+		config := state.EndpointConfigs[id]
+		if config.TLSTermination != "" || EndpointScheme(config) != "tls" {
+			continue
+		}
+		config.TLSTermination = TLSTerminationEdge
+		config.ResourceVersion = state.NextResourceVersion()
+		state.EndpointConfigs[id] = config
+		migrated = append(migrated, id)
+	}
+	return migrated
+}
+
+// terminationPolicy prepends to the traffic policy of a TLS endpoint
+// terminating at the edge the rule doing so, so that the policy's own
+// on_tcp_connect rules see the decrypted connection
+func terminationPolicy(policy string) (string, error) {
+	return addPolicyRules(policy, "on_tcp_connect", []any{map[string]any{
+		"name":    "terminate tls at the edge",
+		"actions": []any{map[string]any{"type": "terminate-tls"}},
+	}}, nil)
+}
+
+// upstreamProxyProto returns the upstream option sending the PROXY protocol
+// header configured for an endpoint, if any
+func upstreamProxyProto(config store.EndpointConfig) []ngrok.UpstreamOption {
+	switch config.ProxyProtocol {
+	case ProxyProtocolV1:
+		return []ngrok.UpstreamOption{ngrok.WithUpstreamProxyProto(ngrok.ProxyProtoV1)}
+	case ProxyProtocolV2:
+		return []ngrok.UpstreamOption{ngrok.WithUpstreamProxyProto(ngrok.ProxyProtoV2)}
+	}
+	return nil
+}
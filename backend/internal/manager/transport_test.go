@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+func TestTerminationPolicy(t *testing.T) {
+	policy := `
+on_tcp_connect:
+  - actions:
+      - type: restrict-ips
+        config:
+          enforce: true
+          allow: ["10.0.0.0/8"]
+`
+	terminated, err := terminationPolicy(policy)
+	require.NoError(t, err)
+
+	var document map[string][]struct {
+		Actions []struct {
+			Type string `json:"type"`
+		} `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(terminated), &document))
+
+	// TLS is terminated before the policy's own rules run
+	rules := document["on_tcp_connect"]
+	require.Len(t, rules, 2)
+	assert.Equal(t, "terminate-tls", rules[0].Actions[0].Type)
+	assert.Equal(t, "restrict-ips", rules[1].Actions[0].Type)
+}
+
+func TestMigrateTLSTermination(t *testing.T) {
+	state := &store.State{
+		EndpointConfigs: map[string]store.EndpointConfig{
+			"legacy:8443":      {ID: "legacy:8443", URL: "tls://legacy.example.com", ResourceVersion: 1},
+			"passthrough:8443": {ID: "passthrough:8443", URL: "tls://app.example.com", TLSTermination: TLSTerminationPassthrough, ResourceVersion: 2},
+			"web:80":           {ID: "web:80", URL: "https://web.example.com", ResourceVersion: 3},
+		},
+		LastResourceVersion: 3,
+	}
+
+	assert.Equal(t, []string{"legacy:8443"}, MigrateTLSTermination(state))
+	assert.Equal(t, TLSTerminationEdge, state.EndpointConfigs["legacy:8443"].TLSTermination)
+	assert.Equal(t, int64(4), state.EndpointConfigs["legacy:8443"].ResourceVersion)
+	assert.Equal(t, TLSTerminationPassthrough, state.EndpointConfigs["passthrough:8443"].TLSTermination)
+	assert.Empty(t, state.EndpointConfigs["web:80"].TLSTermination)
+
+	// Migrating again changes nothing
+	assert.Empty(t, MigrateTLSTermination(state))
+}
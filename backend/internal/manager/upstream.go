@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/store"
+)
+
+// Endpoint target types
+const (
+	TargetTypeContainer = "container" // a port published by a container
+	TargetTypeUpstream  = "upstream"  // an arbitrary upstream URL
+)
+
+// upstreamIDPrefix prefixes the IDs of endpoints targeting an upstream URL
+const upstreamIDPrefix = "upstream:"
+
+// upstreamDefaultPorts are the schemes accepted in upstream URLs, with the
+// port used when the URL has none. tcp and tls URLs must include a port.
+var upstreamDefaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"tcp":   "",
+	"tls":   "",
+}
+
+// TargetType returns the target type of an endpoint
+func TargetType(config store.EndpointConfig) string {
+	if len(config.Router) > 0 {
+		return TargetTypeRouter
+	}
+	if config.Upstream != "" {
+		return TargetTypeUpstream
+	}
+	return TargetTypeContainer
+}
+
+// EndpointTarget validates the target of an endpoint, either a container port
+// or an upstream URL but not both, and returns the endpoint ID:
+// containerID:targetPort or upstream:host:port. The upstream URL is returned
+// normalized.
+func EndpointTarget(containerID, targetPort, upstream string) (id string, normalized string, err error) {
+	if upstream == "" {
+		if containerID == "" {
+			return "", "", errors.New("containerId or upstream is required")
+		}
+		if targetPort == "" {
+			return "", "", errors.New("targetPort is required")
+		}
+		return containerID + ":" + targetPort, "", nil
+	}
+
+	if containerID != "" || targetPort != "" {
+		return "", "", errors.New("upstream cannot be combined with containerId and targetPort")
+	}
+	u, err := ParseUpstream(upstream)
+	if err != nil {
+		return "", "", err
+	}
+	return upstreamIDPrefix + u.Host, u.String(), nil
+}
+
+// ParseUpstream parses an upstream URL such as http://host.docker.internal:3000
+// or tcp://db:5432, filling in the default port of http and https URLs
+func ParseUpstream(upstream string) (*url.URL, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream: %w", err)
+	}
+
+	defaultPort, ok := upstreamDefaultPorts[u.Scheme]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("invalid upstream %q: scheme must be http, https, tcp or tls", upstream)
+	case u.Hostname() == "":
+		return nil, fmt.Errorf("invalid upstream %q: host is required", upstream)
+	case u.User != nil:
+		return nil, fmt.Errorf("invalid upstream %q: credentials are not supported", upstream)
+	case (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "":
+		return nil, fmt.Errorf("invalid upstream %q: paths and queries are not supported", upstream)
+	}
+
+	port := u.Port()
+	if port == "" {
+		if defaultPort == "" {
+			return nil, fmt.Errorf("invalid upstream %q: %s upstreams require a port", upstream, u.Scheme)
+		}
+		port = defaultPort
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("invalid upstream %q: invalid port %s", upstream, port)
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: net.JoinHostPort(u.Hostname(), port)}, nil
+}
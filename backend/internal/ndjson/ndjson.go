@@ -0,0 +1,147 @@
+// Package ndjson keeps records in newline delimited JSON files that are
+// rotated once they reach a size, and sends newly written records to
+// followers. It is shared by the access log and the backend's logs.
+package ndjson
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Files names a file and its rotated backups. Rotated files have the suffix
+// .1 (the most recent) to .MaxBackups.
+type Files struct {
+	Path       string
+	MaxBackups int
+}
+
+// Backup returns the file rotated backup times ago, or Path for 0
+func (f Files) Backup(backup int) string {
+	if backup == 0 {
+		return f.Path
+	}
+	return f.Path + "." + strconv.Itoa(backup)
+}
+
+// Rotate shifts the files by one, dropping the oldest
+func (f Files) Rotate() error {
+	if f.MaxBackups < 1 {
+		return os.Remove(f.Path)
+	}
+	for i := f.MaxBackups - 1; i >= 0; i-- {
+		err := os.Rename(f.Backup(i), f.Backup(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read returns the records of every file, oldest first, parsed by parse and
+// selected by match. Only the last limit records are kept if limit > 0.
+func Read[T any](files Files, parse func([]byte) (T, error), match func(T) bool, limit int) ([]T, error) {
+	records := []T{}
+	for i := files.MaxBackups; i >= 0; i-- {
+		var err error
+		if records, err = readFile(files.Backup(i), parse, match, records); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+// readFile appends the records of the file at path selected by match
+func readFile[T any](path string, parse func([]byte) (T, error), match func(T) bool, records []T) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return records, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record, err := parse(scanner.Bytes())
+		if err != nil {
+			// Skip a line torn by a crash rather than failing the read
+			continue
+		}
+		if match(record) {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// Followers sends the records written under a key to the channels returned
+// by Add. Its methods must be called with the lock passed to NewFollowers
+// held, the same one serializing writes and reads of the files, so that a
+// follower sees every record written after the read preceding Add.
+type Followers[T any] struct {
+	mu        sync.Locker
+	followers map[string]map[*follower[T]]struct{} // by key
+}
+
+// follower receives the records selected by match
+type follower[T any] struct {
+	ch    chan T
+	match func(T) bool
+}
+
+// NewFollowers creates followers guarded by mu
+func NewFollowers[T any](mu sync.Locker) *Followers[T] {
+	return &Followers[T]{mu: mu, followers: make(map[string]map[*follower[T]]struct{})}
+}
+
+// Add returns a channel of the records sent under key selected by match,
+// closed once ctx is done. Records are dropped from the channel if the
+// receiver falls behind.
+func (f *Followers[T]) Add(ctx context.Context, key string, match func(T) bool) <-chan T {
+	fl := &follower[T]{ch: make(chan T, 64), match: match}
+	if f.followers[key] == nil {
+		f.followers[key] = make(map[*follower[T]]struct{})
+	}
+	f.followers[key][fl] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.followers[key], fl)
+		if len(f.followers[key]) == 0 {
+			delete(f.followers, key)
+		}
+		close(fl.ch)
+	}()
+	return fl.ch
+}
+
+// Following reports whether any follower was added under key
+func (f *Followers[T]) Following(key string) bool {
+	return len(f.followers[key]) > 0
+}
+
+// Send sends record to the followers of key it matches
+func (f *Followers[T]) Send(key string, record T) {
+	for fl := range f.followers[key] {
+		if !fl.match(record) {
+			continue
+		}
+		select {
+		case fl.ch <- record:
+		default: // drop records for followers that fall behind
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ndjson
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseInt(line []byte) (int, error) {
+	var n int
+	err := json.Unmarshal(line, &n)
+	return n, err
+}
+
+func TestRotateAndRead(t *testing.T) {
+	files := Files{Path: filepath.Join(t.TempDir(), "test.log"), MaxBackups: 2}
+	for _, lines := range []string{"1\n2\n", "3\n{torn\n4\n", "5\n", "6\n"} {
+		require.NoError(t, files.Rotate())
+		require.NoError(t, os.WriteFile(files.Path, []byte(lines), 0600))
+	}
+
+	// The oldest file was dropped and the torn line skipped
+	all := func(int) bool { return true }
+	records, err := Read(files, parseInt, all, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6}, records)
+
+	even := func(n int) bool { return n%2 == 0 }
+	records, err = Read(files, parseInt, even, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{6}, records)
+
+	records, err = Read(Files{Path: filepath.Join(t.TempDir(), "missing.log")}, parseInt, all, 0)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestFollowers(t *testing.T) {
+	var mu sync.Mutex
+	followers := NewFollowers[int](&mu)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mu.Lock()
+	ch := followers.Add(ctx, "a", func(n int) bool { return n > 1 })
+	assert.True(t, followers.Following("a"))
+	assert.False(t, followers.Following("b"))
+	followers.Send("a", 1)
+	followers.Send("b", 3)
+	followers.Send("a", 2)
+	mu.Unlock()
+
+	assert.Equal(t, 2, <-ch)
+	cancel()
+	_, open := <-ch
+	assert.False(t, open)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.False(t, followers.Following("a"))
+}
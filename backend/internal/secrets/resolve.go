@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// referencePattern matches ${secret:name} and ${env:NAME}
+var referencePattern = regexp.MustCompile(`\$\{(secret|env):([A-Za-z0-9_.-]+)\}`)
+
+// EnvPrefix is the prefix of the environment variables ${env:NAME} references
+// may name, so that endpoints cannot read the extension's own credentials such
+// as NGROK_AUTHTOKEN or NGROK_EXT_API_TOKENS
+const EnvPrefix = "NGROK_EXT_SECRET_"
+
+// ErrUnresolved is returned when a reference names a secret or environment
+// variable that does not exist
+var ErrUnresolved = errors.New("unresolved reference")
+
+// ErrForbidden is returned when an ${env:NAME} reference names a variable
+// without EnvPrefix
+var ErrForbidden = errors.New("forbidden reference")
+
+// Reference is a ${kind:name} reference found in a configuration value
+type Reference struct {
+	Kind string // "secret" | "env"
+	Name string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("${%s:%s}", r.Kind, r.Name)
+}
+
+// allowed reports whether a reference may be resolved
+func (r Reference) allowed() bool {
+	return r.Kind != "env" || strings.HasPrefix(r.Name, EnvPrefix)
+}
+
+// CheckReferences returns an error naming the ${env:NAME} references in s to
+// variables without EnvPrefix
+func CheckReferences(s string) error {
+	var forbidden []Reference
+	for _, groups := range referencePattern.FindAllStringSubmatch(s, -1) {
+		if ref := (Reference{Kind: groups[1], Name: groups[2]}); !ref.allowed() {
+			forbidden = append(forbidden, ref)
+		}
+	}
+	if len(forbidden) > 0 {
+		return fmt.Errorf("%w: %v (environment variables must start with %s)", ErrForbidden, forbidden, EnvPrefix)
+	}
+	return nil
+}
+
+// Resolver expands references from a secret store and the environment
+type Resolver struct {
+	Secrets   *Store
+	LookupEnv func(string) (string, bool)
+}
+
+// NewResolver creates a resolver that reads environment variables from the
+// extension's own environment
+func NewResolver(secrets *Store) *Resolver {
+	return &Resolver{Secrets: secrets, LookupEnv: os.LookupEnv}
+}
+
+// Resolve replaces every reference in s with its value. The error names the
+// missing references but never includes resolved values.
+func (r *Resolver) Resolve(s string) (string, error) {
+	if err := CheckReferences(s); err != nil {
+		return "", err
+	}
+
+	var missing []Reference
+	resolved := referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := referencePattern.FindStringSubmatch(match)
+		ref := Reference{Kind: groups[1], Name: groups[2]}
+
+		var value string
+		var ok bool
+		switch ref.Kind {
+		case "secret":
+			value, ok = r.Secrets.Lookup(ref.Name)
+		case "env":
+			value, ok = r.LookupEnv(ref.Name)
+		}
+		if !ok {
+			missing = append(missing, ref)
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %v", ErrUnresolved, missing)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,155 @@
+// Package secrets stores named secrets and expands the ${secret:name} and
+// ${env:NAME} references that endpoint configurations use to keep them out of
+// the persisted state.
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// namePattern restricts secret and environment variable names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidName reports whether name can be used as a secret name
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Info describes a stored secret. Values are never returned by the API.
+type Info struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// entry is a secret as persisted in the secrets file
+type entry struct {
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Store holds named secrets, persisted to a file readable only by the
+// extension
+type Store struct {
+	path string // empty for an in-memory store
+
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+// NewMemoryStore creates a store that keeps secrets in memory only
+func NewMemoryStore() *Store {
+	return &Store{entries: make(map[string]entry)}
+}
+
+// NewStore loads the secrets file at path, which may not exist yet
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %s: %w", path, err)
+	}
+	if s.entries == nil {
+		s.entries = make(map[string]entry)
+	}
+	return s, nil
+}
+
+// List returns the stored secrets sorted by name
+func (s *Store) List() []Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := []Info{}
+	for _, name := range slices.Sorted(maps.Keys(s.entries)) {
+		infos = append(infos, Info{Name: name, UpdatedAt: s.entries[name].UpdatedAt})
+	}
+	return infos
+}
+
+// Lookup returns the value of a secret
+func (s *Store) Lookup(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[name]
+	return e.Value, ok
+}
+
+// Set creates or replaces a secret. It reports whether the secret was created.
+func (s *Store) Set(name, value string) (Info, bool, error) {
+	if !ValidName(name) {
+		return Info{}, false, fmt.Errorf("invalid secret name %q: use letters, digits, '_', '-' and '.'", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.entries[name]
+	e := entry{Value: value, UpdatedAt: time.Now().UTC()}
+	s.entries[name] = e
+	if err := s.saveUnsafe(); err != nil {
+		if existed {
+			s.entries[name] = previous
+		} else {
+			delete(s.entries, name)
+		}
+		return Info{}, false, err
+	}
+	return Info{Name: name, UpdatedAt: e.UpdatedAt}, !existed, nil
+}
+
+// Delete removes a secret. It reports whether the secret existed.
+func (s *Store) Delete(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.entries[name]
+	if !existed {
+		return false, nil
+	}
+	delete(s.entries, name)
+	if err := s.saveUnsafe(); err != nil {
+		s.entries[name] = previous
+		return false, err
+	}
+	return true, nil
+}
+
+// saveUnsafe writes the secrets file without acquiring the mutex
+func (s *Store) saveUnsafe() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	// Write file atomically by writing to temp file first
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, s.path)
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_PersistsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	s, err := NewStore(path)
+	require.NoError(t, err)
+
+	_, created, err := s.Set("password", "hunter2")
+	require.NoError(t, err)
+	assert.True(t, created)
+	_, created, err = s.Set("password", "correct-horse")
+	require.NoError(t, err)
+	assert.False(t, created)
+	_, _, err = s.Set("client-secret", "abc")
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reopened, err := NewStore(path)
+	require.NoError(t, err)
+	value, ok := reopened.Lookup("password")
+	assert.True(t, ok)
+	assert.Equal(t, "correct-horse", value)
+
+	names := []string{}
+	for _, info := range reopened.List() {
+		names = append(names, info.Name)
+	}
+	assert.Equal(t, []string{"client-secret", "password"}, names)
+
+	deleted, err := reopened.Delete("password")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = reopened.Delete("password")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestStore_RejectsInvalidName(t *testing.T) {
+	_, _, err := NewMemoryStore().Set("a b", "value")
+	assert.Error(t, err)
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	store := NewMemoryStore()
+	_, _, err := store.Set("password", "hunter2")
+	require.NoError(t, err)
+
+	resolver := &Resolver{
+		Secrets: store,
+		LookupEnv: func(name string) (string, bool) {
+			if name == "NGROK_EXT_SECRET_USER" || name == "NGROK_AUTHTOKEN" {
+				return "admin", true
+			}
+			return "", false
+		},
+	}
+
+	resolved, err := resolver.Resolve("credentials: ['${env:NGROK_EXT_SECRET_USER}:${secret:password}']")
+	require.NoError(t, err)
+	assert.Equal(t, "credentials: ['admin:hunter2']", resolved)
+
+	resolved, err = resolver.Resolve("no references, ${literal} and $HOME")
+	require.NoError(t, err)
+	assert.Equal(t, "no references, ${literal} and $HOME", resolved)
+
+	_, err = resolver.Resolve("${secret:password} ${secret:missing} ${env:NGROK_EXT_SECRET_MISSING}")
+	assert.ErrorIs(t, err, ErrUnresolved)
+	assert.EqualError(t, err, "unresolved reference: [${secret:missing} ${env:NGROK_EXT_SECRET_MISSING}]")
+	assert.NotContains(t, err.Error(), "hunter2")
+
+	// Only variables with the prefix can be read, even if others are set
+	_, err = resolver.Resolve("${env:NGROK_AUTHTOKEN}")
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.NotContains(t, err.Error(), "admin")
+}
+
+func TestCheckReferences(t *testing.T) {
+	assert.NoError(t, CheckReferences("${secret:password} ${env:NGROK_EXT_SECRET_USER} $HOME"))
+	assert.EqualError(t, CheckReferences("${env:NGROK_EXT_API_TOKENS} ${env:HOME} ${env:NGROK_EXT_SECRET_USER}"),
+		"forbidden reference: [${env:NGROK_EXT_API_TOKENS} ${env:HOME}] (environment variables must start with NGROK_EXT_SECRET_)")
+}
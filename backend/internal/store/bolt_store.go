@@ -0,0 +1,244 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// metaBucket holds the state version, resource version counter and agent
+	// configuration
+	metaBucket = []byte("meta")
+	// endpointsBucket holds one key per endpoint configuration
+	endpointsBucket = []byte("endpoints")
+
+	versionKey             = []byte("version")
+	lastResourceVersionKey = []byte("lastResourceVersion")
+	agentConfigKey         = []byte("agentConfig")
+)
+
+// BoltStore implements Store on an embedded BoltDB database. Every Save and
+// Update is a single transaction that is synced to disk before it returns, so
+// a failed write leaves the previous state intact. Only the keys that changed
+// are rewritten.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens, or creates, the database at path
+func NewBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	// The timeout stops a second process from blocking forever on the lock
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(metaBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(endpointsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the database
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the path of the database
+func (s *BoltStore) Path() string {
+	return s.db.Path()
+}
+
+// Empty reports whether no state has been saved yet
+func (s *BoltStore) Empty() (bool, error) {
+	empty := true
+	err := s.db.View(func(tx *bolt.Tx) error {
+		empty = tx.Bucket(metaBucket).Get(versionKey) == nil
+		return nil
+	})
+	return empty, err
+}
+
+func (s *BoltStore) Load() (*State, error) {
+	var state *State
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		state, err = readState(tx)
+		return err
+	})
+	return state, err
+}
+
+func (s *BoltStore) Save(state *State) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		current, err := readState(tx)
+		if err != nil {
+			return err
+		}
+		return writeState(tx, current, state)
+	})
+}
+
+func (s *BoltStore) Update(fn func(*State) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		current, err := readState(tx)
+		if err != nil {
+			return err
+		}
+		// Give fn its own copy so that current still describes what is on
+		// disk when the changes are written
+		state, err := deepCopyState(current)
+		if err != nil {
+			return err
+		}
+		if err := fn(state); err != nil {
+			return err
+		}
+		return writeState(tx, current, state)
+	})
+}
+
+// Watch never delivers a state: the database is locked by this process, so
+// there are no outside changes to watch for
+func (s *BoltStore) Watch(ctx context.Context) <-chan *State {
+	return unwatched(ctx)
+}
+
+// readState reads the state in tx, or the default state if none was saved
+func readState(tx *bolt.Tx) (*State, error) {
+	meta := tx.Bucket(metaBucket)
+	state := &State{
+		EndpointConfigs: make(map[string]EndpointConfig),
+		Version:         1,
+	}
+
+	if data := meta.Get(versionKey); data != nil {
+		if err := json.Unmarshal(data, &state.Version); err != nil {
+			return nil, fmt.Errorf("corrupt state version: %w", err)
+		}
+		if state.Version != 1 {
+			return nil, fmt.Errorf("unsupported state version %d", state.Version)
+		}
+	}
+	if data := meta.Get(lastResourceVersionKey); data != nil {
+		if err := json.Unmarshal(data, &state.LastResourceVersion); err != nil {
+			return nil, fmt.Errorf("corrupt resource version: %w", err)
+		}
+	}
+	if data := meta.Get(agentConfigKey); data != nil {
+		if err := json.Unmarshal(data, &state.AgentConfig); err != nil {
+			return nil, fmt.Errorf("corrupt agent config: %w", err)
+		}
+	}
+
+	err := tx.Bucket(endpointsBucket).ForEach(func(id, data []byte) error {
+		var config EndpointConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("corrupt endpoint config %s: %w", id, err)
+		}
+		state.EndpointConfigs[string(id)] = config
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// writeState writes the keys that differ between current and state
+func writeState(tx *bolt.Tx, current, state *State) error {
+	meta := tx.Bucket(metaBucket)
+	if err := putJSON(meta, versionKey, state.Version); err != nil {
+		return err
+	}
+	if err := putJSON(meta, lastResourceVersionKey, state.LastResourceVersion); err != nil {
+		return err
+	}
+	if err := putJSON(meta, agentConfigKey, state.AgentConfig); err != nil {
+		return err
+	}
+
+	endpoints := tx.Bucket(endpointsBucket)
+	for id, config := range state.EndpointConfigs {
+		if err := putJSON(endpoints, []byte(id), config); err != nil {
+			return err
+		}
+	}
+	for id := range current.EndpointConfigs {
+		if _, exists := state.EndpointConfigs[id]; !exists {
+			if err := endpoints.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// putJSON stores v under key unless the stored value is already identical
+func putJSON(bucket *bolt.Bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(bucket.Get(key), data) {
+		return nil
+	}
+	return bucket.Put(key, data)
+}
+
+// MigrateFileStore copies the state from the JSON state file at path into
+// dst. It only runs when dst is empty and the file exists, and renames the
+// file afterwards so that the migration happens once. A file that cannot be
+// parsed aborts the migration and is left in place. It reports whether a
+// migration took place.
+func MigrateFileStore(path string, dst *BoltStore, logger *slog.Logger) (bool, error) {
+	empty, err := dst.Empty()
+	if err != nil || !empty {
+		return false, err
+	}
+	// FileStore.Load would replace a corrupt file with the default state, so
+	// the file is parsed here instead
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	var state *State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if state == nil || state.Version != 1 {
+		return false, fmt.Errorf("failed to migrate %s: unsupported state version", path)
+	}
+	if err := dst.Save(state); err != nil {
+		return false, fmt.Errorf("failed to write migrated state: %w", err)
+	}
+	if err := os.Rename(path, path+".migrated"); err != nil {
+		return false, fmt.Errorf("failed to rename %s after migration: %w", path, err)
+	}
+
+	logger.Info("Migrated state file to database", "from", path, "endpoints", len(state.EndpointConfigs))
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBoltStore(t *testing.T, path string) *BoltStore {
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestBoltStore_LoadEmpty(t *testing.T) {
+	store := newTestBoltStore(t, filepath.Join(t.TempDir(), "state.db"))
+
+	empty, err := store.Empty()
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, &State{
+		EndpointConfigs: map[string]EndpointConfig{},
+		Version:         1,
+	}, state)
+}
+
+func TestBoltStore_UpdatePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	store := newTestBoltStore(t, path)
+
+	err := store.Update(func(state *State) error {
+		state.AgentConfig = AgentConfig{AuthToken: "test_token", ExpectedState: "online", ResourceVersion: state.NextResourceVersion()}
+		state.EndpointConfigs["web:80"] = EndpointConfig{ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "online"}
+		state.EndpointConfigs["db:5432"] = EndpointConfig{ID: "db:5432", ContainerID: "db", TargetPort: "5432", ExpectedState: "offline"}
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.Update(func(state *State) error {
+		delete(state.EndpointConfigs, "db:5432")
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	reopened := newTestBoltStore(t, path)
+	state, err := reopened.Load()
+	require.NoError(t, err)
+	assert.Equal(t, &State{
+		AgentConfig: AgentConfig{AuthToken: "test_token", ExpectedState: "online", ResourceVersion: 1},
+		EndpointConfigs: map[string]EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "online"},
+		},
+		Version:             1,
+		LastResourceVersion: 1,
+	}, state)
+}
+
+func TestBoltStore_FailedUpdateKeepsPreviousState(t *testing.T) {
+	store := newTestBoltStore(t, filepath.Join(t.TempDir(), "state.db"))
+
+	initial := &State{
+		AgentConfig:     AgentConfig{AuthToken: "test_token", ExpectedState: "offline"},
+		EndpointConfigs: map[string]EndpointConfig{"web:80": {ID: "web:80", ExpectedState: "offline"}},
+		Version:         1,
+	}
+	require.NoError(t, store.Save(initial))
+
+	updateErr := errors.New("validation failed")
+	err := store.Update(func(state *State) error {
+		state.AgentConfig.ExpectedState = "online"
+		delete(state.EndpointConfigs, "web:80")
+		return updateErr
+	})
+	assert.ErrorIs(t, err, updateErr)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, initial, state)
+}
+
+func TestMigrateFileStore(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "state.json")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	fileState := &State{
+		AgentConfig: AgentConfig{AuthToken: "test_token", ExpectedState: "online", ResourceVersion: 3},
+		EndpointConfigs: map[string]EndpointConfig{
+			"web:80": {ID: "web:80", ContainerID: "web", TargetPort: "80", ExpectedState: "online", ResourceVersion: 2},
+		},
+		Version:             1,
+		LastResourceVersion: 3,
+	}
+	require.NoError(t, NewFileStore(jsonPath).Save(fileState))
+
+	store := newTestBoltStore(t, filepath.Join(dir, "state.db"))
+	migrated, err := MigrateFileStore(jsonPath, store, logger)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, fileState, state)
+
+	// The JSON file is kept aside so the migration never runs twice
+	_, err = os.Stat(jsonPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(jsonPath + ".migrated")
+	assert.NoError(t, err)
+
+	require.NoError(t, NewFileStore(jsonPath).Save(&State{Version: 1}))
+	migrated, err = MigrateFileStore(jsonPath, store, logger)
+	require.NoError(t, err)
+	assert.False(t, migrated, "a database with state must not be overwritten")
+
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, fileState, state)
+}
+
+func TestMigrateFileStore_CorruptStateFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "state.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"agentConfig": {`), 0600))
+	store := newTestBoltStore(t, filepath.Join(dir, "state.db"))
+
+	migrated, err := MigrateFileStore(jsonPath, store, slog.Default())
+	assert.Error(t, err)
+	assert.False(t, migrated)
+
+	// Neither the file nor the database is touched
+	data, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"agentConfig": {`, string(data))
+	empty, err := store.Empty()
+	require.NoError(t, err)
+	assert.True(t, empty)
+}
+
+func TestMigrateFileStore_NoStateFile(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestBoltStore(t, filepath.Join(dir, "state.db"))
+
+	migrated, err := MigrateFileStore(filepath.Join(dir, "state.json"), store, slog.Default())
+	require.NoError(t, err)
+	assert.False(t, migrated)
+}
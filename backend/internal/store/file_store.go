@@ -1,19 +1,25 @@
 package store
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 type FileStore struct {
-	path   string
-	logger *slog.Logger
-	mu     sync.RWMutex
+	path    string
+	logger  *slog.Logger
+	mu      sync.RWMutex
+	written []byte // the last state this store wrote, guarded by mu
 }
 
 func NewFileStore(path string) *FileStore {
@@ -32,6 +38,11 @@ func NewFileStoreWithLogger(path string, logger *slog.Logger) *FileStore {
 	}
 }
 
+// Path returns the path of the state file
+func (s *FileStore) Path() string {
+	return s.path
+}
+
 func (s *FileStore) Load() (*State, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -135,5 +146,107 @@ func (s *FileStore) saveUnsafe(state *State) error {
 	}
 
 	// Atomic rename
-	return os.Rename(tempPath, s.path)
+	if err := os.Rename(tempPath, s.path); err != nil {
+		return err
+	}
+	s.written = data
+	return nil
+}
+
+// Watch watches the state file with inotify, so that edits made by other
+// processes are delivered. This store's own writes are not: the process
+// making them converges afterwards. Files that fail to parse, such as a
+// partially written edit, are skipped until they become valid.
+func (s *FileStore) Watch(ctx context.Context) <-chan *State {
+	ch := make(chan *State, 1)
+
+	watcher, err := s.newWatcher()
+	if err != nil {
+		s.logger.Warn("Failed to watch state file, external edits will only be picked up by polling",
+			"path", s.path, "error", err)
+		go func() {
+			<-ctx.Done()
+			close(ch)
+		}()
+		return ch
+	}
+
+	go func() {
+		defer close(ch)
+		defer watcher.Close()
+
+		var last []byte
+		name := filepath.Base(s.path)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				s.logger.Warn("State file watch error", "path", s.path, "error", err)
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != name || !event.Has(fsnotify.Create|fsnotify.Write) {
+					continue
+				}
+				data, state, err := s.readForWatch()
+				if err != nil || bytes.Equal(data, last) {
+					continue
+				}
+				last = data
+				if state != nil {
+					sendLatest(ch, state)
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// newWatcher watches the directory of the state file, because writes replace
+// the file and a watch on the file itself would be lost
+func (s *FileStore) newWatcher() (*fsnotify.Watcher, error) {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// readForWatch reads and parses the state file without resetting it when it
+// is invalid. The state is nil if the file holds this store's last write.
+func (s *FileStore) readForWatch() ([]byte, *State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bytes.Equal(data, s.written) {
+		return data, nil, nil
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil, err
+	}
+	if state.Version != 1 {
+		return nil, nil, fmt.Errorf("unsupported state version %d", state.Version)
+	}
+	if state.EndpointConfigs == nil {
+		state.EndpointConfigs = make(map[string]EndpointConfig)
+	}
+	return data, &state, nil
 }
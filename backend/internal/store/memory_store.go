@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
 )
@@ -70,6 +71,16 @@ func (m *MemoryStore) Update(fn func(*State) error) error {
 	return nil
 }
 
+// Watch never delivers a state: the store is only written by this process
+func (m *MemoryStore) Watch(ctx context.Context) <-chan *State {
+	return unwatched(ctx)
+}
+
+// DeepCopy returns a copy of s sharing no memory with it
+func (s *State) DeepCopy() (*State, error) {
+	return deepCopyState(s)
+}
+
 // deepCopyState creates a deep copy of a State using JSON marshaling/unmarshaling
 func deepCopyState(state *State) (*State, error) {
 	data, err := json.Marshal(state)
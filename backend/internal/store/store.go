@@ -1,17 +1,26 @@
 package store
 
+import (
+	"context"
+	"errors"
+)
+
 // AgentConfig represents the desired agent configuration
 type AgentConfig struct {
 	AuthToken     string `json:"authToken"`
 	ConnectURL    string `json:"connectURL,omitempty"`
 	ExpectedState string `json:"expectedState"` // "online" | "offline"
+
+	// ResourceVersion changes on every modification, for optimistic concurrency
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
 }
 
 // EndpointConfig represents the desired endpoint configuration
 type EndpointConfig struct {
-	ID             string `json:"id"` // containerID:targetPort
+	ID             string `json:"id"` // containerID:targetPort, upstream:host:port or router:host
 	ContainerID    string `json:"containerId"`
 	TargetPort     string `json:"targetPort"`
+	Upstream       string `json:"upstream,omitempty"` // upstream URL, for endpoints not attached to a container
 	URL            string `json:"url,omitempty"`
 	Binding        string `json:"binding,omitempty"`
 	PoolingEnabled bool   `json:"poolingEnabled"`
@@ -20,6 +29,42 @@ type EndpointConfig struct {
 	Metadata       string `json:"metadata,omitempty"`
 	ExpectedState  string `json:"expectedState"`         // "online" | "offline"
 	LastStarted    string `json:"lastStarted,omitempty"` // when endpoint was last started
+
+	// TCP and TLS endpoints only
+	ProxyProtocol  string `json:"proxyProtocol,omitempty"`  // "v1" | "v2": PROXY protocol header sent to the upstream
+	TLSTermination string `json:"tlsTermination,omitempty"` // "passthrough" (default) | "edge"
+
+	// HTTP endpoints only
+	ForwardedFor string `json:"forwardedFor,omitempty"` // X-Forwarded-For: "append" (default) | "replace" | "remove"
+	RealIP       bool   `json:"realIP,omitempty"`       // set X-Real-IP to the client's address
+	HostHeader   string `json:"hostHeader,omitempty"`   // "rewrite" to the upstream's address, or a host; the client's by default
+
+	// Routes send requests matching a path prefix to other ports of the
+	// container, in order. Other requests go to TargetPort.
+	Routes []Route `json:"routes,omitempty"`
+
+	// Router makes the endpoint a router: it has no target of its own and
+	// forwards each request to the internal endpoint of the first matching
+	// rule
+	Router []RouterRule `json:"router,omitempty"`
+
+	// ResourceVersion changes on every modification, for optimistic concurrency
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
+}
+
+// Route sends the requests of an HTTP endpoint whose path starts with Path to
+// another port of the endpoint's container
+type Route struct {
+	Path       string `json:"path"` // e.g. "/api/" or "/api/*"
+	TargetPort string `json:"targetPort"`
+}
+
+// RouterRule forwards the requests of a router matching Host and Path to an
+// internal endpoint. Empty fields match every request.
+type RouterRule struct {
+	Host     string `json:"host,omitempty"` // e.g. "api.example.com"
+	Path     string `json:"path,omitempty"` // e.g. "/api/" or "/api/*"
+	Endpoint string `json:"endpoint"`       // ID of an internal endpoint
 }
 
 // State is the root persistent state structure
@@ -27,6 +72,32 @@ type State struct {
 	AgentConfig     AgentConfig               `json:"agentConfig"`
 	EndpointConfigs map[string]EndpointConfig `json:"endpointConfigs"`
 	Version         int                       `json:"version"`
+
+	// LastResourceVersion is the most recently assigned resource version.
+	// Versions are drawn from this single counter so that a deleted and
+	// recreated endpoint never reuses an old version.
+	LastResourceVersion int64 `json:"lastResourceVersion,omitempty"`
+}
+
+// NextResourceVersion allocates a new resource version
+func (s *State) NextResourceVersion() int64 {
+	s.LastResourceVersion++
+	return s.LastResourceVersion
+}
+
+// ErrReadOnly is returned by Save and Update on stores whose state is owned
+// by something other than the API
+var ErrReadOnly = errors.New("state is read-only")
+
+// FileBacked is implemented by stores that keep the state in a file
+type FileBacked interface {
+	Path() string
+}
+
+// ReadOnlyStore is implemented by stores whose Save and Update always return
+// ErrReadOnly
+type ReadOnlyStore interface {
+	ReadOnly() bool
 }
 
 // Store provides atomic persistence operations
@@ -34,4 +105,11 @@ type Store interface {
 	Load() (*State, error)
 	Save(*State) error
 	Update(func(*State) error) error
+
+	// Watch returns a channel that receives the new state whenever it is
+	// changed outside this process, where the store supports it. Writes made
+	// through the store are not delivered, because the writer converges
+	// after them. Changes that arrive faster than they are received are
+	// coalesced into the latest state. The channel is closed when ctx is done.
+	Watch(ctx context.Context) <-chan *State
 }
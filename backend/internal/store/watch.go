@@ -0,0 +1,31 @@
+package store
+
+import "context"
+
+// unwatched returns a channel that is closed when ctx is done without ever
+// receiving a state, for stores that only this process writes to. The writer
+// converges after each write, so notifying it would converge twice.
+func unwatched(ctx context.Context) <-chan *State {
+	ch := make(chan *State)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
+
+// sendLatest sends state on a buffered channel of size one without blocking,
+// dropping a pending older state if necessary
+func sendLatest(ch chan *State, state *State) {
+	for {
+		select {
+		case ch <- state:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive waits for the next state from ch
+func receive(t *testing.T, ch <-chan *State) *State {
+	t.Helper()
+	select {
+	case state, ok := <-ch:
+		require.True(t, ok, "watch channel closed unexpectedly")
+		return state
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for state change")
+		return nil
+	}
+}
+
+func TestFileStore_WatchExternalEdits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewFileStore(path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := store.Watch(ctx)
+
+	// A partially written file is skipped rather than reset
+	require.NoError(t, os.WriteFile(path, []byte(`{"agentConfig": {`), 0600))
+	require.NoError(t, os.WriteFile(path, []byte(`{"agentConfig":{"authToken":"first","expectedState":"online"},"version":1}`), 0600))
+	state := receive(t, changes)
+	assert.Equal(t, "first", state.AgentConfig.AuthToken)
+	assert.NotNil(t, state.EndpointConfigs)
+
+	// A write through the store is not delivered, an edit after it is
+	require.NoError(t, store.Save(&State{
+		AgentConfig:     AgentConfig{AuthToken: "own", ExpectedState: "offline"},
+		EndpointConfigs: map[string]EndpointConfig{},
+		Version:         1,
+	}))
+	require.NoError(t, os.WriteFile(path, []byte(`{"agentConfig":{"authToken":"second","expectedState":"online"},"version":1}`), 0600))
+	assert.Equal(t, "second", receive(t, changes).AgentConfig.AuthToken)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestMemoryStore_Watch(t *testing.T) {
+	store := NewMemoryStore(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := store.Watch(ctx)
+
+	// Writes come from this process, which converges after them
+	require.NoError(t, store.Update(func(state *State) error {
+		state.AgentConfig.AuthToken = "first"
+		return nil
+	}))
+
+	cancel()
+	_, ok := <-changes
+	assert.False(t, ok, "channel is closed without a state when the context is done")
+}
+
+func TestBoltStore_Watch(t *testing.T) {
+	store := newTestBoltStore(t, filepath.Join(t.TempDir(), "state.db"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := store.Watch(ctx)
+
+	require.NoError(t, store.Update(func(state *State) error {
+		state.EndpointConfigs["web:80"] = EndpointConfig{ID: "web:80"}
+		return nil
+	}))
+
+	cancel()
+	_, ok := <-changes
+	assert.False(t, ok, "channel is closed without a state when the context is done")
+}
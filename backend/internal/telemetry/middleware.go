@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware traces every API request in a span named after its route, and
+// records its duration in the http.server.request.duration histogram
+func Middleware() echo.MiddlewareFunc {
+	tracer := otel.Tracer(InstrumentationName)
+	duration, _ := otel.Meter(InstrumentationName).Float64Histogram("http.server.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of API requests"),
+	)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			started := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			var httpErr *echo.HTTPError
+			if err != nil && !c.Response().Committed {
+				// Echo writes the response after the middleware returns
+				status = http.StatusInternalServerError
+				if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				}
+			}
+			if err != nil {
+				span.RecordError(err)
+			}
+			if status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+			duration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(route),
+				semconv.HTTPResponseStatusCode(status),
+			))
+			return err
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dialTimeout matches the timeout the ngrok SDK dials upstreams with
+const dialTimeout = 3 * time.Second
+
+// Dialer dials the upstream of an endpoint. It matches the ngrok SDK's
+// upstream Dialer.
+type Dialer interface {
+	Dial(network, address string) (net.Conn, error)
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Propagator continues the trace of every request forwarded to a plain HTTP
+// upstream. The connections ngrok forwards are served in process: each
+// request gets a span, a child of the trace context it arrived with, and is
+// proxied to the upstream carrying that span's context.
+type Propagator struct {
+	server   *http.Server
+	listener *pipeListener
+	tracer   trace.Tracer
+}
+
+// NewPropagator starts a propagator
+func NewPropagator() *Propagator {
+	p := &Propagator{
+		listener: newPipeListener(),
+		tracer:   otel.Tracer(InstrumentationName),
+	}
+	p.server = &http.Server{
+		Handler: http.HandlerFunc(p.serveHTTP),
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connKey{}, c.(*pipeConn))
+		},
+	}
+	go func() { _ = p.server.Serve(p.listener) }()
+	return p
+}
+
+// Close stops the propagator, closing the connections it serves
+func (p *Propagator) Close() error {
+	return p.server.Close()
+}
+
+// Dialer returns a dialer for the SDK to forward an endpoint's connections
+// through the propagator. Upstream connections are dialed with next, or
+// directly if it is nil.
+func (p *Propagator) Dialer(endpointID string, next Dialer) Dialer {
+	if next == nil {
+		next = &net.Dialer{Timeout: dialTimeout}
+	}
+	u := &upstream{endpointID: endpointID}
+	u.proxy = &httputil.ReverseProxy{
+		Rewrite: u.rewrite,
+		Transport: &http.Transport{
+			DialContext:        next.DialContext,
+			DisableCompression: true,
+			IdleConnTimeout:    90 * time.Second,
+			MaxIdleConns:       100,
+		},
+		// Streamed responses such as server-sent events are not buffered
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			span := trace.SpanFromContext(r.Context())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	return &propagatorDialer{propagator: p, upstream: u}
+}
+
+// connKey keys a served connection in its context
+type connKey struct{}
+
+// upstream is the upstream requests of one endpoint are proxied to
+type upstream struct {
+	endpointID string
+	proxy      *httputil.ReverseProxy
+}
+
+func (u *upstream) rewrite(r *httputil.ProxyRequest) {
+	r.Out.URL.Scheme = "http"
+	// Requests go to the address the SDK dialed for their connection
+	r.Out.URL.Host = r.In.Context().Value(connKey{}).(*pipeConn).address
+	r.Out.Host = r.In.Host
+	// The proxy strips the headers ngrok adds, which belong to the client
+	for _, header := range []string{"X-Forwarded-For", "X-Forwarded-Host", "X-Forwarded-Proto"} {
+		if values := r.In.Header.Values(header); len(values) > 0 {
+			r.Out.Header[header] = values
+		}
+	}
+	otel.GetTextMapPropagator().Inject(r.Out.Context(), propagation.HeaderCarrier(r.Out.Header))
+}
+
+func (p *Propagator) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	u := r.Context().Value(connKey{}).(*pipeConn).upstream
+
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := p.tracer.Start(ctx, "forward "+r.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("ngrok_ext.endpoint", u.endpointID),
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+		),
+	)
+	defer span.End()
+
+	sw := &statusWriter{ResponseWriter: w}
+	u.proxy.ServeHTTP(sw, r.WithContext(ctx))
+	span.SetAttributes(semconv.HTTPResponseStatusCode(sw.statusCode()))
+	if sw.statusCode() >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(sw.statusCode()))
+	}
+}
+
+// propagatorDialer hands the connections the SDK forwards to the propagator
+type propagatorDialer struct {
+	propagator *Propagator
+	upstream   *upstream
+}
+
+func (d *propagatorDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *propagatorDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	if err := d.propagator.listener.push(ctx, &pipeConn{Conn: server, upstream: d.upstream, address: address}); err != nil {
+		client.Close()
+		server.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// pipeConn is a forwarded connection served by the propagator
+type pipeConn struct {
+	net.Conn
+	upstream *upstream
+	address  string // the upstream address the SDK dialed
+}
+
+// pipeListener accepts the connections handed to the propagator
+type pipeListener struct {
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *pipeListener) push(ctx context.Context, c net.Conn) error {
+	select {
+	case l.conns <- c:
+		return nil
+	case <-l.closed:
+		return net.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+// statusWriter remembers the status code of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	// Interim responses precede the final one
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap lets http.ResponseController flush and hijack the response
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,119 @@
+// Package telemetry exports the backend's traces and metrics over OTLP and
+// propagates trace context to the upstreams of HTTP endpoints. Other packages
+// instrument themselves through the global OpenTelemetry API, which does
+// nothing until Setup installs providers.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// InstrumentationName names the tracers and meters of the backend
+const InstrumentationName = "github.com/ngrok/ngrok-docker-extension"
+
+// ServiceName is the service.name of exported telemetry
+const ServiceName = "ngrok-docker-extension"
+
+// Config configures the export of telemetry
+type Config struct {
+	// Endpoint is the base URL of an OTLP/HTTP collector, e.g.
+	// http://otel-collector:4318. Traces and metrics are sent to its
+	// /v1/traces and /v1/metrics paths.
+	Endpoint string
+	// Standard is set when the OTEL_EXPORTER_OTLP_* environment variables
+	// configure the exporters instead of Endpoint
+	Standard bool
+	// MetricInterval is how often metrics are exported
+	MetricInterval time.Duration
+	// ServiceVersion is the service.version of exported telemetry
+	ServiceVersion string
+	// PropagateTraces proxies the requests of plain HTTP endpoints in
+	// process to continue their traces to the upstream. It changes how
+	// requests are forwarded, so it is opt-in and needs export enabled.
+	PropagateTraces bool
+}
+
+// Enabled reports whether telemetry is exported at all
+func (c Config) Enabled() bool {
+	return c.Endpoint != "" || c.Standard
+}
+
+// ConfigFromEnv reads NGROK_EXT_OTLP_ENDPOINT, or the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT and OTEL_EXPORTER_OTLP_TRACES_ENDPOINT variables.
+// Telemetry is disabled when none of them is set. NGROK_EXT_TRACE_UPSTREAMS=true
+// enables trace propagation to upstreams.
+func ConfigFromEnv(getenv func(string) string, serviceVersion string) Config {
+	propagate, _ := strconv.ParseBool(getenv("NGROK_EXT_TRACE_UPSTREAMS"))
+	config := Config{
+		Endpoint:        strings.TrimSuffix(getenv("NGROK_EXT_OTLP_ENDPOINT"), "/"),
+		MetricInterval:  time.Minute,
+		ServiceVersion:  serviceVersion,
+		PropagateTraces: propagate,
+	}
+	if config.Endpoint == "" {
+		config.Standard = getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	}
+	return config
+}
+
+// Setup installs global tracer and meter providers exporting to the
+// configured collector, and the W3C trace context propagator. The returned
+// function flushes and stops the providers.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !config.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(config.ServiceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var traceOpts []otlptracehttp.Option
+	var metricOpts []otlpmetrichttp.Option
+	if config.Endpoint != "" {
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(config.Endpoint+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(config.Endpoint+"/v1/metrics"))
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, err
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(config.MetricInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
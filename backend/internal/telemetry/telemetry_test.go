@@ -0,0 +1,174 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording the spans ended in a test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	assert.False(t, ConfigFromEnv(getenv, "1.0.0").Enabled())
+
+	env["OTEL_EXPORTER_OTLP_ENDPOINT"] = "http://collector:4318"
+	config := ConfigFromEnv(getenv, "1.0.0")
+	assert.True(t, config.Enabled())
+	assert.True(t, config.Standard)
+	assert.False(t, config.PropagateTraces)
+
+	env["NGROK_EXT_OTLP_ENDPOINT"] = "http://otel:4318/"
+	config = ConfigFromEnv(getenv, "1.0.0")
+	assert.Equal(t, "http://otel:4318", config.Endpoint)
+	assert.False(t, config.Standard)
+
+	env["NGROK_EXT_TRACE_UPSTREAMS"] = "true"
+	assert.True(t, ConfigFromEnv(getenv, "1.0.0").PropagateTraces)
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/endpoints/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/endpoints/web:80", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /endpoints/:id", spans[0].Name())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, int64(http.StatusNoContent), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+}
+
+func TestPropagator(t *testing.T) {
+	recorder := recordSpans(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s|%s", r.Host, r.Header.Get("X-Forwarded-For"), r.Header.Get("traceparent"))
+	}))
+	defer upstream.Close()
+
+	propagator := NewPropagator()
+	defer propagator.Close()
+
+	// The SDK dials the upstream, then copies the client's bytes over
+	conn, err := propagator.Dialer("web:80", nil).DialContext(context.Background(), "tcp", upstream.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "GET /checkout HTTP/1.1\r\nHost: shop.ngrok.app\r\nX-Forwarded-For: 203.0.113.7\r\n"+
+		"traceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01\r\n\r\n")
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// The span ends once the response is written
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	span := recorder.Ended()[0]
+	assert.Equal(t, "forward GET", span.Name())
+	assert.Equal(t, "web:80", spanAttribute(span, "ngrok_ext.endpoint").AsString())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.SpanContext().TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", span.Parent().SpanID().String())
+
+	// The upstream continues the trace from the forward's span, and still
+	// sees the client's host and address
+	traceparent := fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	assert.Equal(t, "shop.ngrok.app|203.0.113.7|"+traceparent, string(body))
+}
+
+func TestPropagatorUnreachableUpstream(t *testing.T) {
+	recordSpans(t)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	address := closed.Listener.Addr().String()
+	closed.Close()
+
+	propagator := NewPropagator()
+	defer propagator.Close()
+
+	conn, err := propagator.Dialer("web:80", nil).Dial("tcp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: web\r\n\r\n")
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func TestPropagatorConnectionsKeepTheirAddress(t *testing.T) {
+	recordSpans(t)
+
+	upstreams := make([]*httptest.Server, 2)
+	for i := range upstreams {
+		upstreams[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "upstream %d", i)
+		}))
+		defer upstreams[i].Close()
+	}
+
+	propagator := NewPropagator()
+	defer propagator.Close()
+
+	// Both connections are dialed before either sends a request
+	dialer := propagator.Dialer("web:80", nil)
+	conns := make([]net.Conn, 2)
+	for i, upstream := range upstreams {
+		conn, err := dialer.Dial("tcp", upstream.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		conns[i] = conn
+	}
+
+	for i, conn := range conns {
+		_, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: web\r\n\r\n")
+		require.NoError(t, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("upstream %d", i), string(body))
+	}
+}
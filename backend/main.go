@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"flag"
-	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
+
+	"github.com/ngrok/ngrok-docker-extension/internal/logging"
 )
 
 func main() {
@@ -13,11 +15,21 @@ func main() {
 	flag.StringVar(&socketPath, "socket", "/run/guest/ext.sock", "Unix domain socket to listen on")
 	flag.Parse()
 
-	// Setup structured logger to output to stdout
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	// Logs are written to stdout and a rotating file in the state directory,
+	// at levels that can be changed at runtime with PUT /logging
+	logs := logging.New(filepath.Join(stateDir(os.Getenv), "backend.log"), os.Stdout)
+	logger := logs.Logger("")
+	config, err := loggingConfig(os.Getenv)
+	if err == nil {
+		err = logs.SetConfig(config)
+	}
+	if err != nil {
+		logger.Error("Invalid log levels", "error", err)
+		os.Exit(1)
+	}
 
 	// Create the ngrok extension
-	ext, err := newNgrokExtension(socketPath, logger)
+	ext, err := newNgrokExtension(socketPath, logs)
 	if err != nil {
 		logger.Error("Failed to initialize ngrok extension", "error", err)
 		os.Exit(1)
@@ -1,6 +1,6 @@
 import React from 'react';
 import { Box, Tooltip } from '@mui/material';
-import { SettingsOutlined, MenuBookOutlined } from '@mui/icons-material';
+import { SettingsOutlined, MenuBookOutlined, ArticleOutlined } from '@mui/icons-material';
 import { AgentStatus } from '../types/api';
 import { createDockerDesktopClient } from '@docker/extension-api-client';
 import { SquareIconButton, StatusChip, IconMedium } from './styled';
@@ -11,13 +11,15 @@ interface AppHeaderProps {
     expectedState: "online" | "offline";
     onToggleAgentState: (expectedState: "online" | "offline") => void;
     onSettingsClick?: () => void;
+    onLogsClick?: () => void;
 }
 
 export const AppHeader: React.FC<AppHeaderProps> = ({
     status,
     expectedState,
     onToggleAgentState,
-    onSettingsClick
+    onSettingsClick,
+    onLogsClick
 }) => {
     const ddClient = createDockerDesktopClient();
 
@@ -77,6 +79,17 @@ export const AppHeader: React.FC<AppHeaderProps> = ({
                     </SquareIconButton>
                 </Tooltip>
 
+                {/* Logs button */}
+                {onLogsClick && (
+                    <Tooltip title="Backend logs" arrow>
+                        <SquareIconButton onClick={onLogsClick}>
+                            <IconMedium>
+                                <ArticleOutlined />
+                            </IconMedium>
+                        </SquareIconButton>
+                    </Tooltip>
+                )}
+
                 {/* Settings button */}
                 <Tooltip title="Settings" arrow>
                     <SquareIconButton onClick={onSettingsClick}>
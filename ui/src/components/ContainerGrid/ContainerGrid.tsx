@@ -44,6 +44,7 @@ interface ContainerGridRow {
     errorMessage?: string;
     state?: string;
     isDeleted: boolean;
+    isUpstream: boolean; // endpoint targets an upstream URL or is a router, not a container
     expectedState?: "online" | "offline";
     hasEndpointConfig: boolean;
     isContainerRunning: boolean;
@@ -125,7 +126,27 @@ const ContainerGrid: React.FC = () => {
                     endpoint.id === `${c.ContainerId}:${c.Port.PublicPort}`
                 );
 
-                if (!matchingContainer) {
+                if (endpoint.targetType !== "container") {
+                    // Endpoints targeting an upstream URL are shown with the URL in place of
+                    // the container, and routers with their own URL
+                    const isRouter = endpoint.targetType === "router";
+                    const port = isRouter ? 0 : parseInt(endpoint.id.substring(endpoint.id.lastIndexOf(':') + 1));
+                    if (!includedContainerIds.has(endpoint.id)) {
+                        includedContainerIds.add(endpoint.id);
+                        result.push({
+                            id: endpoint.id,
+                            ContainerId: '',
+                            Name: (isRouter ? endpoint.url : endpoint.upstream) || endpoint.id,
+                            Image: isRouter ? '<router>' : '<upstream>',
+                            ImageId: '',
+                            Port: {
+                                PublicPort: port,
+                                PrivatePort: undefined,
+                                Type: 'tcp'
+                            }
+                        });
+                    }
+                } else if (!matchingContainer) {
                     // This endpoint doesn't have a matching container - check if container truly doesn't exist
                     const [containerId, portStr] = endpoint.id.split(':');
                     const port = parseInt(portStr);
@@ -209,6 +230,7 @@ const ContainerGrid: React.FC = () => {
         const hasError = Boolean(endpoint?.status.lastError && endpoint?.status.lastError.trim() !== '');
         const errorMessage = hasError ? endpoint?.status.lastError : undefined;
         const isDeleted = container.Name === '<deleted>' && container.Image === '<deleted>';
+        const isUpstream = endpoint !== undefined && endpoint.targetType !== "container";
         const expectedState = endpoint?.expectedState ?? "offline";
         
         // Check if container is actually running in Docker (vs just having endpoint online)
@@ -250,6 +272,7 @@ const ContainerGrid: React.FC = () => {
             errorMessage: errorMessage,
             state: endpoint?.status.state,
             isDeleted: isDeleted,
+            isUpstream: isUpstream,
             expectedState: expectedState,
             hasEndpointConfig: !!endpoint,
             isContainerRunning: isContainerRunning,
@@ -407,7 +430,7 @@ const ContainerGrid: React.FC = () => {
             headerName: 'Container',
             flex: 1,
             minWidth: isSmall ? 80 : 100,
-            renderCell: (params) => (
+            renderCell: (params) => params.row.isUpstream ? params.value : (
                 <ClickableContainerName
                     name={params.value}
                     containerId={params.row.containerId}
@@ -423,7 +446,7 @@ const ContainerGrid: React.FC = () => {
             headerName: 'Image',
             flex: isMobile ? 0 : 1.5,
             minWidth: isMobile ? 0 : 120,
-            renderCell: (params) => (
+            renderCell: (params) => params.row.isUpstream ? params.value : (
                 <ClickableImageName
                     image={params.value}
                     imageId={params.row.imageId}
@@ -512,7 +535,11 @@ const ContainerGrid: React.FC = () => {
             } else {
                 // For deleted containers, we need to find the endpoint info
                 const endpoint = getEndpointForContainer(moreMenuContainerId);
-                if (endpoint) {
+                const row = getFilteredContainers().find(c => c.id === moreMenuContainerId);
+                if (endpoint && endpoint.targetType !== "container" && row) {
+                    setCurrentContainer(row);
+                    setEditDialogOpen(true);
+                } else if (endpoint) {
                     // Create a synthetic container object for the edit dialog
                     const [containerId, portStr] = moreMenuContainerId.split(':');
                     const port = parseInt(portStr);
@@ -627,17 +654,26 @@ const ContainerGrid: React.FC = () => {
 
         // Create updated endpoint configuration
         const currentEndpoint = getEndpointForContainer(currentContainer.id);
+        const isUpstream = currentEndpoint !== undefined && currentEndpoint.targetType !== "container";
         const updatedConfig: EndpointConfig = {
             id: currentContainer.id,
-            containerId: currentContainer.ContainerId,
-            targetPort: currentContainer.Port.PublicPort.toString(),
+            containerId: isUpstream ? '' : currentContainer.ContainerId,
+            targetPort: isUpstream ? '' : currentContainer.Port.PublicPort.toString(),
+            upstream: currentEndpoint?.upstream,
             url: stepOne.url || '',
             binding: stepOne.binding,
             poolingEnabled: stepOne.additionalOptions.poolingEnabled,
             trafficPolicy: stepTwo.trafficPolicy,
             description: stepOne.additionalOptions.description,
             metadata: stepOne.additionalOptions.metadata,
-            expectedState: currentEndpoint?.expectedState || "offline"
+            expectedState: currentEndpoint?.expectedState || "offline",
+            proxyProtocol: currentEndpoint?.proxyProtocol,
+            tlsTermination: currentEndpoint?.tlsTermination,
+            forwardedFor: currentEndpoint?.forwardedFor,
+            realIP: currentEndpoint?.realIP,
+            hostHeader: currentEndpoint?.hostHeader,
+            routes: currentEndpoint?.routes,
+            router: currentEndpoint?.router
         };
 
         // Save updated configuration using new API (PUT handles restarting if configuration changed)
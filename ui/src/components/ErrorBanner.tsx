@@ -44,6 +44,9 @@ const ErrorBanner: React.FC = () => {
       >
         <AlertTitle>{getErrorTitle()}</AlertTitle>
         {getErrorMessage()}
+        {!hasRequestError && status.error?.remediation && (
+          <div>{status.error.remediation}</div>
+        )}
       </Alert>
     </Collapse>
   );
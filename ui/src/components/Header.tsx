@@ -1,4 +1,5 @@
 import SettingsDialog from "./SettingsDialog";
+import LogsDialog from "./LogsDialog";
 import { useNgrokContext } from "./NgrokContext";
 import { AppHeader } from "./AppHeader";
 import { useState } from "react";
@@ -7,6 +8,7 @@ import { SectionBoxMb3 } from "./styled";
 export function Header() {
   const { authIsSetup, agentStatus, agentConfig, toggleAgentState } = useNgrokContext();
   const [settingsOpen, setSettingsOpen] = useState(false);
+  const [logsOpen, setLogsOpen] = useState(false);
 
   const handleSettingsClick = () => {
     setSettingsOpen(true);
@@ -20,6 +22,7 @@ export function Header() {
           expectedState={agentConfig?.expectedState || 'offline'}
           onToggleAgentState={toggleAgentState}
           onSettingsClick={handleSettingsClick}
+          onLogsClick={() => setLogsOpen(true)}
         />
       </SectionBoxMb3>
       
@@ -30,6 +33,9 @@ export function Header() {
           onClose={() => setSettingsOpen(false)} 
         />
       )}
+
+      {/* Backend logs dialog */}
+      <LogsDialog open={logsOpen} onClose={() => setLogsOpen(false)} />
     </>
   );
 }
@@ -0,0 +1,181 @@
+import {
+  Box,
+  Dialog,
+  DialogContent,
+  DialogTitle,
+  IconButton,
+  MenuItem,
+  TextField,
+  Typography,
+} from "@mui/material";
+import CloseIcon from '@mui/icons-material/Close';
+import { useEffect, useRef, useState } from "react";
+
+import * as api from '../services/api';
+import { LogEntry, LogLevel } from "../types/api";
+
+// The service client cannot stream, so new entries are polled for
+const POLL_INTERVAL_MS = 2000;
+// Entries kept on screen
+const MAX_ENTRIES = 1000;
+
+const LEVELS: LogLevel[] = ["debug", "info", "warn", "error"];
+
+const LEVEL_COLORS: Record<LogEntry["level"], string> = {
+  DEBUG: '#677285',
+  INFO: 'inherit',
+  WARN: '#B26B00',
+  ERROR: '#D32F2F',
+};
+
+interface LogsDialogProps {
+  open: boolean;
+  onClose: () => void;
+}
+
+function formatAttrs(attrs?: Record<string, unknown>): string {
+  if (!attrs) return "";
+  return Object.entries(attrs)
+    .map(([key, value]) => `${key}=${typeof value === "string" ? value : JSON.stringify(value)}`)
+    .join(" ");
+}
+
+export default function LogsDialog({ open, onClose }: LogsDialogProps) {
+  const [entries, setEntries] = useState<LogEntry[]>([]);
+  const [filterLevel, setFilterLevel] = useState<LogLevel>("info");
+  const [backendLevel, setBackendLevel] = useState<LogLevel | "">("");
+  const [error, setError] = useState<string | null>(null);
+  const lastTime = useRef<string | undefined>(undefined);
+  const bottom = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    if (!open) return;
+    api.getLogging()
+      .then((config) => setBackendLevel(config.level))
+      .catch(() => setBackendLevel(""));
+  }, [open]);
+
+  useEffect(() => {
+    if (!open) return;
+    setEntries([]);
+    lastTime.current = undefined;
+
+    let cancelled = false;
+    const poll = async () => {
+      try {
+        // since is inclusive, so the last entry shown is fetched again and
+        // skipped
+        const response = await api.getLogs({
+          since: lastTime.current,
+          level: filterLevel,
+          limit: lastTime.current ? undefined : 200,
+        });
+        if (cancelled) return;
+        const last = lastTime.current ? Date.parse(lastTime.current) : 0;
+        const newer = response.entries.filter((entry) => Date.parse(entry.time) > last);
+        if (newer.length > 0) {
+          lastTime.current = newer[newer.length - 1].time;
+          setEntries((current) => [...current, ...newer].slice(-MAX_ENTRIES));
+        }
+        setError(null);
+      } catch (e: any) {
+        if (!cancelled) setError(e?.message || "Failed to load logs");
+      }
+    };
+
+    poll();
+    const timer = setInterval(poll, POLL_INTERVAL_MS);
+    return () => {
+      cancelled = true;
+      clearInterval(timer);
+    };
+  }, [open, filterLevel]);
+
+  useEffect(() => {
+    bottom.current?.scrollIntoView({ block: "end" });
+  }, [entries]);
+
+  const handleBackendLevelChange = async (level: LogLevel) => {
+    try {
+      const config = await api.getLogging();
+      const updated = await api.putLogging({ ...config, level });
+      setBackendLevel(updated.level);
+    } catch (e: any) {
+      setError(e?.message || "Failed to change the log level");
+    }
+  };
+
+  return (
+    <Dialog
+      open={open}
+      onClose={onClose}
+      maxWidth="lg"
+      fullWidth
+      transitionDuration={0}
+    >
+      <DialogTitle sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', gap: 2 }}>
+        Backend Logs
+        <Box display="flex" alignItems="center" gap={2}>
+          <TextField
+            select
+            size="small"
+            label="Show"
+            value={filterLevel}
+            onChange={(e) => setFilterLevel(e.target.value as LogLevel)}
+            sx={{ minWidth: 120 }}
+          >
+            {LEVELS.map((level) => (
+              <MenuItem key={level} value={level}>{level} and above</MenuItem>
+            ))}
+          </TextField>
+          {backendLevel && (
+            <TextField
+              select
+              size="small"
+              label="Backend level"
+              value={backendLevel}
+              onChange={(e) => handleBackendLevelChange(e.target.value as LogLevel)}
+              sx={{ minWidth: 140 }}
+            >
+              {LEVELS.map((level) => (
+                <MenuItem key={level} value={level}>{level}</MenuItem>
+              ))}
+            </TextField>
+          )}
+          <IconButton onClick={onClose} sx={{ p: 0 }}>
+            <CloseIcon />
+          </IconButton>
+        </Box>
+      </DialogTitle>
+      <DialogContent>
+        {error && (
+          <Typography variant="body2" color="error" sx={{ mb: 1 }}>
+            {error}
+          </Typography>
+        )}
+        <Box
+          sx={{
+            fontFamily: 'Roboto Mono, monospace',
+            fontSize: 12,
+            lineHeight: '18px',
+            height: '60vh',
+            overflow: 'auto',
+            whiteSpace: 'pre-wrap',
+            wordBreak: 'break-all',
+          }}
+        >
+          {entries.length === 0 && !error && (
+            <Typography variant="body2" color="text.secondary">No log entries</Typography>
+          )}
+          {entries.map((entry, i) => (
+            <Box key={`${entry.time}-${i}`} sx={{ color: LEVEL_COLORS[entry.level] }}>
+              {new Date(entry.time).toLocaleTimeString()} {entry.level.padEnd(5)}{" "}
+              {entry.subsystem ? `[${entry.subsystem}] ` : ""}{entry.msg} {formatAttrs(entry.attrs)}
+            </Box>
+          ))}
+          <div ref={bottom} />
+        </Box>
+      </DialogContent>
+    </Dialog>
+  );
+}
@@ -6,6 +6,9 @@ import {
   EndpointResponse,
   DetectProtocolRequest,
   DetectProtocolResponse,
+  GetLogsResponse,
+  LoggingConfig,
+  LogLevel,
 } from "../types/api";
 
 const ddClient = createDockerDesktopClient();
@@ -46,3 +49,23 @@ export const detectProtocol = async (request: DetectProtocolRequest): Promise<De
   const result = await ddClient.extension.vm!.service!.post('/detect_protocol', request);
   return result as DetectProtocolResponse;
 };
+
+// Logging API
+export const getLogs = async (params: { since?: string; level?: LogLevel; limit?: number } = {}): Promise<GetLogsResponse> => {
+  const query = new URLSearchParams();
+  if (params.since) query.set('since', params.since);
+  if (params.level) query.set('level', params.level);
+  if (params.limit) query.set('limit', String(params.limit));
+  const result = await ddClient.extension.vm!.service!.get(`/logs?${query}`);
+  return result as GetLogsResponse;
+};
+
+export const getLogging = async (): Promise<LoggingConfig> => {
+  const result = await ddClient.extension.vm!.service!.get('/logging');
+  return result as LoggingConfig;
+};
+
+export const putLogging = async (config: LoggingConfig): Promise<LoggingConfig> => {
+  const result = await ddClient.extension.vm!.service!.put('/logging', config);
+  return result as LoggingConfig;
+};
@@ -5,6 +5,29 @@ export interface AgentConfig {
   authToken: string;
   connectURL?: string;
   expectedState: "online" | "offline";
+  resourceVersion?: number; // rejected with 409 if stale
+}
+
+export type ErrorCode =
+  | "auth_failed"
+  | "url_in_use"
+  | "policy_invalid"
+  | "upstream_unreachable"
+  | "quota_exceeded"
+  | "network_down"
+  | "secret_unresolved"
+  | "endpoint_missing"
+  | "operation_timeout"
+  | "agent_disconnected"
+  | "agent_not_connected"
+  | "unknown";
+
+// Classified form of lastError
+export interface StatusError {
+  code: ErrorCode;
+  message: string;
+  remediation?: string;
+  ngrokCode?: string; // e.g. ERR_NGROK_105
 }
 
 export interface AgentStatus {
@@ -12,6 +35,7 @@ export interface AgentStatus {
   connectedAt: string;
   latency?: number;
   lastError?: string;
+  error?: StatusError;
   requestError?: string;
 }
 
@@ -20,6 +44,7 @@ export interface AgentResponse {
   authToken: string;
   connectURL?: string;
   expectedState: "online" | "offline";
+  resourceVersion: number;
   
   // Runtime status
   status: AgentStatus;
@@ -27,29 +52,62 @@ export interface AgentResponse {
 
 // Endpoint API types
 export interface EndpointConfig {
-  id: string; // containerID:targetPort
+  id: string; // containerID:targetPort, upstream:host:port or router:host
   containerId: string;
   targetPort: string;
+  upstream?: string; // e.g. http://host.docker.internal:3000, instead of containerId and targetPort
   url?: string;
-  binding: "public" | "internal" | "kubernetes";
+  binding: "public" | "internal" | "kubernetes"; // internal requires a .internal url
   poolingEnabled: boolean;
   trafficPolicy?: string;
   description?: string;
   metadata?: string;
   expectedState: "online" | "offline";
+  proxyProtocol?: "v1" | "v2"; // tcp and tls endpoints
+  tlsTermination?: "passthrough" | "edge"; // tls endpoints; passthrough by default
+  forwardedFor?: "append" | "replace" | "remove"; // http endpoints; append by default
+  realIP?: boolean; // sets X-Real-IP to the client's address
+  hostHeader?: string; // "rewrite" to the upstream's address, or a host
+  routes?: EndpointRoute[]; // other ports of the container served under this URL
+  router?: RouterRule[]; // makes the endpoint a router, without containerId and targetPort
+  resourceVersion?: number; // rejected with 409 if stale
+}
+
+export interface EndpointRoute {
+  path: string; // e.g. /api/ or /api/*, matched as a prefix
+  targetPort: string;
+}
+
+export interface RouterRule {
+  host?: string; // e.g. api.example.com
+  path?: string; // e.g. /api/ or /api/*, matched as a prefix
+  endpoint: string; // ID of an internal endpoint
+}
+
+export interface RouteStatus {
+  path: string;
+  targetPort: string;
+  url: string; // internal endpoint serving the route
+  state: "online" | "offline" | "starting" | "failed";
+  lastError?: string;
+  error?: StatusError;
 }
 
 export interface EndpointStatus {
   url?: string;
   state: "online" | "offline";
   lastError?: string;
+  error?: StatusError;
+  routes?: RouteStatus[];
 }
 
 export interface EndpointResponse {
   // Configuration fields (from EndpointConfig)
   id: string;
+  targetType: "container" | "upstream" | "router";
   containerId: string;
   targetPort: string;
+  upstream?: string;
   url?: string;
   binding: "public" | "internal" | "kubernetes";
   poolingEnabled: boolean;
@@ -58,11 +116,82 @@ export interface EndpointResponse {
   metadata?: string;
   expectedState: "online" | "offline";
   lastStarted?: string;
+  proxyProtocol?: "v1" | "v2";
+  tlsTermination?: "passthrough" | "edge";
+  forwardedFor?: "append" | "replace" | "remove";
+  realIP?: boolean;
+  hostHeader?: string;
+  routes?: EndpointRoute[];
+  router?: RouterRule[];
+  resourceVersion: number;
   
   // Runtime status
   status: EndpointStatus;
 }
 
+// GET /topology types
+export interface TopologyNode {
+  id: string; // endpoint ID, route URL, container:<id> or upstream URL
+  type: "router" | "endpoint" | "route" | "container" | "upstream";
+  label: string;
+  url?: string;
+  binding?: string;
+  state?: string;
+}
+
+export interface TopologyEdge {
+  from: string;
+  to: string;
+  host?: string;
+  path?: string;
+  port?: string;
+  broken?: boolean; // the node forwarded to does not exist
+}
+
+export interface Topology {
+  nodes: TopologyNode[];
+  edges: TopologyEdge[];
+}
+
+// GET /endpoints/{id}/logs types
+export interface AccessLogEntry {
+  time: string; // when the request or connection started
+  endpoint: string;
+  protocol: "http" | "tcp"; // http entries are requests, tcp entries connections
+  remoteAddr?: string;
+  method?: string;
+  path?: string;
+  status?: number;
+  bytesIn: number;
+  bytesOut: number;
+  durationMs: number;
+  error?: string; // the upstream could not be reached
+}
+
+export interface GetEndpointLogsResponse {
+  entries: AccessLogEntry[];
+}
+
+// GET /logs and /logging types
+export type LogLevel = "debug" | "info" | "warn" | "error";
+
+export interface LoggingConfig {
+  level: LogLevel; // the default level
+  subsystems?: Record<string, LogLevel>; // manager, store, detectproto or http
+}
+
+export interface LogEntry {
+  time: string;
+  level: "DEBUG" | "INFO" | "WARN" | "ERROR";
+  subsystem?: string;
+  msg: string;
+  attrs?: Record<string, unknown>;
+}
+
+export interface GetLogsResponse {
+  entries: LogEntry[];
+}
+
 // Protocol detection types (unchanged)
 export interface DetectProtocolRequest {
   container_id: string;
@@ -74,4 +203,6 @@ export interface DetectProtocolResponse {
   http: boolean;
   https: boolean;
   tls: boolean;
+  error?: string;
+  detectedAt: string;
 }